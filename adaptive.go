@@ -0,0 +1,157 @@
+package clubhouse
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ResponseObserver is a Limiter that adjusts itself to the responses it
+// sees. The client passes it every response, including errors.
+type ResponseObserver interface {
+	Observe(resp *http.Response)
+}
+
+// DefaultRetryAfter is how long an AdaptiveLimiter holds requests back
+// after a 429 response that doesn't say how long to wait.
+var DefaultRetryAfter = 10 * time.Second
+
+// AdaptiveLimiter is a Limiter that paces requests by what the API says
+// about its rate limit, instead of at a fixed rate. It starts at a given
+// rate, and then:
+//
+//   - when a response has X-RateLimit-Remaining and X-RateLimit-Reset,
+//     spreads the remaining requests evenly until the reset, so a
+//     migration uses the whole budget without going over it;
+//   - when the budget is used up, or a response is 429 Too Many
+//     Requests, holds every request until the reset or Retry-After.
+//
+// The client retries 429 responses like 5xx ones when Retries is set.
+type AdaptiveLimiter struct {
+	// MinInterval is the least time between requests, however much
+	// budget is left.
+	MinInterval time.Duration
+
+	// Clock, if set, is used instead of RealClock.
+	Clock Clock
+
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	hold     time.Time
+}
+
+// NewAdaptiveLimiter makes an AdaptiveLimiter that starts at perSecond
+// requests a second.
+func NewAdaptiveLimiter(perSecond int) *AdaptiveLimiter {
+	l := &AdaptiveLimiter{}
+	if perSecond > 0 {
+		l.interval = time.Second / time.Duration(perSecond)
+	}
+	return l
+}
+
+func (l *AdaptiveLimiter) clock() Clock {
+	if l.Clock == nil {
+		return RealClock
+	}
+	return l.Clock
+}
+
+// Take blocks until the next request can be made.
+func (l *AdaptiveLimiter) Take() time.Time {
+	clock := l.clock()
+	l.mu.Lock()
+	now := clock.Now()
+	at := now
+	if l.next.After(at) {
+		at = l.next
+	}
+	if l.hold.After(at) {
+		at = l.hold
+	}
+	interval := l.interval
+	if interval < l.MinInterval {
+		interval = l.MinInterval
+	}
+	l.next = at.Add(interval)
+	l.mu.Unlock()
+
+	if wait := at.Sub(now); wait > 0 {
+		<-clock.After(wait)
+	}
+	return at
+}
+
+// Interval returns the current time between requests.
+func (l *AdaptiveLimiter) Interval() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.interval
+}
+
+// Observe adjusts the pace to the rate limit headers of resp.
+func (l *AdaptiveLimiter) Observe(resp *http.Response) {
+	now := l.clock().Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+		if !ok {
+			wait = DefaultRetryAfter
+		}
+		l.holdUntil(now.Add(wait))
+		return
+	}
+
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, ok := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now)
+	if !ok || !reset.After(now) {
+		return
+	}
+	if remaining <= 0 {
+		l.holdUntil(reset)
+		return
+	}
+	l.interval = reset.Sub(now) / time.Duration(remaining)
+}
+
+func (l *AdaptiveLimiter) holdUntil(t time.Time) {
+	if t.After(l.hold) {
+		l.hold = t
+	}
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number
+// of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.Sub(now), true
+	}
+	return 0, false
+}
+
+// parseRateLimitReset reads an X-RateLimit-Reset header. APIs send
+// either a Unix time or a number of seconds from now; anything big
+// enough to be a Unix time is taken as one.
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	secs, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if secs > 1000000000 {
+		return time.Unix(secs, 0), true
+	}
+	return now.Add(time.Duration(secs) * time.Second), true
+}
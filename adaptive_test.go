@@ -0,0 +1,132 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func rateLimitResponse(status int, header map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}}
+	for k, v := range header {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestAdaptiveLimiter(t *testing.T) {
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	l := NewAdaptiveLimiter(4)
+	l.Clock = clock
+
+	if at := l.Take(); !at.Equal(start) {
+		t.Errorf("expected the first request right away, got %s", at)
+	}
+	if l.Interval() != 250*time.Millisecond {
+		t.Errorf("expected the starting rate, got %s", l.Interval())
+	}
+
+	// 100 requests left in the next 10s: one every 100ms
+	l.Observe(rateLimitResponse(200, map[string]string{
+		"X-RateLimit-Remaining": "100",
+		"X-RateLimit-Reset":     strconv.FormatInt(start.Add(10*time.Second).Unix(), 10),
+	}))
+	if l.Interval() != 100*time.Millisecond {
+		t.Errorf("expected the budget to be spread out, got %s", l.Interval())
+	}
+	// relative resets work too
+	l.Observe(rateLimitResponse(200, map[string]string{"X-RateLimit-Remaining": "20", "X-RateLimit-Reset": "10"}))
+	if l.Interval() != 500*time.Millisecond {
+		t.Errorf("expected a relative reset, got %s", l.Interval())
+	}
+
+	l.Observe(rateLimitResponse(429, map[string]string{"Retry-After": "30"}))
+	taken := make(chan time.Time)
+	go func() { taken <- l.Take() }()
+	clock.BlockUntil(1)
+	clock.Advance(29 * time.Second)
+	select {
+	case <-taken:
+		t.Fatal("expected Take to wait for Retry-After")
+	default:
+	}
+	clock.Advance(time.Second)
+	if at := <-taken; !at.Equal(start.Add(30 * time.Second)) {
+		t.Errorf("expected to wait 30s, got %s", at.Sub(start))
+	}
+
+	l.Observe(rateLimitResponse(200, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "5"}))
+	go func() { taken <- l.Take() }()
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Second)
+	if at := <-taken; !at.Equal(start.Add(35 * time.Second)) {
+		t.Errorf("expected to wait for the reset, got %s", at.Sub(start))
+	}
+}
+
+func TestAdaptiveLimiterRetries429(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(429)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	clock := NewFakeClock(time.Now())
+	limiter := NewAdaptiveLimiter(0)
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: limiter, Retries: 1, Clock: clock}
+
+	done := make(chan error)
+	go func() {
+		_, err := c.ListEpics()
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(retryBackoff)
+	if err := <-done; err != nil || requests != 2 {
+		t.Errorf("expected the 429 to be retried, got %d requests, %v", requests, err)
+	}
+
+	// a 429 means the POST wasn't acted on, so it's safe to send again
+	requests = 0
+	go func() {
+		_, err := c.CreateStories([]CreateStoryParams{{Name: "x"}})
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(retryBackoff)
+	if err := <-done; err != nil || requests != 2 {
+		t.Errorf("expected the 429 POST to be retried, got %d requests, %v", requests, err)
+	}
+
+	c.Retries = 0
+	requests = 0
+	if _, err := c.ListEpics(); !hasStatus(err, 429) {
+		t.Errorf("expected ErrTooManyRequests, got %v", err)
+	}
+}
+
+func TestTooManyRequestsRetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(429)
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	_, err := c.ListEpics()
+	wait, ok := IsTooManyRequests(err)
+	if !ok || wait != 7*time.Second {
+		t.Errorf("expected a 7s Retry-After, got %s, %v", wait, err)
+	}
+	if _, ok := IsTooManyRequests(ErrClientRequest{Err: ErrResourceNotFound}); ok {
+		t.Error("expected a 404 not to be a 429")
+	}
+}
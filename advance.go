@@ -0,0 +1,180 @@
+package clubhouse
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// VCSEvent is something that happened in version control that can move
+// a story along its workflow.
+type VCSEvent string
+
+// Valid values for VCSEvent
+const (
+	VCSBranchCreated     VCSEvent = "branch_created"
+	VCSPullRequestOpened VCSEvent = "pull_request_opened"
+	VCSPullRequestMerged VCSEvent = "pull_request_merged"
+	VCSPullRequestClosed VCSEvent = "pull_request_closed"
+)
+
+// PullRequestEvent returns the event that best describes the current
+// state of a pull request.
+func PullRequestEvent(pr PullRequest) VCSEvent {
+	switch {
+	case pr.Merged:
+		return VCSPullRequestMerged
+	case pr.Closed:
+		return VCSPullRequestClosed
+	}
+	return VCSPullRequestOpened
+}
+
+// StateRules maps VCS events to the name of the workflow state a story
+// should be moved to, e.g.
+//
+//	StateRules{
+//		VCSPullRequestOpened: "In Review",
+//		VCSPullRequestMerged: "Ready for Deploy",
+//	}
+type StateRules map[VCSEvent]string
+
+// Advancement describes what an Advancer did, or would have done, to a
+// story.
+type Advancement struct {
+	StoryID int
+	Event   VCSEvent
+	FromID  int
+	ToID    int
+	ToName  string
+
+	// Applied is true if the story was updated. It's always false in
+	// dry-run mode.
+	Applied bool
+
+	// Skipped says why the story wasn't moved, if it wasn't.
+	Skipped string
+}
+
+// Advancer moves stories to configured workflow states in response to
+// VCS events. Stories are only ever moved forward in their workflow, so
+// a late "PR opened" event can't pull a deployed story back to review.
+type Advancer struct {
+	Client *Client
+
+	// Rules apply to every project without its own entry in
+	// ProjectRules.
+	Rules        StateRules
+	ProjectRules map[int]StateRules
+
+	// DryRun reports what would happen without updating any stories.
+	DryRun bool
+
+	mu        sync.Mutex
+	workflows []Workflow
+}
+
+// Advance applies the rule for event to a story.
+func (a *Advancer) Advance(storyID int, event VCSEvent) (*Advancement, error) {
+	result := &Advancement{StoryID: storyID, Event: event}
+
+	story, err := a.Client.GetStory(storyID)
+	if err != nil {
+		return nil, err
+	}
+	result.FromID = story.WorkflowStateID
+
+	rules, ok := a.ProjectRules[story.ProjectID]
+	if !ok {
+		rules = a.Rules
+	}
+	name, ok := rules[event]
+	if !ok {
+		result.Skipped = "no rule for event"
+		return result, nil
+	}
+	result.ToName = name
+
+	workflows, err := a.listWorkflows()
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := ResolveWorkflowState(workflows, story.WorkflowStateID, name)
+	if err != nil {
+		return nil, err
+	}
+	result.ToID = to.ID
+
+	switch {
+	case to.ID == from.ID:
+		result.Skipped = "already in state"
+		return result, nil
+	case to.Position < from.Position:
+		result.Skipped = fmt.Sprintf("already past %q", to.Name)
+		return result, nil
+	case a.DryRun:
+		result.Skipped = "dry run"
+		return result, nil
+	}
+
+	_, err = a.Client.UpdateStory(storyID, &UpdateStoryParams{WorkflowStateID: ID(to.ID)})
+	if err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// listWorkflows lists the workflows the first time it succeeds, and
+// returns that list from then on.
+func (a *Advancer) listWorkflows() ([]Workflow, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.workflows == nil {
+		workflows, err := a.Client.ListWorkflows()
+		if err != nil {
+			return nil, err
+		}
+		a.workflows = workflows
+	}
+	return a.workflows, nil
+}
+
+// AdvanceReferenced applies the rule for event to every story referenced
+// in text, which is usually a branch name or pull request title (see
+// ParseStoryIDs).
+func (a *Advancer) AdvanceReferenced(text string, event VCSEvent) ([]Advancement, error) {
+	results := []Advancement{}
+	for _, id := range ParseStoryIDs(text) {
+		result, err := a.Advance(id, event)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// ResolveWorkflowState finds the workflow that contains the state
+// currentID and returns that state along with the state in the same
+// workflow named name. Names are matched case-insensitively.
+func ResolveWorkflowState(workflows []Workflow, currentID int, name string) (from WorkflowState, to WorkflowState, err error) {
+	for _, w := range workflows {
+		found := false
+		for _, s := range w.States {
+			if s.ID == currentID {
+				from, found = s, true
+			}
+		}
+		if !found {
+			continue
+		}
+		for _, s := range w.States {
+			if strings.EqualFold(s.Name, name) {
+				return from, s, nil
+			}
+		}
+		return from, to, fmt.Errorf("ResolveWorkflowState: workflow %q has no state %q", w.Name, name)
+	}
+	return from, to, fmt.Errorf("ResolveWorkflowState: no workflow has state %d", currentID)
+}
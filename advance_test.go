@@ -0,0 +1,92 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveWorkflowState(t *testing.T) {
+	workflows := []Workflow{
+		{Name: "Ops", States: []WorkflowState{{ID: 1, Name: "Todo"}, {ID: 2, Name: "Done"}}},
+		{Name: "Dev", States: []WorkflowState{
+			{ID: 10, Name: "Unstarted", Position: 1},
+			{ID: 11, Name: "In Review", Position: 2},
+			{ID: 12, Name: "Done", Position: 3},
+		}},
+	}
+
+	from, to, err := ResolveWorkflowState(workflows, 10, "done")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from.ID != 10 || to.ID != 12 {
+		t.Errorf("expected 10 -> 12, got %d -> %d", from.ID, to.ID)
+	}
+	if _, _, err := ResolveWorkflowState(workflows, 1, "In Review"); err == nil {
+		t.Error("expected error for state missing from the story's workflow")
+	}
+	if _, _, err := ResolveWorkflowState(workflows, 99, "Done"); err == nil {
+		t.Error("expected error for unknown current state")
+	}
+}
+
+func TestPullRequestEvent(t *testing.T) {
+	tests := []struct {
+		pr     PullRequest
+		expect VCSEvent
+	}{
+		{PullRequest{}, VCSPullRequestOpened},
+		{PullRequest{Closed: true}, VCSPullRequestClosed},
+		{PullRequest{Closed: true, Merged: true}, VCSPullRequestMerged},
+	}
+	for _, test := range tests {
+		if got := PullRequestEvent(test.pr); got != test.expect {
+			t.Errorf("%+v: expected %s, got %s", test.pr, test.expect, got)
+		}
+	}
+}
+
+func TestAdvancerRetriesWorkflows(t *testing.T) {
+	workflowCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/stories/1":
+			w.Write([]byte(`{"id":1,"workflow_state_id":10}`))
+		case "/v2/workflows":
+			workflowCalls++
+			if workflowCalls == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`[{"name":"Dev","states":[{"id":10,"name":"Unstarted","position":1},{"id":11,"name":"In Review","position":2}]}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	a := &Advancer{Client: c, Rules: StateRules{VCSPullRequestOpened: "In Review"}, DryRun: true}
+
+	if _, err := a.Advance(1, VCSPullRequestOpened); err == nil {
+		t.Fatal("expected the failed workflow list to be returned")
+	}
+	result, err := a.Advance(1, VCSPullRequestOpened)
+	if err != nil {
+		t.Fatalf("expected the workflows to be listed again, got %v", err)
+	}
+	if result.FromID != 10 || result.ToID != 11 {
+		t.Errorf("expected 10 -> 11, got %+v", result)
+	}
+	if _, err := a.Advance(1, VCSPullRequestOpened); err != nil || workflowCalls != 2 {
+		t.Errorf("expected the workflows to be cached once listed, got %d calls, %v", workflowCalls, err)
+	}
+
+	story, err := c.GetStory(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if story.WorflowStateID != 10 {
+		t.Errorf("expected the deprecated field to be filled in, got %d", story.WorflowStateID)
+	}
+}
@@ -0,0 +1,97 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// AuditEvent is a normalized change to an entity, for feeding audit
+// pipelines and SIEMs. Each action in a History entry becomes one event.
+type AuditEvent struct {
+	// ID is unique per event: the History ID and the action's index.
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+
+	// Actor is the ID of the member who made the change, or of the
+	// webhook or integration if no member did; ActorType says which.
+	Actor     string `json:"actor"`
+	ActorType string `json:"actor_type"`
+
+	// Verb is the history action, e.g. "create", "update" or "delete".
+	Verb       string `json:"verb"`
+	EntityType string `json:"entity_type"`
+	EntityID   int    `json:"entity_id"`
+	EntityName string `json:"entity_name,omitempty"`
+
+	// Before and After hold the old and new value of each changed field,
+	// in the shape the API reports them.
+	Before map[string]json.RawMessage `json:"before,omitempty"`
+	After  map[string]json.RawMessage `json:"after,omitempty"`
+}
+
+// Valid values for AuditEvent.ActorType
+const (
+	AuditActorMember      = "member"
+	AuditActorWebhook     = "webhook"
+	AuditActorIntegration = "integration"
+)
+
+// AuditEvents converts history, e.g. from GetStoryHistory, to audit
+// events, in the same order.
+func AuditEvents(history []History) []AuditEvent {
+	events := []AuditEvent{}
+	for _, h := range history {
+		actor, actorType := h.MemberID, AuditActorMember
+		switch {
+		case h.MemberID != "":
+		case h.WebhookID != "":
+			actor, actorType = h.WebhookID, AuditActorWebhook
+		default:
+			actor, actorType = h.ExternalID, AuditActorIntegration
+		}
+		for i, a := range h.Actions {
+			event := AuditEvent{
+				ID:         fmt.Sprintf("%s/%d", h.ID, i),
+				Time:       h.ChangedAt,
+				Actor:      actor,
+				ActorType:  actorType,
+				Verb:       a.Action,
+				EntityType: a.EntityType,
+				EntityID:   a.ID,
+				EntityName: a.Name,
+			}
+			for field, change := range a.Changes {
+				if event.Before == nil {
+					event.Before = map[string]json.RawMessage{}
+					event.After = map[string]json.RawMessage{}
+				}
+				event.Before[field] = rawOrNull(change.Old)
+				event.After[field] = rawOrNull(change.New)
+			}
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// WriteAuditEvents writes events to w as JSON lines.
+func WriteAuditEvents(w io.Writer, events []AuditEvent) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawOrNull is needed because an empty RawMessage doesn't marshal.
+func rawOrNull(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage(`null`)
+	}
+	return raw
+}
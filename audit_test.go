@@ -0,0 +1,45 @@
+package clubhouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAuditEvents(t *testing.T) {
+	history := []History{}
+	err := json.Unmarshal([]byte(`[
+		{"id":"h1","changed_at":"2019-03-01T10:00:00Z","member_id":"u1","actions":[
+			{"action":"create","entity_type":"story","id":1,"name":"a"},
+			{"action":"update","entity_type":"story","id":1,"name":"a",
+			 "changes":{"estimate":{"old":1,"new":3},"deadline":{"new":"2019-04-01"}}}]},
+		{"id":"h2","changed_at":"2019-03-02T10:00:00Z","webhook_id":"w1","actions":[
+			{"action":"delete","entity_type":"task","id":7}]}
+	]`), &history)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := AuditEvents(history)
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", events)
+	}
+	if events[0].ID != "h1/0" || events[0].Verb != "create" || events[0].Before != nil {
+		t.Errorf("unexpected create event %+v", events[0])
+	}
+	if events[2].Actor != "w1" || events[2].ActorType != AuditActorWebhook || events[2].EntityType != "task" {
+		t.Errorf("unexpected delete event %+v", events[2])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAuditEvents(&buf, events[1:2]); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"h1/1","time":"2019-03-01T10:00:00Z","actor":"u1","actor_type":"member",` +
+		`"verb":"update","entity_type":"story","entity_id":1,"entity_name":"a",` +
+		`"before":{"deadline":null,"estimate":1},"after":{"deadline":"2019-04-01","estimate":3}}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
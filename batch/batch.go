@@ -0,0 +1,457 @@
+// Package batch runs a list of operations described in a YAML file, so
+// repeatable changes to a workspace can be written down, reviewed like
+// any other file, and run by people who don't write Go:
+//
+//	vars:
+//	  release: "2.4"
+//	operations:
+//	  - name: epic
+//	    create_epic:
+//	      name: "Release {{.Vars.release}}"
+//	  - name: move
+//	    update_stories:
+//	      query: 'label:"release-{{.Vars.release}}"'
+//	      epic_id: "{{.Results.epic.ID}}"
+//	  - comment:
+//	      epic_id: "{{.Results.epic.ID}}"
+//	      text: "Moved {{len .Results.move.IDs}} stories here."
+//
+// Every string in an operation is a text/template, executed just before
+// the operation runs with the file's vars and the results of the named
+// operations before it. A template can be used where a number is
+// expected, as long as it produces a whole number; elsewhere its output
+// is always a string, even if it looks like a number.
+//
+// Load reads a file, and Runner.Run runs it:
+//
+//	file, err := batch.Load("release.yaml")
+//	results, err := (&batch.Runner{Client: client, DryRun: true}).Run(file)
+//	batch.WriteReport(os.Stdout, results)
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/brianloveswords/clubhouse"
+	"gopkg.in/yaml.v2"
+)
+
+// File is a list of operations and the variables they can use.
+type File struct {
+	Vars       map[string]string `json:"vars" yaml:"vars"`
+	Operations []interface{}     `json:"operations" yaml:"operations"`
+}
+
+// Load reads a File from a YAML or JSON file. Files ending in .json are
+// read as JSON, everything else as YAML.
+func Load(path string) (*File, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("batch: could not read file, %s", err)
+	}
+	file := File{}
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(content, &file)
+	} else {
+		err = yaml.Unmarshal(content, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("batch: could not decode %s, %s", path, err)
+	}
+	return &file, nil
+}
+
+// Operation is one step of a File. Name is optional and lets later
+// operations refer to its result; exactly one of the other fields must
+// be set.
+type Operation struct {
+	Name          string         `json:"name" yaml:"name"`
+	CreateEpic    *CreateEpic    `json:"create_epic" yaml:"create_epic"`
+	CreateStory   *CreateStory   `json:"create_story" yaml:"create_story"`
+	UpdateStories *UpdateStories `json:"update_stories" yaml:"update_stories"`
+	Comment       *Comment       `json:"comment" yaml:"comment"`
+}
+
+// CreateEpic creates an epic. The result's ID is the new epic's.
+type CreateEpic struct {
+	Name        string          `json:"name" yaml:"name"`
+	MilestoneID int             `json:"milestone_id" yaml:"milestone_id"`
+	State       clubhouse.State `json:"state" yaml:"state"`
+	Labels      []string        `json:"labels" yaml:"labels"`
+	OwnerIDs    []string        `json:"owner_ids" yaml:"owner_ids"`
+}
+
+// CreateStory creates a story. The result's ID is the new story's.
+type CreateStory struct {
+	Name            string              `json:"name" yaml:"name"`
+	Description     string              `json:"description" yaml:"description"`
+	ProjectID       int                 `json:"project_id" yaml:"project_id"`
+	EpicID          int                 `json:"epic_id" yaml:"epic_id"`
+	StoryType       clubhouse.StoryType `json:"story_type" yaml:"story_type"`
+	WorkflowStateID int                 `json:"workflow_state_id" yaml:"workflow_state_id"`
+	Estimate        int                 `json:"estimate" yaml:"estimate"`
+	Labels          []string            `json:"labels" yaml:"labels"`
+	OwnerIDs        []string            `json:"owner_ids" yaml:"owner_ids"`
+}
+
+// UpdateStories applies the same update to every story matching Query,
+// a Clubhouse search, and every story in StoryIDs. The result's IDs are
+// the stories that matched.
+type UpdateStories struct {
+	Query    string `json:"query" yaml:"query"`
+	StoryIDs []int  `json:"story_ids" yaml:"story_ids"`
+
+	Archived        *bool    `json:"archived" yaml:"archived"`
+	EpicID          *int     `json:"epic_id" yaml:"epic_id"`
+	Estimate        *int     `json:"estimate" yaml:"estimate"`
+	ProjectID       *int     `json:"project_id" yaml:"project_id"`
+	WorkflowStateID *int     `json:"workflow_state_id" yaml:"workflow_state_id"`
+	LabelsAdd       []string `json:"labels_add" yaml:"labels_add"`
+	LabelsRemove    []string `json:"labels_remove" yaml:"labels_remove"`
+	OwnerIDsAdd     []string `json:"owner_ids_add" yaml:"owner_ids_add"`
+	OwnerIDsRemove  []string `json:"owner_ids_remove" yaml:"owner_ids_remove"`
+}
+
+// Comment comments on a story or an epic. The result's ID is the new
+// comment's.
+type Comment struct {
+	StoryID int    `json:"story_id" yaml:"story_id"`
+	EpicID  int    `json:"epic_id" yaml:"epic_id"`
+	Text    string `json:"text" yaml:"text"`
+}
+
+// Result is what an operation did, or in dry-run mode what it would
+// have done. ID and IDs are 0 and empty for things that weren't created
+// because of a dry run.
+type Result struct {
+	Index  int
+	Name   string
+	Op     string
+	ID     int
+	IDs    []int
+	DryRun bool
+	Err    error
+
+	// URL is the app link of the epic or story that was created or
+	// commented on, if the client already knows its workspace slug. See
+	// clubhouse.Client.CachedStoryURL.
+	URL string
+}
+
+// Runner runs Files against a workspace.
+type Runner struct {
+	Client *clubhouse.Client
+
+	// DryRun still searches for the stories operations would update, but
+	// doesn't create or change anything.
+	DryRun bool
+
+	// Vars are added to the file's vars, replacing any with the same
+	// name, e.g. to pass values in from the command line.
+	Vars map[string]string
+}
+
+type templateData struct {
+	Vars    map[string]string
+	Results map[string]Result
+}
+
+// Run runs the operations in file in order, stopping at the first one
+// that fails. The results of the operations that ran are returned
+// either way, with the failed one last.
+func (r *Runner) Run(file *File) ([]Result, error) {
+	data := templateData{Vars: map[string]string{}, Results: map[string]Result{}}
+	for k, v := range file.Vars {
+		data.Vars[k] = v
+	}
+	for k, v := range r.Vars {
+		data.Vars[k] = v
+	}
+
+	results := []Result{}
+	for i, raw := range file.Operations {
+		op, err := renderOperation(raw, data)
+		if err != nil {
+			err = fmt.Errorf("batch: operation %d: %s", i, err)
+			return append(results, Result{Index: i, Err: err}), err
+		}
+		result := Result{Index: i, Name: op.Name, DryRun: r.DryRun}
+		if err := r.run(op, &result); err != nil {
+			result.Err = fmt.Errorf("batch: operation %d (%s): %s", i, result.Op, err)
+			return append(results, result), result.Err
+		}
+		results = append(results, result)
+		if op.Name != "" {
+			data.Results[op.Name] = result
+		}
+	}
+	return results, nil
+}
+
+func (r *Runner) run(op *Operation, result *Result) error {
+	set := 0
+	for _, isSet := range []bool{op.CreateEpic != nil, op.CreateStory != nil, op.UpdateStories != nil, op.Comment != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("expected exactly one of create_epic, create_story, update_stories or comment, got %d", set)
+	}
+
+	switch {
+	case op.CreateEpic != nil:
+		result.Op = "create_epic"
+		if r.DryRun {
+			return nil
+		}
+		o := op.CreateEpic
+		epic, err := r.Client.CreateEpic(&clubhouse.CreateEpicParams{
+			Name:        o.Name,
+			MilestoneID: o.MilestoneID,
+			State:       o.State,
+			Labels:      labelParams(o.Labels),
+			OwnerIDs:    o.OwnerIDs,
+		})
+		if err != nil {
+			return err
+		}
+		result.ID = epic.ID
+		result.URL = r.Client.CachedEpicURL(epic.ID)
+	case op.CreateStory != nil:
+		result.Op = "create_story"
+		if r.DryRun {
+			return nil
+		}
+		o := op.CreateStory
+		story, err := r.Client.CreateStory(&clubhouse.CreateStoryParams{
+			Name:            o.Name,
+			Description:     o.Description,
+			ProjectID:       o.ProjectID,
+			EpicID:          o.EpicID,
+			StoryType:       o.StoryType,
+			WorkflowStateID: o.WorkflowStateID,
+			Estimate:        o.Estimate,
+			Labels:          labelParams(o.Labels),
+			OwnerIDs:        o.OwnerIDs,
+		})
+		if err != nil {
+			return err
+		}
+		result.ID = story.ID
+		result.URL = r.Client.CachedStoryURL(story.ID)
+	case op.UpdateStories != nil:
+		result.Op = "update_stories"
+		return r.updateStories(op.UpdateStories, result)
+	case op.Comment != nil:
+		result.Op = "comment"
+		o := op.Comment
+		if o.StoryID != 0 && o.EpicID != 0 {
+			return fmt.Errorf("expected only one of story_id or epic_id")
+		}
+		// in a dry run, the ID may refer to something that wasn't created
+		if r.DryRun {
+			return nil
+		}
+		if o.StoryID == 0 && o.EpicID == 0 {
+			return fmt.Errorf("expected story_id or epic_id")
+		}
+		params := &clubhouse.CreateCommentParams{Text: o.Text}
+		if o.StoryID != 0 {
+			comment, err := r.Client.CreateStoryComment(o.StoryID, params)
+			if err != nil {
+				return err
+			}
+			result.ID = comment.ID
+			result.URL = r.Client.CachedStoryURL(o.StoryID)
+		} else {
+			comment, err := r.Client.CreateEpicComment(o.EpicID, params)
+			if err != nil {
+				return err
+			}
+			result.ID = comment.ID
+			result.URL = r.Client.CachedEpicURL(o.EpicID)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) updateStories(o *UpdateStories, result *Result) error {
+	ids := append([]int{}, o.StoryIDs...)
+	if o.Query != "" {
+		stories, err := r.Client.SearchStoriesAll(&clubhouse.SearchParams{
+			PageSize: 25,
+			Query:    &clubhouse.SearchQuery{Raw: o.Query},
+		})
+		if err != nil {
+			return err
+		}
+		seen := map[int]bool{}
+		for _, id := range ids {
+			seen[id] = true
+		}
+		for _, s := range stories {
+			if !seen[s.ID] {
+				ids = append(ids, s.ID)
+			}
+		}
+	}
+	result.IDs = ids
+	if r.DryRun {
+		return nil
+	}
+
+	params := clubhouse.UpdateStoriesParams{
+		Archived:        o.Archived,
+		EpicID:          o.EpicID,
+		Estimate:        o.Estimate,
+		ProjectID:       o.ProjectID,
+		WorkflowStateID: o.WorkflowStateID,
+		LabelsAdd:       labelParams(o.LabelsAdd),
+		LabelsRemove:    labelParams(o.LabelsRemove),
+		OwnerIDsAdd:     o.OwnerIDsAdd,
+		OwnerIDsRemove:  o.OwnerIDsRemove,
+	}
+	for start := 0; start < len(ids); start += clubhouse.MaxBulkItems {
+		end := start + clubhouse.MaxBulkItems
+		if end > len(ids) {
+			end = len(ids)
+		}
+		params.StoryIDs = ids[start:end]
+		if _, err := r.Client.UpdateStories(&params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func labelParams(names []string) []clubhouse.CreateLabelParams {
+	if len(names) == 0 {
+		return nil
+	}
+	labels := make([]clubhouse.CreateLabelParams, len(names))
+	for i, name := range names {
+		labels[i] = clubhouse.CreateLabelParams{Name: name}
+	}
+	return labels
+}
+
+// renderOperation executes the templates in raw and decodes it into an
+// Operation.
+func renderOperation(raw interface{}, data templateData) (*Operation, error) {
+	rendered, err := render(raw, data, reflect.TypeOf(Operation{}))
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(rendered)
+	if err != nil {
+		return nil, err
+	}
+	op := Operation{}
+	if err := json.Unmarshal(content, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// render executes every string in v as a template. A template whose
+// destination, t, is a number is converted to one; everything else
+// stays a string. It also turns the map[interface{}]interface{} the
+// YAML decoder produces into something encoding/json can marshal.
+func render(v interface{}, data templateData, t reflect.Type) (interface{}, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch v := v.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		tmpl, err := template.New("").Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		out := buf.String()
+		if t != nil && isInt(t.Kind()) {
+			n, err := strconv.Atoi(strings.TrimSpace(out))
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a whole number", out)
+			}
+			return n, nil
+		}
+		return out, nil
+	case []interface{}:
+		var elem reflect.Type
+		if t != nil && t.Kind() == reflect.Slice {
+			elem = t.Elem()
+		}
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			rendered, err := render(item, data, elem)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for k, item := range v {
+			rendered, err := render(item, data, fieldType(t, k))
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rendered
+		}
+		return out, nil
+	case map[interface{}]interface{}:
+		out := map[string]interface{}{}
+		for k, item := range v {
+			key := fmt.Sprint(k)
+			rendered, err := render(item, data, fieldType(t, key))
+			if err != nil {
+				return nil, err
+			}
+			out[key] = rendered
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// fieldType is the type of the field of struct t that key decodes into,
+// or nil if there isn't one.
+func fieldType(t reflect.Type, key string) reflect.Type {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == key || (name == "" && strings.EqualFold(field.Name, key)) {
+			return field.Type
+		}
+	}
+	return nil
+}
+
+func isInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+// JSON is YAML too, and keeps the test independent of how the YAML
+// decoder represents maps.
+const releaseFile = `{
+  "vars": {"release": "2.4"},
+  "operations": [
+    {"name": "epic", "create_epic": {"name": "Release {{.Vars.release}}"}},
+    {"name": "move", "update_stories": {
+      "query": "label:release-{{.Vars.release}}",
+      "story_ids": [7],
+      "epic_id": "{{.Results.epic.ID}}"
+    }},
+    {"comment": {"epic_id": "{{.Results.epic.ID}}", "text": "Moved {{len .Results.move.IDs}} stories here."}}
+  ]
+}`
+
+func loadFile(t *testing.T, content string) *File {
+	dir, err := ioutil.TempDir("", "batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, "ops.yaml")
+	if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := Load(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestRun(t *testing.T) {
+	requests := []string{}
+	bodies := map[string]map[string]interface{}{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		requests = append(requests, key)
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies[key] = body
+		switch key {
+		case "POST /v2/epics":
+			w.Write([]byte(`{"id": 100}`))
+		case "GET /v2/search/stories":
+			w.Write([]byte(`{"data": [{"id": 7}, {"id": 8}]}`))
+		case "PUT /v2/stories/bulk":
+			w.Write([]byte(`[]`))
+		case "POST /v2/epics/100/comments":
+			w.Write([]byte(`{"id": 5}`))
+		case "GET /v2/member":
+			w.Write([]byte(`{"workspace2": {"url_slug": "acme"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client := &clubhouse.Client{AuthToken: "token", RootURL: srv.URL + "/", Limiter: clubhouse.RateLimiter(0)}
+	if _, err := client.WorkspaceSlug(); err != nil {
+		t.Fatal(err)
+	}
+	requests = requests[:0]
+
+	results, err := (&Runner{Client: client}).Run(loadFile(t, releaseFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"POST /v2/epics", "GET /v2/search/stories", "PUT /v2/stories/bulk", "POST /v2/epics/100/comments"}
+	if !reflect.DeepEqual(requests, expect) {
+		t.Fatalf("expected requests %v, got %v", expect, requests)
+	}
+	if name := bodies["POST /v2/epics"]["name"]; name != "Release 2.4" {
+		t.Errorf("expected templated epic name, got %v", name)
+	}
+	update := bodies["PUT /v2/stories/bulk"]
+	if update["epic_id"] != 100.0 || !reflect.DeepEqual(update["story_ids"], []interface{}{7.0, 8.0}) {
+		t.Errorf("unexpected bulk update %v", update)
+	}
+	if text := bodies["POST /v2/epics/100/comments"]["text"]; text != "Moved 2 stories here." {
+		t.Errorf("unexpected comment %v", text)
+	}
+
+	var buf bytes.Buffer
+	WriteReport(&buf, results)
+	report := buf.String()
+	for _, line := range []string{
+		"created 100  https://app.clubhouse.io/acme/epic/100",
+		"updated 2 stories",
+		"commented 5  https://app.clubhouse.io/acme/epic/100",
+	} {
+		if !strings.Contains(report, line) {
+			t.Errorf("expected report to contain %q, got:\n%s", line, report)
+		}
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	requests := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"data": [{"id": 8}]}`))
+	}))
+	defer srv.Close()
+	client := &clubhouse.Client{AuthToken: "token", RootURL: srv.URL + "/", Limiter: clubhouse.RateLimiter(0)}
+
+	runner := &Runner{Client: client, DryRun: true, Vars: map[string]string{"release": "2.5"}}
+	results, err := runner.Run(loadFile(t, releaseFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(requests, []string{"GET /v2/search/stories"}) {
+		t.Errorf("expected only the search to be sent, got %v", requests)
+	}
+	if len(results) != 3 || !reflect.DeepEqual(results[1].IDs, []int{7, 8}) {
+		t.Errorf("unexpected results %+v", results)
+	}
+	var buf bytes.Buffer
+	WriteReport(&buf, results)
+	if !strings.Contains(buf.String(), "would have updated 2 stories") {
+		t.Errorf("unexpected report:\n%s", buf.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"no op":        `{"operations": [{"name": "x"}]}`,
+		"two ops":      `{"operations": [{"comment": {"story_id": 1, "text": "a"}, "create_epic": {"name": "b"}}]}`,
+		"missing var":  `{"operations": [{"create_epic": {"name": "{{.Vars.nope}}"}}]}`,
+		"comment both": `{"operations": [{"comment": {"story_id": 1, "epic_id": 2, "text": "a"}}]}`,
+	} {
+		results, err := (&Runner{DryRun: true}).Run(loadFile(t, content))
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		if len(results) != 1 || results[0].Err == nil {
+			t.Errorf("%s: expected the failed operation in the results, got %+v", name, results)
+		}
+	}
+}
+
+func TestRenderByFieldType(t *testing.T) {
+	raw := map[string]interface{}{
+		"create_story": map[interface{}]interface{}{
+			"name":       "{{.Vars.release}}",
+			"labels":     []interface{}{"{{.Vars.release}}"},
+			"project_id": "{{.Vars.release}}",
+		},
+	}
+	data := templateData{Vars: map[string]string{"release": "2024"}}
+	op, err := renderOperation(raw, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := op.CreateStory
+	if got.Name != "2024" || !reflect.DeepEqual(got.Labels, []string{"2024"}) || got.ProjectID != 2024 {
+		t.Errorf("unexpected story %+v", got)
+	}
+
+	data.Vars["release"] = "2.4"
+	if _, err := renderOperation(raw, data); err == nil {
+		t.Error("expected an error for a project_id that isn't a number")
+	}
+}
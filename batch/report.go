@@ -0,0 +1,47 @@
+package batch
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteReport writes a line for each result saying what was done, with
+// a link when there is one, e.g.
+//
+//	0  epic  create_epic     created 1234  https://app.clubhouse.io/acme/epic/1234
+//	1        update_stories  updated 12 stories
+func WriteReport(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, r := range results {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s", r.Index, r.Name, r.Op, r.summary())
+		if r.URL != "" {
+			fmt.Fprintf(tw, "\t%s", r.URL)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+func (r Result) summary() string {
+	if r.Err != nil {
+		return "failed: " + r.Err.Error()
+	}
+	verb := "created"
+	switch r.Op {
+	case "update_stories":
+		verb = "updated"
+	case "comment":
+		verb = "commented"
+	}
+	if r.DryRun {
+		verb = "would have " + verb
+	}
+	switch {
+	case r.Op == "update_stories":
+		return fmt.Sprintf("%s %d stories", verb, len(r.IDs))
+	case r.DryRun:
+		return verb
+	}
+	return fmt.Sprintf("%s %d", verb, r.ID)
+}
@@ -0,0 +1,76 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ErrFieldNeverChanged is returned by BlameStoryField when the story's
+// history has no changes to the field.
+var ErrFieldNeverChanged = fmt.Errorf("clubhouse: field was never changed")
+
+// blameAliases maps friendly field names to the names used in story
+// history.
+var blameAliases = map[string]string{
+	"state":     "workflow_state_id",
+	"epic":      "epic_id",
+	"project":   "project_id",
+	"owners":    "owner_ids",
+	"type":      "story_type",
+	"requester": "requested_by_id",
+}
+
+// Blame describes the last change made to a field.
+type Blame struct {
+	Field     string
+	MemberID  string
+	ChangedAt time.Time
+	Old       json.RawMessage
+	New       json.RawMessage
+}
+
+// BlameStoryField walks a story's history and returns who last changed
+// field, and when. field is the API name of the field, e.g. "estimate"
+// or "deadline"; "state" is accepted as an alias for
+// "workflow_state_id".
+func (c *Client) BlameStoryField(storyID int, field string) (*Blame, error) {
+	if alias, ok := blameAliases[field]; ok {
+		field = alias
+	}
+	history, err := c.GetStoryHistory(storyID)
+	if err != nil {
+		return nil, err
+	}
+	if blame := blameField(history, storyID, field); blame != nil {
+		return blame, nil
+	}
+	return nil, ErrFieldNeverChanged
+}
+
+// blameField finds the most recent change to field on the story.
+func blameField(history []History, storyID int, field string) *Blame {
+	var last *Blame
+	for _, h := range history {
+		for _, a := range h.Actions {
+			if a.EntityType != "story" || a.ID != storyID {
+				continue
+			}
+			change, ok := a.Changes[field]
+			if !ok {
+				continue
+			}
+			if last != nil && h.ChangedAt.Before(last.ChangedAt) {
+				continue
+			}
+			last = &Blame{
+				Field:     field,
+				MemberID:  h.MemberID,
+				ChangedAt: h.ChangedAt,
+				Old:       change.Old,
+				New:       change.New,
+			}
+		}
+	}
+	return last
+}
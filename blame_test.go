@@ -0,0 +1,33 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBlameField(t *testing.T) {
+	history := []History{}
+	err := json.Unmarshal([]byte(`[
+		{"changed_at": "2018-04-01T00:00:00Z", "member_id": "a", "actions": [
+			{"entity_type": "story", "id": 1, "changes": {"estimate": {"old": null, "new": 1}}}
+		]},
+		{"changed_at": "2018-04-02T00:00:00Z", "member_id": "b", "actions": [
+			{"entity_type": "story", "id": 1, "changes": {"estimate": {"old": 1, "new": 3}}},
+			{"entity_type": "story", "id": 2, "changes": {"deadline": {"new": "2018-05-01"}}}
+		]}
+	]`), &history)
+	if err != nil {
+		t.Fatal("bad fixture", err)
+	}
+
+	blame := blameField(history, 1, "estimate")
+	if blame == nil {
+		t.Fatal("expected to find a change")
+	}
+	if blame.MemberID != "b" || string(blame.New) != "3" {
+		t.Errorf("wrong blame %+v", blame)
+	}
+	if blameField(history, 1, "deadline") != nil {
+		t.Error("deadline change belongs to another story")
+	}
+}
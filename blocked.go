@@ -0,0 +1,171 @@
+package clubhouse
+
+import (
+	"path"
+	"sort"
+)
+
+// Blocker is a story that is blocking another story.
+type Blocker struct {
+	ID        int
+	Name      string
+	Completed bool
+	Archived  bool
+	URL       string
+}
+
+// BlockedStory is an entry in a BlockedReport.
+type BlockedStory struct {
+	Story    StorySearch
+	Blockers []Blocker
+
+	// URL is the story's app link, if the client already knows its
+	// workspace slug (see CachedStoryURL). Blockers get one too.
+	URL string
+
+	// Stale is true when at least one of the blockers has already been
+	// completed (or archived), meaning the link is probably out of date.
+	Stale bool
+
+	// Chain is the length of the longest chain of open blockers behind
+	// this story. A story blocked by a story that isn't blocked itself
+	// has a chain of 1.
+	Chain int
+}
+
+// BlockedReport describes all of the currently blocked, unarchived
+// stories in the workspace.
+type BlockedReport struct {
+	Stories []BlockedStory
+}
+
+// StaleBlocks returns the stories that are blocked by at least one
+// story that's already completed.
+func (r *BlockedReport) StaleBlocks() []BlockedStory {
+	out := []BlockedStory{}
+	for _, s := range r.Stories {
+		if s.Stale {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// LongChains returns the stories with a chain of blockers at least min
+// long, longest first.
+func (r *BlockedReport) LongChains(min int) []BlockedStory {
+	out := []BlockedStory{}
+	for _, s := range r.Stories {
+		if s.Chain >= min {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Chain > out[j].Chain
+	})
+	return out
+}
+
+// BlockedReport finds every blocked, unarchived story, looks up the
+// state of each of their blockers, and works out how long the chains of
+// blockers are.
+func (c *Client) BlockedReport() (*BlockedReport, error) {
+	blocked, err := c.SearchStoriesAll(&SearchParams{
+		PageSize: 25,
+		Query: &SearchQuery{
+			IsBlocked:  true,
+			Inversions: SearchQueryInversions{IsArchived: true},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	g := blockerGraph{client: c, stories: map[int]*Story{}}
+	report := &BlockedReport{}
+	progress := c.trackProgress("BlockedReport", len(blocked))
+	for _, s := range blocked {
+		entry := BlockedStory{Story: s, URL: c.CachedStoryURL(s.ID)}
+		for _, id := range blockerIDs(s.ID, s.StoryLinks) {
+			b, err := g.story(id)
+			if err != nil {
+				return nil, err
+			}
+			entry.Blockers = append(entry.Blockers, Blocker{
+				ID:        b.ID,
+				Name:      b.Name,
+				Completed: b.Completed,
+				Archived:  b.Archived,
+				URL:       c.CachedStoryURL(b.ID),
+			})
+			if b.Completed || b.Archived {
+				entry.Stale = true
+			}
+		}
+		entry.Chain, err = g.chain(s.ID, s.StoryLinks, map[int]bool{})
+		if err != nil {
+			return nil, err
+		}
+		report.Stories = append(report.Stories, entry)
+		progress.step(1, path.Join("stories", itoa(s.ID)))
+	}
+	return report, nil
+}
+
+// blockerIDs returns the IDs of the stories blocking the story id.
+func blockerIDs(id int, links []TypedStoryLink) []int {
+	ids := []int{}
+	for _, l := range links {
+		if StoryVerb(l.Verb) == VerbBlocks && l.ObjectID == id {
+			ids = append(ids, l.SubjectID)
+		}
+	}
+	return ids
+}
+
+// blockerGraph walks blocking links, caching every story it fetches.
+type blockerGraph struct {
+	client  *Client
+	stories map[int]*Story
+}
+
+func (g *blockerGraph) story(id int) (*Story, error) {
+	if s, ok := g.stories[id]; ok {
+		return s, nil
+	}
+	s, err := g.client.GetStory(id)
+	if err != nil {
+		return nil, err
+	}
+	g.stories[id] = s
+	return s, nil
+}
+
+// chain computes the longest chain of open blockers behind a story.
+// seen guards against cycles.
+func (g *blockerGraph) chain(id int, links []TypedStoryLink, seen map[int]bool) (int, error) {
+	seen[id] = true
+	defer delete(seen, id)
+
+	longest := 0
+	for _, bid := range blockerIDs(id, links) {
+		if seen[bid] {
+			continue
+		}
+		b, err := g.story(bid)
+		if err != nil {
+			return 0, err
+		}
+		if b.Completed || b.Archived {
+			continue
+		}
+		n, err := g.chain(b.ID, b.StoryLinks, seen)
+		if err != nil {
+			return 0, err
+		}
+		if n+1 > longest {
+			longest = n + 1
+		}
+	}
+	return longest, nil
+}
@@ -0,0 +1,64 @@
+package clubhouse
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// blocks is a story link saying blocker blocks blocked.
+func blocks(blocker, blocked int) string {
+	return fmt.Sprintf(`{"verb":"blocks","subject_id":%d,"object_id":%d}`, blocker, blocked)
+}
+
+func TestBlockedReport(t *testing.T) {
+	stories := map[string]string{
+		// 2 and 3 block each other, and 2 blocks 1
+		"2": `{"id":2,"story_links":[` + blocks(2, 1) + `,` + blocks(3, 2) + `,` + blocks(2, 3) + `]}`,
+		"3": `{"id":3,"story_links":[` + blocks(2, 3) + `,` + blocks(3, 2) + `]}`,
+		"5": `{"id":5,"completed":true,"story_links":[` + blocks(5, 4) + `]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/search/stories" {
+			w.Write([]byte(`{"data":[
+				{"id":1,"story_links":[` + blocks(2, 1) + `]},
+				{"id":4,"story_links":[` + blocks(5, 4) + `]}],"total":2}`))
+			return
+		}
+		story, ok := stories[strings.TrimPrefix(r.URL.Path, "/v2/stories/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(story))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	report, err := c.BlockedReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Stories) != 2 {
+		t.Fatalf("expected 2 blocked stories, got %+v", report.Stories)
+	}
+	one, four := report.Stories[0], report.Stories[1]
+	if one.Chain != 2 || one.Stale || len(one.Blockers) != 1 || one.Blockers[0].ID != 2 {
+		t.Errorf("expected 1 to have an open chain of 2 despite the cycle, got %+v", one)
+	}
+	if four.Chain != 0 || !four.Stale || !four.Blockers[0].Completed {
+		t.Errorf("expected 4 to be stale with no open chain, got %+v", four)
+	}
+
+	if stale := report.StaleBlocks(); len(stale) != 1 || stale[0].Story.ID != 4 {
+		t.Errorf("expected only 4 to be stale, got %+v", stale)
+	}
+	if long := report.LongChains(2); len(long) != 1 || long[0].Story.ID != 1 {
+		t.Errorf("expected only 1 to have a long chain, got %+v", long)
+	}
+	if all := report.LongChains(0); len(all) != 2 || all[0].Story.ID != 1 {
+		t.Errorf("expected the longest chain first, got %+v", all)
+	}
+}
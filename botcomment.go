@@ -0,0 +1,56 @@
+package clubhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BotMarker returns the hidden marker UpsertBotComment appends to
+// comments. It's a Markdown link reference definition, which the
+// Clubhouse UI doesn't render.
+func BotMarker(marker string) string {
+	return fmt.Sprintf("[//]: # (bot:%s)", marker)
+}
+
+// UpsertBotComment makes sure a story has exactly one comment for
+// marker, with text as its content. If a comment carrying the marker
+// already exists it's updated, otherwise a new comment is created. This
+// lets CI integrations post status updates without leaving a wall of
+// duplicate comments.
+//
+// marker should identify the bot and the kind of comment, e.g.
+// "ci-build-status". It must not contain ")".
+func (c *Client) UpsertBotComment(storyID int, marker string, text string) (*Comment, error) {
+	if strings.Contains(marker, ")") {
+		return nil, fmt.Errorf("UpsertBotComment: marker %q must not contain \")\"", marker)
+	}
+	hidden := BotMarker(marker)
+	text = text + "\n\n" + hidden
+
+	story, err := c.GetStory(storyID)
+	if err != nil {
+		return nil, err
+	}
+	if existing := findBotComment(story.Comments, hidden); existing != nil {
+		if existing.Text == text {
+			return existing, nil
+		}
+		return c.UpdateStoryComment(storyID, existing.ID, &UpdateCommentParams{Text: text})
+	}
+	return c.CreateStoryComment(storyID, &CreateCommentParams{Text: text})
+}
+
+// findBotComment returns the most recent comment containing hidden.
+func findBotComment(comments []Comment, hidden string) *Comment {
+	var found *Comment
+	for i := range comments {
+		comment := &comments[i]
+		if !strings.Contains(comment.Text, hidden) {
+			continue
+		}
+		if found == nil || comment.CreatedAt.After(found.CreatedAt) {
+			found = comment
+		}
+	}
+	return found
+}
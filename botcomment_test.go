@@ -0,0 +1,23 @@
+package clubhouse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindBotComment(t *testing.T) {
+	hidden := BotMarker("ci")
+	comments := []Comment{
+		{ID: 1, Text: "build passed\n\n" + hidden, CreatedAt: time.Unix(10, 0)},
+		{ID: 2, Text: "a human comment", CreatedAt: time.Unix(30, 0)},
+		{ID: 3, Text: "build failed\n\n" + hidden, CreatedAt: time.Unix(20, 0)},
+		{ID: 4, Text: "other bot\n\n" + BotMarker("cid"), CreatedAt: time.Unix(40, 0)},
+	}
+	found := findBotComment(comments, hidden)
+	if found == nil || found.ID != 3 {
+		t.Errorf("expected comment 3, got %+v", found)
+	}
+	if found := findBotComment(comments[1:2], hidden); found != nil {
+		t.Errorf("expected no comment, got %+v", found)
+	}
+}
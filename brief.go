@@ -0,0 +1,76 @@
+package clubhouse
+
+import (
+	"context"
+	"path"
+	"time"
+)
+
+// StoryBrief is a reduced view of a story, for pollers that only need
+// to know what a story is called and where it is. Decoding into it
+// skips everything else in the response.
+type StoryBrief struct {
+	Archived        bool      `json:"archived"`
+	Completed       bool      `json:"completed"`
+	Deadline        time.Time `json:"deadline"`
+	EpicID          int       `json:"epic_id"`
+	Estimate        int       `json:"estimate"`
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	OwnerIDs        []string  `json:"owner_ids"`
+	Position        int       `json:"position"`
+	ProjectID       int       `json:"project_id"`
+	StoryType       StoryType `json:"story_type"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	WorkflowStateID int       `json:"workflow_state_id"`
+}
+
+// ListProjectStories lists the stories in a project. The API only
+// returns the slim version of each story here.
+func (c *Client) ListProjectStories(projectID int, opts ...ListOptions) ([]StorySlim, error) {
+	resource := []StorySlim{}
+	uri := listEndpoint(path.Join("projects", itoa(projectID), "stories"), opts)
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// ListProjectStoryBriefs is like ListProjectStories but only decodes the
+// fields in StoryBrief, and doesn't ask for descriptions.
+func (c *Client) ListProjectStoryBriefs(projectID int) ([]StoryBrief, error) {
+	resource := []StoryBrief{}
+	uri := listEndpoint(path.Join("projects", itoa(projectID), "stories"), briefOptions)
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// SearchStoryBriefs runs a search, following every page, and returns
+// StoryBriefs. Unless params.Detail is set, slim results are requested
+// to cut down the size of each page. params isn't modified.
+func (c *Client) SearchStoryBriefs(params *SearchParams) ([]StoryBrief, error) {
+	pager := newSearchPager(c, context.Background(), "stories", params)
+	if pager.params.Detail == "" {
+		pager.params.Detail = SearchDetailSlim
+	}
+	collected := []StoryBrief{}
+	for {
+		page := struct {
+			Data  []StoryBrief `json:"data"`
+			Next  string       `json:"next"`
+			Total int          `json:"total"`
+		}{}
+		if !pager.fetch(&page, &page.Next, &page.Total) {
+			break
+		}
+		collected = append(collected, page.Data...)
+	}
+	if pager.err != nil {
+		return nil, pager.err
+	}
+	return collected, nil
+}
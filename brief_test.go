@@ -0,0 +1,40 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchStoryBriefs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		params := SearchParams{}
+		json.Unmarshal(body, &params)
+		if params.Detail != SearchDetailSlim {
+			t.Errorf("expected slim detail, got %q", params.Detail)
+		}
+		if params.Next == "" {
+			w.Write([]byte(`{"data":[{"id":1,"name":"one","workflow_state_id":500,"description":"long"}],` +
+				`"next":"/api/v2/search/stories?next=abc"}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":2,"name":"two"}],"next":null}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	params := &SearchParams{Query: &SearchQuery{}}
+	briefs, err := c.SearchStoryBriefs(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Detail != "" || params.Next != "" {
+		t.Errorf("expected params to be left alone, got %+v", params)
+	}
+	if len(briefs) != 2 || briefs[0].WorkflowStateID != 500 || briefs[1].Name != "two" {
+		t.Errorf("unexpected briefs: %+v", briefs)
+	}
+}
@@ -0,0 +1,334 @@
+package clubhouse
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrBulk is returned by the client-side bulk helpers when some, but
+// not necessarily all, of the items could not be processed. Errors is
+// keyed by the ID of the item that failed, and Result describes the
+// whole operation.
+type ErrBulk struct {
+	Errors map[int]error
+	Result *BulkResult
+}
+
+func (e ErrBulk) Error() string {
+	ids := make([]int, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if e.Result != nil && e.Result.URLs[id] != "" {
+			msgs = append(msgs, fmt.Sprintf("%d (%s): %s", id, e.Result.URLs[id], e.Errors[id]))
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("%d: %s", id, e.Errors[id]))
+	}
+	return fmt.Sprintf("clubhouse: %d bulk operation(s) failed: %s",
+		len(ids), strings.Join(msgs, "; "))
+}
+
+// BulkResult describes a run of one of the bulk helpers, e.g.
+// UpdateEpics or MoveStoriesToEpic. Every run is passed to the client's
+// BulkResultHandler, and runs with failures come back as an ErrBulk
+// carrying their BulkResult, so callers can report on and retry all the
+// helpers the same way.
+type BulkResult struct {
+	// Operation is the helper's name, e.g. "UpdateEpics".
+	Operation string
+	// Succeeded are the IDs of the items that were processed, in the
+	// order they were given.
+	Succeeded []int
+	// Failed has the error for each ID that couldn't be processed. IDs
+	// that were never attempted, e.g. chunks after a failed bulk
+	// request, are in neither Succeeded nor Failed.
+	Failed map[int]error
+	// Retries is how many times the client retried a request while the
+	// operation ran. Requests made concurrently by other callers of the
+	// same client are counted too.
+	Retries int
+	// Duration is how long the operation took.
+	Duration time.Duration
+	// URLs has the app link for each succeeded or failed ID when the
+	// items are stories or epics and the client already knows its
+	// workspace slug (see CachedStoryURL).
+	URLs map[int]string
+}
+
+// Err returns an ErrBulk if anything failed, or nil.
+func (r *BulkResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	return ErrBulk{Errors: r.Failed, Result: r}
+}
+
+// BulkResultHandler is called with the result of every bulk helper run.
+type BulkResultHandler func(BulkResult)
+
+// bulkRun fills in a BulkResult as an operation runs.
+type bulkRun struct {
+	c        *Client
+	result   BulkResult
+	start    time.Time
+	retries  int
+	endpoint string
+}
+
+// startBulk starts a result for operation on the items of the resource
+// collection endpoint, e.g. "stories".
+func (c *Client) startBulk(operation, endpoint string) *bulkRun {
+	return &bulkRun{
+		c:        c,
+		result:   BulkResult{Operation: operation, Succeeded: []int{}},
+		start:    c.clock().Now(),
+		retries:  c.counters.retryCount(),
+		endpoint: endpoint,
+	}
+}
+
+func (r *bulkRun) succeeded(ids ...int) {
+	r.result.Succeeded = append(r.result.Succeeded, ids...)
+}
+
+func (r *bulkRun) failed(err error, ids ...int) {
+	if r.result.Failed == nil {
+		r.result.Failed = map[int]error{}
+	}
+	for _, id := range ids {
+		r.result.Failed[id] = err
+	}
+}
+
+// finish completes the result, hands it to the BulkResultHandler, and
+// returns it.
+func (r *bulkRun) finish() *BulkResult {
+	r.result.Duration = r.c.clock().Now().Sub(r.start)
+	r.result.Retries = r.c.counters.retryCount() - r.retries
+	r.addURLs(r.endpoint, r.result.Succeeded)
+	for id := range r.result.Failed {
+		r.addURLs(r.endpoint, []int{id})
+	}
+	if r.c.BulkResultHandler != nil {
+		r.c.BulkResultHandler(r.result)
+	}
+	return &r.result
+}
+
+func (r *bulkRun) addURLs(endpoint string, ids []int) {
+	link := map[string]func(int) string{
+		"stories": r.c.CachedStoryURL,
+		"epics":   r.c.CachedEpicURL,
+	}[endpoint]
+	if link == nil {
+		return
+	}
+	for _, id := range ids {
+		url := link(id)
+		if url == "" {
+			return
+		}
+		if r.result.URLs == nil {
+			r.result.URLs = map[int]string{}
+		}
+		r.result.URLs[id] = url
+	}
+}
+
+// bulk calls fn for every id, running up to BulkConcurrency calls at
+// a time. Every request still goes through the client's rate limiter.
+// Progress is reported under the name operation, with endpoint being
+// the resource collection, e.g. "epics".
+func (c *Client) bulk(
+	operation string,
+	endpoint string,
+	ids []int,
+	fn func(i int, id int) error,
+) *BulkResult {
+	c.checkSetup()
+	run := c.startBulk(operation, endpoint)
+	run.each(endpoint, ids, c.trackProgress(operation, len(ids)), fn)
+	return run.finish()
+}
+
+// each calls fn for every id like bulk does, recording the outcomes in
+// the run, for operations that span more than one resource collection.
+// URLs are added for endpoint as it goes, so the run itself can be
+// started without one. It returns the failures among ids.
+func (r *bulkRun) each(
+	endpoint string,
+	ids []int,
+	progress *progressTracker,
+	fn func(i int, id int) error,
+) map[int]error {
+	results := GoEach(context.Background(), r.c.BulkConcurrency, len(ids), func(_ context.Context, i int) error {
+		defer progress.step(1, path.Join(endpoint, itoa(ids[i])))
+		return fn(i, ids[i])
+	})
+
+	// results is nil if every call succeeded
+	var failed map[int]error
+	for i, id := range ids {
+		if results != nil && results[i] != nil {
+			r.failed(results[i], id)
+			if failed == nil {
+				failed = map[int]error{}
+			}
+			failed[id] = results[i]
+		} else {
+			r.succeeded(id)
+		}
+	}
+	if r.endpoint != endpoint {
+		r.addURLs(endpoint, ids)
+	}
+	return failed
+}
+
+// UpdateEpics applies the same update to many epics. Clubhouse doesn't
+// have a bulk endpoint for epics, so this is done client-side, making
+// up to BulkConcurrency requests at a time.
+//
+// The epics that were updated successfully are returned in the same
+// order as ids. If any of the updates failed, the error will be an
+// ErrBulk describing which.
+func (c *Client) UpdateEpics(ids []int, params UpdateEpicParams) ([]Epic, error) {
+	updated := make([]*Epic, len(ids))
+	result := c.bulk("UpdateEpics", "epics", ids, func(i, id int) error {
+		epic, err := c.UpdateEpic(id, params)
+		updated[i] = epic
+		return err
+	})
+
+	epics := []Epic{}
+	for _, epic := range updated {
+		if epic != nil {
+			epics = append(epics, *epic)
+		}
+	}
+	return epics, result.Err()
+}
+
+// updateStoriesChunked applies params to ids, breaking them up into
+// chunks small enough for the bulk endpoint, and stopping at the first
+// chunk that fails. It returns the updated stories, and an ErrBulk
+// with the failed chunk's IDs if there was a failure. The result is
+// reported under operation.
+func (c *Client) updateStoriesChunked(operation string, ids []int, params UpdateStoriesParams) ([]StorySlim, error) {
+	c.checkSetup()
+	run := c.startBulk(operation, "stories")
+	updated := []StorySlim{}
+	progress := c.trackProgress(operation, len(ids))
+	for start := 0; start < len(ids); start += MaxBulkItems {
+		end := start + MaxBulkItems
+		if end > len(ids) {
+			end = len(ids)
+		}
+		params.StoryIDs = ids[start:end]
+		stories, err := c.UpdateStories(&params)
+		if err != nil {
+			run.failed(err, ids[start:end]...)
+			break
+		}
+		run.succeeded(ids[start:end]...)
+		updated = append(updated, stories...)
+		progress.step(end-start, "stories/bulk")
+	}
+	return updated, run.finish().Err()
+}
+
+// searchStoryIDs returns the IDs of every story matching query.
+func (c *Client) searchStoryIDs(query SearchQuery) ([]int, error) {
+	results, err := c.SearchStoriesAll(&SearchParams{
+		PageSize: 25,
+		Query:    &query,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(results))
+	for i, s := range results {
+		ids[i] = s.ID
+	}
+	return ids, nil
+}
+
+// ApplyLabelToSearch adds label to every story matching query and
+// returns the number of stories that were updated.
+func (c *Client) ApplyLabelToSearch(query SearchQuery, label CreateLabelParams) (int, error) {
+	ids, err := c.searchStoryIDs(query)
+	if err != nil {
+		return 0, err
+	}
+	updated, err := c.updateStoriesChunked("ApplyLabelToSearch", ids, UpdateStoriesParams{
+		LabelsAdd: []CreateLabelParams{label},
+	})
+	return len(updated), err
+}
+
+// RemoveLabelFromSearch removes label from every story matching query
+// and returns the number of stories that were updated.
+func (c *Client) RemoveLabelFromSearch(query SearchQuery, label CreateLabelParams) (int, error) {
+	ids, err := c.searchStoryIDs(query)
+	if err != nil {
+		return 0, err
+	}
+	updated, err := c.updateStoriesChunked("RemoveLabelFromSearch", ids, UpdateStoriesParams{
+		LabelsRemove: []CreateLabelParams{label},
+	})
+	return len(updated), err
+}
+
+// MoveStoriesToEpic puts every story in storyIDs into the epic epicID
+// using the bulk endpoint. Pass ResetID's value (-1) to take the
+// stories out of their epic. The updated stories are returned in the
+// same order as storyIDs.
+func (c *Client) MoveStoriesToEpic(storyIDs []int, epicID int) ([]StorySlim, error) {
+	updated, err := c.updateStoriesChunked("MoveStoriesToEpic", storyIDs, UpdateStoriesParams{
+		EpicID: resettableID(epicID),
+	})
+	return orderStories(storyIDs, updated), err
+}
+
+// MoveStoriesToIteration puts every story in storyIDs into the
+// iteration iterationID using the bulk endpoint. Pass ResetID's value
+// (-1) to take the stories out of their iteration. The updated stories
+// are returned in the same order as storyIDs.
+func (c *Client) MoveStoriesToIteration(storyIDs []int, iterationID int) ([]StorySlim, error) {
+	updated, err := c.updateStoriesChunked("MoveStoriesToIteration", storyIDs, UpdateStoriesParams{
+		IterationID: resettableID(iterationID),
+	})
+	return orderStories(storyIDs, updated), err
+}
+
+// resettableID returns ResetID itself for -1, so the param is sent as
+// null, and a pointer to id otherwise.
+func resettableID(id int) *int {
+	if id == *ResetID {
+		return ResetID
+	}
+	return ID(id)
+}
+
+// orderStories sorts stories into the order of ids.
+func orderStories(ids []int, stories []StorySlim) []StorySlim {
+	byID := map[int]StorySlim{}
+	for _, s := range stories {
+		byID[s.ID] = s
+	}
+	out := make([]StorySlim, 0, len(stories))
+	for _, id := range ids {
+		if s, ok := byID[id]; ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
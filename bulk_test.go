@@ -0,0 +1,163 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBulk(t *testing.T) {
+	c := &Client{AuthToken: "tkn", BulkConcurrency: 2}
+	ids := []int{1, 2, 3, 4, 5}
+	seen := make([]int, len(ids))
+	var handled BulkResult
+	c.BulkResultHandler = func(r BulkResult) { handled = r }
+	result := c.bulk("test", "things", ids, func(i, id int) error {
+		seen[i] = id
+		if id%2 == 0 {
+			return fmt.Errorf("even")
+		}
+		return nil
+	})
+	for i, id := range ids {
+		if seen[i] != id {
+			t.Errorf("expected %d to be processed", id)
+		}
+	}
+	if !reflect.DeepEqual(result.Succeeded, []int{1, 3, 5}) || len(result.Failed) != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if handled.Operation != "test" || len(handled.Succeeded) != 3 {
+		t.Errorf("expected the result to be handled, got %+v", handled)
+	}
+	err := result.Err()
+	expect := "clubhouse: 2 bulk operation(s) failed: 2: even; 4: even"
+	if err.Error() != expect {
+		t.Errorf("%s != %s", err.Error(), expect)
+	}
+	if e := err.(ErrBulk); e.Result != result {
+		t.Error("expected ErrBulk to carry the result")
+	}
+	if (&BulkResult{}).Err() != nil {
+		t.Error("expected no error without failures")
+	}
+
+	result = c.bulk("test", "things", ids, func(i, id int) error { return nil })
+	if !reflect.DeepEqual(result.Succeeded, ids) || result.Err() != nil {
+		t.Errorf("expected every item to succeed, got %+v", result)
+	}
+}
+
+func TestBulkURLs(t *testing.T) {
+	c := &Client{AuthToken: "tkn"}
+	fail := func(i, id int) error {
+		if id == 2 {
+			return fmt.Errorf("nope")
+		}
+		return nil
+	}
+	if result := c.bulk("test", "stories", []int{1, 2}, fail); result.URLs != nil {
+		t.Errorf("expected no URLs without a workspace slug, got %v", result.URLs)
+	}
+
+	member := &MemberInfo{}
+	member.Workspace.URLSlug = "acme"
+	c.setCurrentMember(member)
+	result := c.bulk("test", "stories", []int{1, 2}, fail)
+	expect := map[int]string{
+		1: "https://app.clubhouse.io/acme/story/1",
+		2: "https://app.clubhouse.io/acme/story/2",
+	}
+	if !reflect.DeepEqual(result.URLs, expect) {
+		t.Errorf("expected %v, got %v", expect, result.URLs)
+	}
+	msg := "clubhouse: 1 bulk operation(s) failed: 2 (https://app.clubhouse.io/acme/story/2): nope"
+	if err := result.Err(); err.Error() != msg {
+		t.Errorf("%s != %s", err, msg)
+	}
+	if result := c.bulk("test", "things", []int{1}, fail); result.URLs != nil {
+		t.Errorf("expected no URLs for other resources, got %v", result.URLs)
+	}
+}
+
+func TestMoveStoriesReset(t *testing.T) {
+	bodies := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`[{"id":2},{"id":1}]`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	stories, err := c.MoveStoriesToEpic([]int{1, 2}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stories[0].ID != 1 || stories[1].ID != 2 {
+		t.Errorf("expected the stories in the order given, got %v", stories)
+	}
+	if _, err := c.MoveStoriesToIteration([]int{1, 2}, -1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.MoveStoriesToEpic([]int{1, 2}, 7); err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		`{"epic_id":null,"story_ids":[1,2]}`,
+		`{"iteration_id":null,"story_ids":[1,2]}`,
+		`{"epic_id":7,"story_ids":[1,2]}`,
+	}
+	if !reflect.DeepEqual(bodies, expect) {
+		t.Errorf("expected %v, got %v", expect, bodies)
+	}
+}
+
+func TestApplyLabelToSearch(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data":[{"id":1},{"id":2}],"next":"/api/v2/search/stories?next=p2","total":3}`,
+		"p2": `{"data":[{"id":3}],"total":3}`,
+	}
+	searches := 0
+	updates := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v2/search/stories":
+			searches++
+			var params SearchParams
+			json.Unmarshal(body, &params)
+			w.Write([]byte(pages[params.Next]))
+		case "PUT /v2/stories/bulk":
+			updates = append(updates, string(body))
+			w.Write([]byte(`[{"id":1},{"id":2},{"id":3}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	label := CreateLabelParams{Name: "triaged"}
+
+	n, err := c.ApplyLabelToSearch(SearchQuery{Label: []string{"bug"}}, label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || searches != 2 {
+		t.Errorf("expected 3 stories from 2 pages, got %d from %d", n, searches)
+	}
+	if _, err := c.RemoveLabelFromSearch(SearchQuery{Label: []string{"bug"}}, label); err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		`{"labels_add":[{"name":"triaged"}],"story_ids":[1,2,3]}`,
+		`{"labels_remove":[{"name":"triaged"}],"story_ids":[1,2,3]}`,
+	}
+	if !reflect.DeepEqual(updates, expect) {
+		t.Errorf("expected %v, got %v", expect, updates)
+	}
+}
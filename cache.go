@@ -0,0 +1,172 @@
+package clubhouse
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiskCache is the file-backed Store. It was written for caching
+// responses to GET requests in local development and CI, where the
+// same reference data (workflows, members, projects) is fetched over
+// and over across runs, but any Store user can use it.
+//
+// Response cache entries are content-addressed by the API they came
+// from (RootURL, Version and Flavor), the endpoint, a hash of the
+// AuthToken and the request parameters, so different tokens and
+// workspaces never share entries and the token itself is never written
+// to disk.
+type DiskCache struct {
+	// Dir is where entries are stored. It's created if it doesn't
+	// exist.
+	Dir string
+
+	// MaxAge is how long an entry stays fresh, regardless of the TTL
+	// it was stored with. Zero means forever.
+	MaxAge time.Duration
+}
+
+// cacheKey returns the content address for a request to api, which
+// identifies the API's root URL, version and flavor.
+func cacheKey(token, api, method, endpoint string, body []byte) string {
+	tokenHash := sha256.Sum256([]byte(token))
+	h := sha256.New()
+	h.Write(tokenHash[:])
+	h.Write([]byte(api + "\n"))
+	h.Write([]byte(method + " " + endpoint + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path returns the file for key. Keys are escaped to make them safe
+// file names, and spread over subdirectories by hash so no directory
+// gets too big.
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.Dir, hex.EncodeToString(sum[:1]), escapeKey(key))
+}
+
+func escapeKey(key string) string {
+	escaped := url.PathEscape(key)
+	if strings.HasPrefix(escaped, ".") {
+		// leading dots are reserved for temporary files
+		escaped = "%2E" + escaped[1:]
+	}
+	return escaped
+}
+
+// Get returns the cached value for key, if there's a fresh one.
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	p := d.path(key)
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, false
+	}
+	if d.MaxAge > 0 && time.Since(info.ModTime()) > d.MaxAge {
+		return nil, false
+	}
+	content, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	value, expired, ok := decodeEntry(content)
+	if !ok || expired {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set stores value under key. The entry is written to a temporary file
+// first so readers never see a partial entry.
+func (d *DiskCache) Set(key string, value []byte, ttl time.Duration) error {
+	p := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(p), ".entry")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(encodeEntry(value, ttl)); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// Delete removes key from the cache.
+func (d *DiskCache) Delete(key string) error {
+	err := os.Remove(d.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Keys returns every fresh key starting with prefix, sorted.
+func (d *DiskCache) Keys(prefix string) ([]string, error) {
+	keys := []string{}
+	err := filepath.Walk(d.Dir, func(p string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil || info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return err
+		}
+		key, err := url.PathUnescape(info.Name())
+		if err != nil || !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		if _, ok := d.Get(key); ok {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Clear removes every entry from the cache.
+func (d *DiskCache) Clear() error {
+	return os.RemoveAll(d.Dir)
+}
+
+// Entries start with a line holding the expiry time in Unix
+// nanoseconds, or 0 if they never expire.
+func encodeEntry(value []byte, ttl time.Duration) []byte {
+	var expires int64
+	if ttl > 0 {
+		expires = time.Now().Add(ttl).UnixNano()
+	}
+	header := strconv.FormatInt(expires, 10) + "\n"
+	return append([]byte(header), value...)
+}
+
+func decodeEntry(content []byte) (value []byte, expired bool, ok bool) {
+	i := bytes.IndexByte(content, '\n')
+	if i < 0 {
+		return nil, false, false
+	}
+	expires, err := strconv.ParseInt(string(content[:i]), 10, 64)
+	if err != nil {
+		return nil, false, false
+	}
+	expired = expires != 0 && time.Now().UnixNano() > expires
+	return content[i+1:], expired, true
+}
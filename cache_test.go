@@ -0,0 +1,51 @@
+package clubhouse
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clubhouse-cache")
+	if err != nil {
+		t.Fatal("could not make temp dir", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := &DiskCache{Dir: dir, MaxAge: time.Hour}
+	key := cacheKey("tkn", "api", "GET", "workflows", nil)
+	if key == cacheKey("other", "api", "GET", "workflows", nil) {
+		t.Error("different tokens should have different keys")
+	}
+	if key == cacheKey("tkn", "other api", "GET", "workflows", nil) {
+		t.Error("different APIs should have different keys")
+	}
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("should start empty")
+	}
+	if err := cache.Set(key, []byte("[]"), 0); err != nil {
+		t.Fatal("did not expect error", err)
+	}
+	content, ok := cache.Get(key)
+	if !ok || string(content) != "[]" {
+		t.Errorf("expected cached content, got %q", content)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(cache.path(key), old, old)
+	if _, ok := cache.Get(key); ok {
+		t.Error("stale entry should not be returned")
+	}
+}
+
+func TestDiskCacheStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clubhouse-cache")
+	if err != nil {
+		t.Fatal("could not make temp dir", err)
+	}
+	defer os.RemoveAll(dir)
+
+	testStore(t, &DiskCache{Dir: dir})
+}
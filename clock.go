@@ -0,0 +1,182 @@
+package clubhouse
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the client's source of time. Retries, SearchStoriesEventually,
+// rate limit accounting, Watcher and StatsRecorder all go through it,
+// so tests can use a FakeClock instead of waiting for real time to
+// pass. The Limiter paces requests on its own; use RateLimiter(0) in
+// tests to turn it off.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker is a time.Ticker from a Clock.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// RealClock is the Clock used when a client doesn't have one.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.t.C }
+func (t realTicker) Stop()               { t.t.Stop() }
+
+// clock returns the client's Clock, or RealClock.
+func (c *Client) clock() Clock {
+	if c.Clock == nil {
+		return RealClock
+	}
+	return c.Clock
+}
+
+// FakeClock is a Clock that only moves when Advance is called. Timers
+// and tickers fire as Advance passes them, so code that waits can be
+// tested without waiting:
+//
+//	clock := NewFakeClock(time.Now())
+//	c := &Client{AuthToken: token, Clock: clock}
+//	go c.SearchStoriesEventually(params, time.Minute, 3)
+//	clock.BlockUntil(1) // the search is waiting to poll again
+//	clock.Advance(time.Second)
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a pending After or an active ticker.
+type fakeWaiter struct {
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+}
+
+// NewFakeClock makes a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the time once the clock has
+// been advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.add(&fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// NewTicker returns a Ticker that ticks every time the clock is
+// advanced past another d. Like a time.Ticker, ticks are dropped while
+// nobody is reading them.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &fakeWaiter{at: c.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.add(w)
+	return fakeTicker{c, w}
+}
+
+func (c *FakeClock) add(w *fakeWaiter) {
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+}
+
+// Advance moves the clock forward by d, firing every After and ticker
+// that comes due on the way.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	end := c.now.Add(d)
+	for {
+		next := c.nextDue(end)
+		if next == nil {
+			break
+		}
+		c.now = next.at
+		select {
+		case next.ch <- c.now:
+		default:
+		}
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+		} else {
+			c.remove(next)
+		}
+	}
+	c.now = end
+}
+
+// nextDue returns the earliest waiter due by end, or nil.
+func (c *FakeClock) nextDue(end time.Time) *fakeWaiter {
+	var next *fakeWaiter
+	for _, w := range c.waiters {
+		if !w.at.After(end) && (next == nil || w.at.Before(next.at)) {
+			next = w
+		}
+	}
+	return next
+}
+
+func (c *FakeClock) remove(w *fakeWaiter) {
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// BlockUntil waits until there are at least n pending Afters and
+// tickers, i.e. until the code under test is waiting on the clock.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+type fakeTicker struct {
+	c *FakeClock
+	w *fakeWaiter
+}
+
+func (t fakeTicker) C() <-chan time.Time { return t.w.ch }
+
+func (t fakeTicker) Stop() {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	t.c.remove(t.w)
+}
@@ -0,0 +1,132 @@
+package clubhouse
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := NewFakeClock(start)
+	after := clock.After(2 * time.Second)
+	ticker := clock.NewTicker(time.Second)
+
+	clock.Advance(time.Second)
+	if got := <-ticker.C(); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("expected tick at 1s, got %s", got)
+	}
+	select {
+	case <-after:
+		t.Error("After fired early")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	if got := <-after; !got.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("expected After at 2s, got %s", got)
+	}
+
+	<-ticker.C() // the tick at 2s
+	ticker.Stop()
+	clock.Advance(time.Hour)
+	select {
+	case <-ticker.C():
+		t.Error("stopped ticker ticked")
+	default:
+	}
+	if !clock.Now().Equal(start.Add(time.Hour + 2*time.Second)) {
+		t.Errorf("unexpected time %s", clock.Now())
+	}
+}
+
+func TestSearchStoriesEventuallyClock(t *testing.T) {
+	var mu sync.Mutex
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls++
+		total := polls
+		mu.Unlock()
+		w.Write([]byte(`{"total": ` + itoa(total) + `, "data": []}`))
+	}))
+	defer srv.Close()
+	clock := NewFakeClock(time.Now())
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), Clock: clock}
+
+	done := make(chan error)
+	go func() {
+		_, err := c.SearchStoriesEventually(&SearchParams{}, time.Minute, 3)
+		done <- err
+	}()
+	// backs off 1s then 2s before the third poll finds enough results
+	for _, wait := range []time.Duration{time.Second, 2 * time.Second} {
+		clock.BlockUntil(1)
+		clock.Advance(wait)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+}
+
+func TestRetryClock(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(500)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	clock := NewFakeClock(time.Now())
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), Retries: 1, Clock: clock}
+
+	done := make(chan error)
+	go func() {
+		_, err := c.ListLabels()
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(retryBackoff)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected a retry, got %d attempts", attempts)
+	}
+}
+
+func TestRetryPOST(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(502)
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), Retries: 3}
+
+	if _, err := c.CreateLabel(&CreateLabelParams{Name: "x"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("expected a POST that reached the server not to be retried, got %d attempts", attempts)
+	}
+
+	refused := ErrClientRequest{Stage: ErrStageSendRequest, Err: &url.Error{Op: "Post", Err: &net.OpError{Op: "dial"}}}
+	if !retryable("POST", refused) {
+		t.Error("expected a POST that never connected to be retried")
+	}
+	timedOut := ErrClientRequest{Stage: ErrStageSendRequest, Err: &url.Error{Op: "Post", Err: &net.OpError{Op: "read"}}}
+	if retryable("POST", timedOut) || !retryable("PUT", timedOut) {
+		t.Error("expected only the PUT that failed mid-request to be retried")
+	}
+}
@@ -2,18 +2,24 @@ package clubhouse
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/ratelimit"
@@ -35,9 +41,18 @@ func (e ErrResponse) Error() string {
 var (
 	ErrSchemaMismatch   = ErrResponse{400, "Schema mismatch"}
 	ErrUnauthorized     = ErrResponse{401, "Unauthorized"}
+	ErrForbidden        = ErrResponse{403, "Forbidden"}
 	ErrResourceNotFound = ErrResponse{404, "Resource does not exist"}
+	ErrConflict         = ErrResponse{409, "Conflict"}
+	ErrEntityTooLarge   = ErrResponse{413, "Request entity too large"}
 	ErrUnprocessable    = ErrResponse{422, "Unprocessable"}
+	ErrTooManyRequests  = ErrResponse{429, "Too many requests"}
 	ErrServerError      = ErrResponse{500, "Server error"}
+	ErrUnavailable      = ErrResponse{503, "Service unavailable"}
+
+	// ErrMaintenance is a 503 with an HTML page instead of a JSON body,
+	// which is what the API serves while it's down for maintenance.
+	ErrMaintenance = ErrResponse{503, "Down for maintenance"}
 )
 
 // Defaults. You can override any of these to change the default for all
@@ -46,16 +61,29 @@ var (
 	// Root URL for the API
 	DefaultRootURL = "https://api.clubhouse.io/api/"
 
+	// Root URL of the Clubhouse app, for StoryURL and EpicURL
+	AppRootURL = "https://app.clubhouse.io/"
+
 	// Current version as of 04-2018 is v2
 	DefaultVersion = "v2"
 
 	// Clubhouse API is 200/minute, so 3.333 every second, which we
 	// round down to 3 since we need to use an int
-	DefaultLimiter = RateLimiter(3)
+	DefaultRequestsPerSecond = 3
+
+	DefaultLimiter = RateLimiter(DefaultRequestsPerSecond)
+
+	// DefaultTokenHeader is the header the AuthToken is sent in, unless
+	// the client has TokenInQuery set or a Flavor with its own header.
+	DefaultTokenHeader = "Clubhouse-Token"
 
 	// DefaultHTTP client is, perhaps unsurprisingly, the default http
 	// client.
 	DefaultHTTPClient = http.DefaultClient
+
+	// DefaultBulkConcurrency is the number of requests the client-side
+	// bulk helpers (e.g. UpdateEpics) will have in flight at once.
+	DefaultBulkConcurrency = 4
 )
 
 // RateLimiter makes a new rate limiter using n as the number of
@@ -95,10 +123,13 @@ var (
 	Unarchived      = &pfalse
 	ShowThermometer = &ptrue
 	HideThermometer = &pfalse
+	Complete        = &ptrue
+	Incomplete      = &pfalse
 	ResetID         = ID(-1)
 	ResetEstimate   = ID(-1)
 	ResetTime       = Time(time.Time{})
 	ResetColor      = String("")
+	ResetGroupID    = String("")
 	EmptyString     = String("")
 
 	ptrue  = true
@@ -112,10 +143,95 @@ type Client struct {
 	Version    string
 	HTTPClient *http.Client
 	Limiter    ratelimit.Limiter
+
+	// Flavor picks the defaults for RootURL, Version, the token header
+	// and app links. The zero value is FlavorClubhouse.
+	Flavor APIFlavor
+
+	// TokenInQuery sends the AuthToken as a token query param, the way
+	// this package used to, instead of in a header.
+	// Query params end up in proxy and server logs, so only set it for
+	// servers that don't read the header.
+	TokenInQuery bool
+
+	// BulkConcurrency is the number of requests the client-side bulk
+	// helpers will have in flight at once.
+	BulkConcurrency int
+
+	// DeterministicJSON makes the client encode params with
+	// MarshalDeterministic so request bodies are reproducible, e.g. for
+	// recorded fixtures or request signatures.
+	DeterministicJSON bool
+
+	// Retries is the number of times a request is retried when it
+	// couldn't be sent or the server responded with a 5xx or 429 error.
+	// POSTs aren't idempotent, so they're only retried when they never
+	// reached the server or got a 429.
+	Retries int
+
+	// Progress, if set, receives updates from long-running operations.
+	Progress Progress
+
+	// Cache, if set, is used to store and serve responses to GET
+	// requests. Entries are stored with a TTL of CacheTTL, where zero
+	// means they never expire. Writes made through the client don't
+	// invalidate cached GETs, so a GET can return data from before a
+	// change until its entry expires; set a CacheTTL, or leave Cache
+	// unset, if that matters.
+	Cache    Store
+	CacheTTL time.Duration
+
+	// UnknownEnumHandler, if set, is called whenever a response
+	// contains an enum value this package doesn't know about.
+	UnknownEnumHandler UnknownEnumHandler
+
+	// SearchCollisionHandler, if set, is called whenever SearchStoriesAll
+	// sees the same story on more than one page.
+	SearchCollisionHandler SearchCollisionHandler
+
+	// EpicSearchCollisionHandler is SearchCollisionHandler for
+	// SearchEpicsAll.
+	EpicSearchCollisionHandler EpicSearchCollisionHandler
+
+	// Clock, if set, replaces real time for retries, polling and the
+	// other things that wait or measure time. See FakeClock.
+	Clock Clock
+
+	// BulkResultHandler, if set, is called with the result of every
+	// run of a bulk helper, e.g. for uniform logging of batch jobs.
+	BulkResultHandler BulkResultHandler
+
+	// Policy, if set, restricts which requests the client may make.
+	Policy *Policy
+
+	// Conventions, if set, are applied to new stories by CreateStory
+	// and CreateStories.
+	Conventions Conventions
+
+	// ExpvarName, if set, publishes request and entity counters with
+	// expvar under this name, e.g. for /debug/vars.
+	ExpvarName string
+
+	// Logger, if set, receives the client's log messages: requests and
+	// their bodies at LogDebug, and retries and other things that went
+	// wrong but were worked around at LogWarn.
+	Logger Logger
+
+	// MaxResponseBytes, if set, is the largest response body the client
+	// will read. Bigger responses fail with ErrResponseTooLarge instead
+	// of being read into memory. Use the Each methods, e.g. EachFile, to
+	// stream endpoints that are expected to be large.
+	MaxResponseBytes int64
+
+	memberMu   sync.Mutex
+	member     *MemberInfo
+	counters   diagnosticCounters
+	middleware []Middleware
 }
 
 // CreateCategory creates a new category. If Category is given a name
-// that already exists, you will get an ErrUnprocessable error.
+// that already exists, errors.Is(err, ErrUnprocessable) is true for the
+// error.
 func (c *Client) CreateCategory(params *CreateCategoryParams) (*Category, error) {
 	resource := Category{}
 	uri := "categories"
@@ -153,7 +269,7 @@ func (c *Client) GetCategory(id int) (*Category, error) {
 
 // UpdateCategory allows you to replace a Category name with another
 // name. If you try to name a Category something that already exists,
-// you will get an ErrUnprocessable error.
+// errors.Is(err, ErrUnprocessable) is true for the error.
 func (c *Client) UpdateCategory(id int, params *UpdateCategoryParams) (*Category, error) {
 	resource := Category{}
 	uri := path.Join("categories", itoa(id))
@@ -170,9 +286,9 @@ func (c *Client) DeleteCategory(id int) error {
 }
 
 // ListEpics lists all the epics
-func (c *Client) ListEpics() ([]Epic, error) {
+func (c *Client) ListEpics(opts ...ListOptions) ([]Epic, error) {
 	resource := []Epic{}
-	uri := "epics"
+	uri := listEndpoint("epics", opts)
 	err := c.RequestResource("GET", &resource, uri, nil)
 	if err != nil {
 		return nil, err
@@ -219,6 +335,66 @@ func (c *Client) DeleteEpic(id int) error {
 	return c.RequestResource("DELETE", nil, uri, nil)
 }
 
+// GetEpicWorkflow gets the workspace's epic workflow, which lists the
+// epic states that EpicStateID can be set to.
+func (c *Client) GetEpicWorkflow() (*EpicWorkflow, error) {
+	resource := EpicWorkflow{}
+	uri := "epic-workflow"
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// Epic health updates are only in version 3 of the API, so these need
+// a client with Version set to "v3".
+
+// GetEpicHealth gets the current health of an epic.
+func (c *Client) GetEpicHealth(epicID int) (*Health, error) {
+	resource := Health{}
+	uri := path.Join("epics", itoa(epicID), "health")
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// CreateEpicHealth posts a new health update on an epic, which becomes
+// its current health.
+func (c *Client) CreateEpicHealth(epicID int, params *CreateHealthParams) (*Health, error) {
+	resource := Health{}
+	uri := path.Join("epics", itoa(epicID), "health")
+	err := c.RequestResource("POST", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// ListEpicHealths lists every health update posted on an epic.
+func (c *Client) ListEpicHealths(epicID int) ([]Health, error) {
+	resource := []Health{}
+	uri := path.Join("epics", itoa(epicID), "health-history")
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// UpdateHealth edits a health update, e.g. to fix its notes.
+func (c *Client) UpdateHealth(healthID string, params *UpdateHealthParams) (*Health, error) {
+	resource := Health{}
+	uri := path.Join("health", healthID)
+	err := c.RequestResource("PUT", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
 // CreateEpicComment ...
 func (c *Client) CreateEpicComment(epicID int, params *CreateCommentParams) (*ThreadedComment, error) {
 	resource := ThreadedComment{}
@@ -288,6 +464,71 @@ func (c *Client) DeleteEpicComment(epicID, commentID int) error {
 	return c.RequestResource("DELETE", nil, uri, nil)
 }
 
+// Entity templates are only in version 3 of the API, so these need a
+// client with Version set to "v3".
+
+// CreateEntityTemplate ...
+func (c *Client) CreateEntityTemplate(params *CreateEntityTemplateParams) (*EntityTemplate, error) {
+	resource := EntityTemplate{}
+	uri := "entity-templates"
+	err := c.RequestResource("POST", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// ListEntityTemplates ...
+func (c *Client) ListEntityTemplates() ([]EntityTemplate, error) {
+	resource := []EntityTemplate{}
+	uri := "entity-templates"
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// GetEntityTemplate ...
+func (c *Client) GetEntityTemplate(id string) (*EntityTemplate, error) {
+	resource := EntityTemplate{}
+	uri := path.Join("entity-templates", id)
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UpdateEntityTemplate ...
+func (c *Client) UpdateEntityTemplate(id string, params *UpdateEntityTemplateParams) (*EntityTemplate, error) {
+	resource := EntityTemplate{}
+	uri := path.Join("entity-templates", id)
+	err := c.RequestResource("PUT", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// DeleteEntityTemplate ...
+func (c *Client) DeleteEntityTemplate(id string) error {
+	uri := path.Join("entity-templates", id)
+	return c.RequestResource("DELETE", nil, uri, nil)
+}
+
+// DisableStoryTemplates turns story templates off for the workspace.
+func (c *Client) DisableStoryTemplates() error {
+	uri := path.Join("entity-templates", "disable")
+	return c.RequestResource("PUT", nil, uri, nil)
+}
+
+// EnableStoryTemplates turns story templates back on for the workspace.
+func (c *Client) EnableStoryTemplates() error {
+	uri := path.Join("entity-templates", "enable")
+	return c.RequestResource("PUT", nil, uri, nil)
+}
+
 // FileUpload ...
 type FileUpload struct {
 	Name string
@@ -378,6 +619,141 @@ func (c *Client) DeleteFile(id int) error {
 	return c.RequestResource("DELETE", nil, uri, nil)
 }
 
+// downloadFile fetches the contents of f, which need the same token as
+// the API. It goes through the client's Limiter and middleware like any
+// other request. The caller has to close the body.
+func (c *Client) downloadFile(ctx context.Context, f File) (io.ReadCloser, error) {
+	c.checkSetup()
+	req, err := http.NewRequest("GET", f.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	c.authenticate(req, redactedToken)
+	send := req.Clone(ctx)
+	c.authenticate(send, c.AuthToken)
+
+	c.Limiter.Take()
+	resp, err := c.roundTrip(send)
+	if err != nil {
+		return nil, scrubURLError(err, req)
+	}
+	if resp.StatusCode != 200 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// Groups are only in version 3 of the API, so these need a client with
+// Version set to "v3".
+
+// CreateGroup ...
+func (c *Client) CreateGroup(params *CreateGroupParams) (*Group, error) {
+	resource := Group{}
+	uri := "groups"
+	err := c.RequestResource("POST", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// ListGroups ...
+func (c *Client) ListGroups() ([]Group, error) {
+	resource := []Group{}
+	uri := "groups"
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// GetGroup ...
+func (c *Client) GetGroup(id string) (*Group, error) {
+	resource := Group{}
+	uri := path.Join("groups", id)
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UpdateGroup ...
+func (c *Client) UpdateGroup(id string, params *UpdateGroupParams) (*Group, error) {
+	resource := Group{}
+	uri := path.Join("groups", id)
+	err := c.RequestResource("PUT", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// CreateIteration ...
+func (c *Client) CreateIteration(params *CreateIterationParams) (*Iteration, error) {
+	resource := Iteration{}
+	uri := path.Join("iterations")
+	err := c.RequestResource("POST", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// ListIterations ...
+func (c *Client) ListIterations() ([]Iteration, error) {
+	resource := []Iteration{}
+	uri := path.Join("iterations")
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// GetIteration ...
+func (c *Client) GetIteration(id int) (*Iteration, error) {
+	resource := Iteration{}
+	uri := path.Join("iterations", itoa(id))
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UpdateIteration ...
+func (c *Client) UpdateIteration(id int, params *UpdateIterationParams) (*Iteration, error) {
+	resource := Iteration{}
+	uri := path.Join("iterations", itoa(id))
+	err := c.RequestResource("PUT", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// DeleteIteration ...
+func (c *Client) DeleteIteration(id int) error {
+	uri := path.Join("iterations", itoa(id))
+	return c.RequestResource("DELETE", nil, uri, nil)
+}
+
+// ListIterationStories lists the stories in an iteration. The API only
+// returns the slim version of each story here.
+func (c *Client) ListIterationStories(id int, opts ...ListOptions) ([]StorySlim, error) {
+	resource := []StorySlim{}
+	uri := listEndpoint(path.Join("iterations", itoa(id), "stories"), opts)
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
 // CreateLabel ...
 func (c *Client) CreateLabel(params *CreateLabelParams) (*Label, error) {
 	resource := Label{}
@@ -450,6 +826,107 @@ func (c *Client) GetMember(uuid string) (*Member, error) {
 	return &resource, nil
 }
 
+// GetCurrentMember returns information about the member that owns the
+// client's AuthToken, including the workspace they belong to.
+func (c *Client) GetCurrentMember() (*MemberInfo, error) {
+	resource := MemberInfo{}
+	uri := "member"
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// CurrentMember is GetCurrentMember, but the result is kept on the
+// client after the first successful call so the features that need it
+// don't each fetch it again. It's safe to call concurrently; callers
+// wait for a fetch that's already in flight rather than making their
+// own. Call InvalidateCurrentMember if the token or member changes.
+func (c *Client) CurrentMember() (*MemberInfo, error) {
+	c.memberMu.Lock()
+	defer c.memberMu.Unlock()
+	if c.member == nil {
+		member, err := c.GetCurrentMember()
+		if err != nil {
+			return nil, err
+		}
+		c.member = member
+	}
+	member := *c.member
+	return &member, nil
+}
+
+// InvalidateCurrentMember forgets the member remembered by
+// CurrentMember, so the next call fetches it again.
+func (c *Client) InvalidateCurrentMember() {
+	c.setCurrentMember(nil)
+}
+
+func (c *Client) setCurrentMember(member *MemberInfo) {
+	c.memberMu.Lock()
+	defer c.memberMu.Unlock()
+	c.member = member
+}
+
+// WorkspaceSlug returns the URL slug of the client's workspace, from
+// CurrentMember.
+func (c *Client) WorkspaceSlug() (string, error) {
+	member, err := c.CurrentMember()
+	if err != nil {
+		return "", err
+	}
+	return member.Workspace.URLSlug, nil
+}
+
+// StoryURL returns the link to a story in the Clubhouse app, without
+// having to fetch the story for its AppURL.
+func (c *Client) StoryURL(id int) (string, error) {
+	return c.appURL("story", id)
+}
+
+// EpicURL returns the link to an epic in the Clubhouse app, without
+// having to fetch the epic for its AppURL.
+func (c *Client) EpicURL(id int) (string, error) {
+	return c.appURL("epic", id)
+}
+
+// CachedStoryURL is StoryURL using the workspace slug from an earlier
+// call to CurrentMember, VerifyToken or WorkspaceSlug. It never makes a
+// request, and returns "" if the slug hasn't been fetched yet, so it's
+// for adding links to results and reports.
+func (c *Client) CachedStoryURL(id int) string {
+	return c.cachedAppURL("story", id)
+}
+
+// CachedEpicURL is EpicURL, but like CachedStoryURL it only uses a
+// workspace slug that's already been fetched.
+func (c *Client) CachedEpicURL(id int) string {
+	return c.cachedAppURL("epic", id)
+}
+
+func (c *Client) appURL(kind string, id int) (string, error) {
+	slug, err := c.WorkspaceSlug()
+	if err != nil {
+		return "", err
+	}
+	return appURL(c.appRootURL(), slug, kind, id), nil
+}
+
+func (c *Client) cachedAppURL(kind string, id int) string {
+	c.memberMu.Lock()
+	member := c.member
+	c.memberMu.Unlock()
+	if member == nil || member.Workspace.URLSlug == "" {
+		return ""
+	}
+	return appURL(c.appRootURL(), member.Workspace.URLSlug, kind, id)
+}
+
+func appURL(root, slug, kind string, id int) string {
+	return root + path.Join(slug, kind, itoa(id))
+}
+
 // CreateMilestone ...
 func (c *Client) CreateMilestone(params *CreateMilestoneParams) (*Milestone, error) {
 	resource := Milestone{}
@@ -500,6 +977,19 @@ func (c *Client) DeleteMilestone(id int) error {
 	return c.RequestResource("DELETE", nil, uri, nil)
 }
 
+// ListMilestoneEpics lists the epics in a milestone. Together with
+// ListEpicStories it walks the milestone, epic, story hierarchy without
+// listing every epic in the workspace.
+func (c *Client) ListMilestoneEpics(milestoneID int, opts ...ListOptions) ([]Epic, error) {
+	resource := []Epic{}
+	uri := listEndpoint(path.Join("milestones", itoa(milestoneID), "epics"), opts)
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
 // CreateProject ...
 func (c *Client) CreateProject(params *CreateProjectParams) (*Project, error) {
 	resource := Project{}
@@ -572,8 +1062,16 @@ func (c *Client) GetRepository(id int) (*Repository, error) {
 	return &resource, nil
 }
 
-// CreateStory ...
+// CreateStory creates a story, after applying the client's Conventions
+// for its project.
 func (c *Client) CreateStory(params *CreateStoryParams) (*Story, error) {
+	if c.Conventions != nil {
+		applied, err := c.Conventions.Apply(*params)
+		if err != nil {
+			return nil, err
+		}
+		params = &applied
+	}
 	resource := Story{}
 	uri := path.Join("stories")
 	err := c.RequestResource("POST", &resource, uri, params)
@@ -587,11 +1085,25 @@ type createStoriesParam struct {
 	Stories []CreateStoryParams `json:"stories"`
 }
 
-// CreateStories ...
+// CreateStories creates stories with the bulk endpoint, after applying
+// the client's Conventions to each.
 func (c *Client) CreateStories(plist []CreateStoryParams) ([]StorySlim, error) {
+	if c.Conventions != nil {
+		applied := make([]CreateStoryParams, len(plist))
+		for i, p := range plist {
+			var err error
+			if applied[i], err = c.Conventions.Apply(p); err != nil {
+				return nil, err
+			}
+		}
+		plist = applied
+	}
 	resource := []StorySlim{}
 	uri := path.Join("stories", "bulk")
 	params := createStoriesParam{Stories: plist}
+	if err := checkPayload(uri, len(plist), params); err != nil {
+		return nil, err
+	}
 	err := c.RequestResource("POST", &resource, uri, params)
 	if err != nil {
 		return nil, err
@@ -621,6 +1133,106 @@ func (c *Client) UpdateStory(id int, params *UpdateStoryParams) (*Story, error)
 	return &resource, nil
 }
 
+// CreateStoryComment ...
+func (c *Client) CreateStoryComment(storyID int, params *CreateCommentParams) (*Comment, error) {
+	resource := Comment{}
+	uri := path.Join("stories", itoa(storyID), "comments")
+	err := c.RequestResource("POST", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UpdateStoryComment ...
+func (c *Client) UpdateStoryComment(
+	storyID int,
+	commentID int,
+	params *UpdateCommentParams,
+) (*Comment, error) {
+	resource := Comment{}
+	uri := path.Join("stories", itoa(storyID), "comments", itoa(commentID))
+	err := c.RequestResource("PUT", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// CreateStoryReaction adds an emoji reaction, e.g. ":thumbsup:", to a
+// story comment. It returns all the reactions on the comment.
+func (c *Client) CreateStoryReaction(
+	storyID int,
+	commentID int,
+	params *CreateReactionParams,
+) ([]Reaction, error) {
+	resource := []Reaction{}
+	uri := path.Join("stories", itoa(storyID), "comments", itoa(commentID), "reactions")
+	err := c.RequestResource("POST", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// DeleteStoryReaction removes the current member's emoji reaction from
+// a story comment.
+func (c *Client) DeleteStoryReaction(storyID, commentID int, params *DeleteReactionParams) error {
+	uri := path.Join("stories", itoa(storyID), "comments", itoa(commentID), "reactions")
+	return c.RequestResource("DELETE", nil, uri, params)
+}
+
+// CreateTask adds a task to a story.
+func (c *Client) CreateTask(storyID int, params *CreateTaskParams) (*Task, error) {
+	resource := Task{}
+	uri := path.Join("stories", itoa(storyID), "tasks")
+	err := c.RequestResource("POST", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// GetTask ...
+func (c *Client) GetTask(storyID, taskID int) (*Task, error) {
+	resource := Task{}
+	uri := path.Join("stories", itoa(storyID), "tasks", itoa(taskID))
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UpdateTask ...
+func (c *Client) UpdateTask(storyID, taskID int, params *UpdateTaskParams) (*Task, error) {
+	resource := Task{}
+	uri := path.Join("stories", itoa(storyID), "tasks", itoa(taskID))
+	err := c.RequestResource("PUT", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// DeleteTask ...
+func (c *Client) DeleteTask(storyID, taskID int) error {
+	uri := path.Join("stories", itoa(storyID), "tasks", itoa(taskID))
+	return c.RequestResource("DELETE", nil, uri, nil)
+}
+
+// GetStoryHistory returns the list of changes made to a story, oldest
+// first.
+func (c *Client) GetStoryHistory(storyID int) ([]History, error) {
+	resource := []History{}
+	uri := path.Join("stories", itoa(storyID), "history")
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
 // DeleteStory ...
 func (c *Client) DeleteStory(id int) error {
 	uri := path.Join("stories", itoa(id))
@@ -635,6 +1247,9 @@ type deleteStoriesParam struct {
 func (c *Client) DeleteStories(ids []int) error {
 	uri := path.Join("stories", "bulk")
 	params := deleteStoriesParam{StoryIDs: ids}
+	if err := checkPayload(uri, len(ids), params); err != nil {
+		return err
+	}
 	return c.RequestResource("DELETE", nil, uri, params)
 }
 
@@ -642,6 +1257,9 @@ func (c *Client) DeleteStories(ids []int) error {
 func (c *Client) UpdateStories(params *UpdateStoriesParams) ([]StorySlim, error) {
 	resource := []StorySlim{}
 	uri := path.Join("stories", "bulk")
+	if err := checkPayload(uri, len(params.StoryIDs), params); err != nil {
+		return nil, err
+	}
 	err := c.RequestResource("PUT", &resource, uri, params)
 	if err != nil {
 		return nil, err
@@ -660,31 +1278,171 @@ func (c *Client) SearchStories(params *SearchParams) (*SearchResults, error) {
 	return &resource, nil
 }
 
-// SearchStoriesAll ...
+// SearchEpics runs a search for epics and returns the first page of
+// results. The search operators are the same as for stories, though
+// story-only ones like estimate: match nothing.
+func (c *Client) SearchEpics(params *SearchParams) (*EpicSearchResults, error) {
+	resource := EpicSearchResults{}
+	uri := path.Join("search", "epics")
+	err := c.RequestResource("GET", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// SearchEpicsAll follows every page of an epic search and returns all
+// the results. Like SearchStoriesAll, an epic that shows up on more than
+// one page is returned once, where it first appeared, with the data
+// from the last page it was seen on.
+func (c *Client) SearchEpicsAll(params *SearchParams) ([]EpicSearch, error) {
+	collected := []EpicSearch{}
+	seen := map[int]int{}
+	progress := c.trackProgress("SearchEpicsAll", 0)
+
+	it := c.Epics(context.Background(), params)
+	for it.NextPage() {
+		for _, epic := range it.Page() {
+			i, ok := seen[epic.ID]
+			if !ok {
+				seen[epic.ID] = len(collected)
+				collected = append(collected, epic)
+				continue
+			}
+			c.logf(LogWarn, "", "", "SearchEpicsAll: epic %d seen more than once", epic.ID)
+			if c.EpicSearchCollisionHandler != nil {
+				c.EpicSearchCollisionHandler(collected[i], epic)
+			}
+			collected[i] = epic
+		}
+		progress.setTotal(it.Total())
+		progress.step(len(it.Page()), "search/epics")
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return collected, nil
+}
+
+// Search runs a search for both stories and epics, returning the first
+// page of each. Use SearchStoriesAll or SearchEpicsAll to get the rest.
+func (c *Client) Search(params *SearchParams) (*CombinedSearchResults, error) {
+	resource := CombinedSearchResults{}
+	uri := path.Join("search")
+	err := c.RequestResource("GET", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// SearchCollisionHandler is called when a story shows up on more than
+// one page of search results, which happens when stories change while
+// the pages are being fetched. earlier is the copy that was kept so far
+// and later is the one replacing it.
+type SearchCollisionHandler func(earlier, later StorySearch)
+
+// EpicSearchCollisionHandler is called when an epic shows up on more
+// than one page of SearchEpicsAll's results.
+type EpicSearchCollisionHandler func(earlier, later EpicSearch)
+
+// SearchStoriesAll follows every page of a search and returns all the
+// results. Stories that move between pages while they're being fetched
+// can show up more than once; they're returned once, where they first
+// appeared, with the data from the last page they were seen on.
 func (c *Client) SearchStoriesAll(params *SearchParams) ([]StorySearch, error) {
 	collected := []StorySearch{}
+	seen := map[int]int{}
+	progress := c.trackProgress("SearchStoriesAll", 0)
+
+	it := c.Stories(context.Background(), params)
+	for it.NextPage() {
+		for _, story := range it.Page() {
+			i, ok := seen[story.ID]
+			if !ok {
+				seen[story.ID] = len(collected)
+				collected = append(collected, story)
+				continue
+			}
+			c.logf(LogWarn, "", "", "SearchStoriesAll: story %d seen more than once", story.ID)
+			if c.SearchCollisionHandler != nil {
+				c.SearchCollisionHandler(collected[i], story)
+			}
+			collected[i] = story
+		}
+		progress.setTotal(it.Total())
+		progress.step(len(it.Page()), "search/stories")
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return collected, nil
+}
+
+// nextPageToken extracts the "next" token from the URL the search
+// endpoints return.
+func nextPageToken(next string) (string, error) {
+	// the clubhouse API returns the whole URL to use as the "next"
+	// token. unfortunately, that doesn't really work for us, so we
+	// parse the URL and extract just the "next" query var from it
+	urlparts, err := url.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("error parsing next page url %s", err)
+	}
+	return urlparts.Query().Get("next"), nil
+}
 
+// ErrSearchTimeout is returned by SearchStoriesEventually when the
+// expected number of results didn't show up before the deadline.
+var ErrSearchTimeout = fmt.Errorf("clubhouse: search timed out waiting for results")
+
+// Backoff bounds for SearchStoriesEventually.
+var (
+	searchPollMin = 1 * time.Second
+	searchPollMax = 15 * time.Second
+)
+
+// SearchStoriesEventually runs a search repeatedly until at least
+// expectAtLeast results are found or waitFor has elapsed.
+//
+// Clubhouse indexes stories asynchronously, so searching for a story
+// right after creating it will often come back empty. This polls with
+// an exponential backoff to smooth over that lag. If the deadline
+// passes, the last page of results is returned along with
+// ErrSearchTimeout.
+func (c *Client) SearchStoriesEventually(
+	params *SearchParams,
+	waitFor time.Duration,
+	expectAtLeast int,
+) (*SearchResults, error) {
+	clock := c.clock()
+	deadline := clock.Now().Add(waitFor)
+	wait := searchPollMin
 	for {
-		page, err := c.SearchStories(params)
+		results, err := c.SearchStories(params)
 		if err != nil {
 			return nil, err
 		}
-		collected = append(collected, page.Data...)
-		if page.Next == "" {
-			break
+		if results.Total >= expectAtLeast {
+			return results, nil
 		}
 
-		// the clubhouse API returns the whole URL to use as the "next"
-		// token. unfortunately, that doesn't really work for us, so we
-		// parse the URL and extract just the "next" query var from it
-		urlparts, err := url.Parse(page.Next)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing next page url %s", err)
+		remaining := deadline.Sub(clock.Now())
+		if remaining <= 0 {
+			return results, ErrSearchTimeout
+		}
+		if wait > remaining {
+			wait = remaining
+		}
+		c.logf(LogDebug, "", "", "SearchStoriesEventually: got %d of %d, waiting %s",
+			results.Total, expectAtLeast, wait)
+		<-clock.After(wait)
+
+		wait *= 2
+		if wait > searchPollMax {
+			wait = searchPollMax
 		}
-		next := urlparts.Query().Get("next")
-		params.Next = next
 	}
-	return collected, nil
 }
 
 // CreateStoryLink ...
@@ -803,12 +1561,29 @@ func (c *Client) DeleteLinkedFile(id int) error {
 type ErrClientRequest struct {
 	Err          error
 	Method       string
+	Endpoint     string
 	URL          string
 	Request      *http.Request
 	Response     *http.Response
 	RequestBody  []byte
 	ResponseBody []byte
 	Stage        ErrStage
+
+	// RetryAfter is how long a 429 response's Retry-After header asked
+	// the client to wait, or zero.
+	RetryAfter time.Duration
+}
+
+// IsTooManyRequests reports whether err is a 429 from the API, and how
+// long it asked the client to wait, e.g. for callers that set Retries
+// to zero and back off themselves. retryAfter is zero if the response
+// didn't say.
+func IsTooManyRequests(err error) (retryAfter time.Duration, ok bool) {
+	e, ok := err.(ErrClientRequest)
+	if !ok || e.Err != ErrTooManyRequests {
+		return 0, false
+	}
+	return e.RetryAfter, true
 }
 
 // ErrStage describes the stage at which a ErrClientRequest occured.
@@ -826,8 +1601,65 @@ func (e ErrClientRequest) Error() string {
 	return fmt.Sprintf("clubhouse client request error: %s %s: %s", e.Method, e.URL, e.Err)
 }
 
-type errMessage struct {
+// Unwrap returns Err.
+func (e ErrClientRequest) Unwrap() error {
+	return e.Err
+}
+
+// ErrValidation is the error for a 400 or 422 response whose body
+// explains what was wrong. Err is ErrSchemaMismatch or
+// ErrUnprocessable, and Errors maps each rejected param to the details
+// the API gave, as decoded from JSON.
+//
+// ErrValidation takes the place of the plain ErrResponse in
+// ErrClientRequest.Err, so check for those with errors.Is rather than
+// ==, e.g. errors.Is(err, ErrUnprocessable).
+type ErrValidation struct {
+	Err     ErrResponse
 	Message string
+	Errors  map[string]interface{}
+}
+
+func (e ErrValidation) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Err, e.Message)
+	for _, field := range e.Fields() {
+		msg += fmt.Sprintf("; %s: %v", field, e.Errors[field])
+	}
+	return msg
+}
+
+// Unwrap returns Err, so errors.Is matches it.
+func (e ErrValidation) Unwrap() error {
+	return e.Err
+}
+
+// Fields returns the names of the rejected params, sorted.
+func (e ErrValidation) Fields() []string {
+	fields := []string{}
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// validationError reads the body of a 400 or 422 response. Bodies it
+// can't make sense of leave err as it is.
+func validationError(err ErrResponse, respContent []byte) error {
+	body := struct {
+		Message string
+		Errors  json.RawMessage
+	}{}
+	if json.Unmarshal(respContent, &body) != nil {
+		return err
+	}
+	e := ErrValidation{Err: err, Message: body.Message}
+	// errors is usually an object of params, but don't lose the
+	// message over it if it isn't
+	if json.Unmarshal(body.Errors, &e.Errors) != nil || len(e.Errors) == 0 {
+		e.Errors = nil
+	}
+	return e
 }
 
 // HTTPRequest makes an HTTP request to the Clubhouse API.
@@ -848,35 +1680,221 @@ type errMessage struct {
 //
 // If client is missing AuthToken, this method will panic.
 //
-//
 // Error Handling:
 //
 // HTTPRequest encapsulates any internal errors in ErrClientRequest. The
 // original error can be extracted from the Err field of the
-// ErrClientRequest instance.
+// ErrClientRequest instance, or matched with errors.Is and errors.As.
 func (c *Client) HTTPRequest(
 	method string,
 	endpoint string,
 	content []byte,
 	header *http.Header,
+) ([]byte, error) {
+	return c.httpRequest(context.Background(), requestNormal, method, endpoint, content, header)
+}
+
+// retryBackoff is how long to wait before the first retry. It doubles
+// after every attempt.
+var retryBackoff = 500 * time.Millisecond
+
+// requestMode says which parts of httpRequest a request skips.
+type requestMode int
+
+const (
+	requestNormal requestMode = iota
+	// requestDirect skips the Cache and retries, for checks that need
+	// an answer from the API right away. The Policy still applies.
+	requestDirect
+)
+
+// httpRequest makes the request, serving GETs from c.Cache when
+// possible and retrying up to c.Retries times when the request couldn't
+// be sent or the server had an error.
+func (c *Client) httpRequest(
+	ctx context.Context,
+	mode requestMode,
+	method string,
+	endpoint string,
+	content []byte,
+	header *http.Header,
 ) ([]byte, error) {
 	// finish setup or panic if the client isn't configured correctly
 	c.checkSetup()
 
-	url, err := c.makeURL(endpoint)
+	if c.Policy != nil {
+		if err := c.Policy.Check(method, endpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	if mode == requestDirect {
+		return c.retryHTTPRequest(ctx, 0, method, endpoint, content, header)
+	}
+	if c.Cache != nil && method == "GET" {
+		api := string(c.Flavor) + " " + c.RootURL + " " + c.Version
+		key := cacheKey(c.AuthToken, api, method, endpoint, content)
+		if cached, ok := c.Cache.Get(key); ok {
+			c.logf(LogDebug, method, endpoint, "served from cache")
+			c.counters.cacheLookup(true)
+			return cached, nil
+		}
+		c.counters.cacheLookup(false)
+		resp, err := c.retryHTTPRequest(ctx, c.Retries, method, endpoint, content, header)
+		if err == nil {
+			if cerr := c.Cache.Set(key, resp, c.CacheTTL); cerr != nil {
+				c.logf(LogWarn, method, endpoint, "could not write cache: %s", cerr)
+			}
+		}
+		return resp, err
+	}
+	return c.retryHTTPRequest(ctx, c.Retries, method, endpoint, content, header)
+}
+
+func (c *Client) retryHTTPRequest(
+	ctx context.Context,
+	retries int,
+	method string,
+	endpoint string,
+	content []byte,
+	header *http.Header,
+) ([]byte, error) {
+	wait := retryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.doHTTPRequest(ctx, method, endpoint, content, header)
+		c.counters.request(err)
+		c.recordExpvar(method, endpoint, err)
+		if err == nil || attempt >= retries || !retryable(method, err) {
+			return resp, err
+		}
+		c.logf(LogWarn, method, endpoint, "retrying after error: %s", err)
+		c.counters.retry()
+		delay := wait
+		if e, ok := err.(ErrClientRequest); ok && e.RetryAfter > delay {
+			delay = e.RetryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-c.clock().After(delay):
+		}
+		wait *= 2
+	}
+}
+
+// retryable reports whether a request that failed with err is worth
+// trying again. Only idempotent requests are retried after they may
+// have reached the server: a POST that timed out or got a 502 might
+// still have created something, and sending it again would create it
+// twice. POSTs are only retried when they never went out, or were
+// turned away with a 429 before anything was done.
+func retryable(method string, err error) bool {
+	e, ok := err.(ErrClientRequest)
+	if !ok {
+		return false
+	}
+	if !idempotent(method) {
+		if e.Stage == ErrStageResponse {
+			return e.Err == ErrTooManyRequests
+		}
+		return e.Stage == ErrStageSendRequest && notSent(e.Err)
+	}
+	switch e.Stage {
+	case ErrStageSendRequest:
+		return true
+	case ErrStageResponse:
+		return e.Response != nil && (e.Response.StatusCode >= 500 || e.Response.StatusCode == 429)
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// notSent reports whether err from the HTTP client happened before the
+// request could reach the server, i.e. while connecting.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (c *Client) doHTTPRequest(
+	ctx context.Context,
+	method string,
+	endpoint string,
+	content []byte,
+	header *http.Header,
+) ([]byte, error) {
+	req, resp, err := c.sendHTTPRequest(ctx, method, endpoint, content, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respContent, err := c.readResponseBody(resp)
 	if err != nil {
 		return nil, ErrClientRequest{
-			Err:    err,
-			URL:    url,
-			Method: method,
-			Stage:  ErrStagePreRequest,
+			Err:          err,
+			Endpoint:     endpoint,
+			URL:          req.URL.String(),
+			Method:       method,
+			Request:      req,
+			RequestBody:  content,
+			Response:     resp,
+			ResponseBody: respContent,
+			Stage:        ErrStageReadRequestBody,
+		}
+	}
+
+	c.logf(LogDebug, method, endpoint, "%s, %d bytes", resp.Status, len(respContent))
+	if err := responseError(resp, respContent); err != nil {
+		c.logf(LogDebug, method, endpoint, "response body: %s", respContent)
+		return nil, ErrClientRequest{
+			Err:          err,
+			Endpoint:     endpoint,
+			URL:          req.URL.String(),
+			Method:       method,
+			Request:      req,
+			RequestBody:  content,
+			Response:     resp,
+			ResponseBody: respContent,
+			Stage:        ErrStageResponse,
+			RetryAfter:   c.retryAfter(resp),
+		}
+	}
+	return respContent, nil
+}
+
+// sendHTTPRequest builds a request, waits for the rate limiter and sends
+// it. The caller must close the response body.
+func (c *Client) sendHTTPRequest(
+	ctx context.Context,
+	method string,
+	endpoint string,
+	content []byte,
+	header *http.Header,
+) (*http.Request, *http.Response, error) {
+	url, err := c.makeURL(endpoint)
+	if err != nil {
+		return nil, nil, ErrClientRequest{
+			Err:      err,
+			Endpoint: endpoint,
+			URL:      url,
+			Method:   method,
+			Stage:    ErrStagePreRequest,
 		}
 	}
 	body := bytes.NewBuffer(content)
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		return nil, ErrClientRequest{
+		return nil, nil, ErrClientRequest{
 			Err:         err,
+			Endpoint:    endpoint,
 			URL:         url,
 			Method:      method,
 			Request:     req,
@@ -889,73 +1907,161 @@ func (c *Client) HTTPRequest(
 		header = &http.Header{}
 		header.Add("Content-Type", "application/json")
 	}
-	req.Header = *header
+	req.Header = header.Clone()
+	req = req.WithContext(ctx)
+
+	// req is what errors and responses carry, so it only ever has a
+	// placeholder where the token goes; the real one goes on a copy
+	c.authenticate(req, redactedToken)
+	send := req.Clone(ctx)
+	c.authenticate(send, c.AuthToken)
 
 	// Take() will block until we can safely make the next request
 	// without going over the rate limit
+	waitStart := c.clock().Now()
 	c.Limiter.Take()
+	c.counters.rateLimitWait(c.clock().Now().Sub(waitStart))
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.roundTrip(send)
+	if o, ok := c.Limiter.(ResponseObserver); ok && err == nil {
+		o.Observe(resp)
+	}
 	if err != nil {
-		return nil, ErrClientRequest{
-			Err:         err,
-			URL:         url,
+		return nil, nil, ErrClientRequest{
+			Err:         scrubURLError(err, req),
+			Endpoint:    endpoint,
+			URL:         req.URL.String(),
 			Method:      method,
 			Request:     req,
 			RequestBody: content,
 			Stage:       ErrStageSendRequest,
 		}
 	}
+	resp.Request = req
+	return req, resp, nil
+}
 
-	respContent, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, ErrClientRequest{
-			Err:          err,
-			URL:          url,
-			Method:       method,
-			Request:      req,
-			RequestBody:  content,
-			Response:     resp,
-			ResponseBody: respContent,
-			Stage:        ErrStageReadRequestBody,
-		}
+// ErrResponseTooLarge is returned when a response body is bigger than
+// the client's MaxResponseBytes.
+type ErrResponseTooLarge struct {
+	Limit int64
+}
+
+func (e ErrResponseTooLarge) Error() string {
+	return fmt.Sprintf("clubhouse: response is larger than the %d byte limit", e.Limit)
+}
+
+// readResponseBody reads the whole body, up to MaxResponseBytes.
+func (c *Client) readResponseBody(resp *http.Response) ([]byte, error) {
+	if c.MaxResponseBytes <= 0 {
+		return ioutil.ReadAll(resp.Body)
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, c.MaxResponseBytes+1))
+	if err == nil && int64(len(content)) > c.MaxResponseBytes {
+		return content[:c.MaxResponseBytes], ErrResponseTooLarge{Limit: c.MaxResponseBytes}
 	}
+	return content, err
+}
 
+// responseError returns the error for an unsuccessful response, or nil.
+func responseError(resp *http.Response, respContent []byte) error {
+	var err error
 	switch resp.StatusCode {
 	case 400:
 		// returns body: {message: ..., errors: ...}
 		err = ErrSchemaMismatch
 	case 401:
 		err = ErrUnauthorized
+	case 403:
+		// the token is valid but its member can't do this
+		err = ErrForbidden
 	case 404:
 		err = ErrResourceNotFound
+	case 409:
+		err = ErrConflict
+	case 413:
+		err = ErrEntityTooLarge
 	case 422:
 		err = ErrUnprocessable
-	case 500:
-		err = ErrServerError
+	case 429:
+		err = ErrTooManyRequests
+	case 503:
+		err = ErrUnavailable
+		if isHTML(resp, respContent) {
+			err = ErrMaintenance
+		}
+	default:
+		if resp.StatusCode >= 500 {
+			err = ErrServerError
+		}
 	}
 
-	if err != nil {
-		if err == ErrUnprocessable || err == ErrSchemaMismatch {
-			message := errMessage{}
-			jsonerr := json.Unmarshal(respContent, &message)
-			if jsonerr == nil {
-				err = fmt.Errorf("%s: %s", err, message.Message)
-			}
+	if err == ErrUnprocessable || err == ErrSchemaMismatch {
+		err = validationError(err.(ErrResponse), respContent)
+	}
+	return err
+}
+
+// streamHTTPRequest is like httpRequest, but the body of a successful
+// response is returned unread so it can be decoded as it arrives,
+// without holding it all in memory. Responses aren't cached, requests
+// aren't retried, and MaxResponseBytes doesn't apply. The caller must
+// close the body.
+func (c *Client) streamHTTPRequest(ctx context.Context, method string, endpoint string) (io.ReadCloser, error) {
+	c.checkSetup()
+	if c.Policy != nil {
+		if err := c.Policy.Check(method, endpoint); err != nil {
+			return nil, err
 		}
+	}
 
-		return nil, ErrClientRequest{
-			Err:          err,
-			URL:          url,
+	req, resp, err := c.sendHTTPRequest(ctx, method, endpoint, nil, nil)
+	if err == nil && resp.StatusCode >= 400 {
+		// error bodies are small; read enough to report them
+		respContent, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		resp.Body.Close()
+		rerr := responseError(resp, respContent)
+		if rerr == nil {
+			rerr = ErrResponse{resp.StatusCode, resp.Status}
+		}
+		err = ErrClientRequest{
+			Err:          rerr,
+			Endpoint:     endpoint,
+			URL:          req.URL.String(),
 			Method:       method,
 			Request:      req,
-			RequestBody:  content,
 			Response:     resp,
 			ResponseBody: respContent,
 			Stage:        ErrStageResponse,
+			RetryAfter:   c.retryAfter(resp),
 		}
 	}
-	return respContent, nil
+	c.counters.request(err)
+	c.recordExpvar(method, endpoint, err)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// retryAfter returns the wait asked for by a 429 response, or zero.
+func (c *Client) retryAfter(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return 0
+	}
+	wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), c.clock().Now())
+	if !ok || wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// isHTML reports whether a response is an HTML page.
+func isHTML(resp *http.Response, body []byte) bool {
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<"))
 }
 
 // RequestResource ...
@@ -964,24 +2070,73 @@ func (c *Client) RequestResource(
 	resource interface{},
 	uri string,
 	params interface{},
+) error {
+	return c.requestResource(context.Background(), method, resource, uri, params)
+}
+
+// Call makes a request to an arbitrary API endpoint and decodes the
+// response into out. It's an escape hatch for reaching endpoints that
+// this package doesn't model yet, and has the same error handling as
+// the resource methods.
+//
+// endpoint is relative to the client's RootURL and Version, e.g.
+// "stories/123". If params is a url.Values it is sent as the query
+// string, merged with any query endpoint already has, otherwise it is
+// JSON encoded and sent as the request body. Either params or out can
+// be nil.
+func (c *Client) Call(
+	ctx context.Context,
+	method string,
+	endpoint string,
+	params interface{},
+	out interface{},
+) error {
+	if query, ok := params.(url.Values); ok {
+		if len(query) > 0 {
+			sep := "?"
+			if strings.Contains(endpoint, "?") {
+				sep = "&"
+			}
+			endpoint += sep + query.Encode()
+		}
+		params = nil
+	}
+	return c.requestResource(ctx, method, out, endpoint, params)
+}
+
+func (c *Client) requestResource(
+	ctx context.Context,
+	method string,
+	resource interface{},
+	uri string,
+	params interface{},
 ) error {
 	var (
 		body = []byte{}
 		err  error
 	)
 	if params != nil {
-		body, err = json.Marshal(params)
+		marshal := json.Marshal
+		if c.DeterministicJSON {
+			marshal = MarshalDeterministic
+		}
+		body, err = marshal(params)
 		if err != nil {
 			return fmt.Errorf("could not marshal params, %s", err)
 		}
-		debugf("%s %s body: %s", method, uri, string(body))
+		c.logf(LogDebug, method, uri, "request body: %s", body)
 	}
-	response, err := c.HTTPRequest(method, uri, body, nil)
+	response, err := c.httpRequest(ctx, requestNormal, method, uri, body, nil)
 	if err != nil {
 		return err
 	}
 	if resource != nil {
-		return json.Unmarshal(response, &resource)
+		if err := json.Unmarshal(response, &resource); err != nil {
+			return decodeError(uri, response, resource, err)
+		}
+		if c.UnknownEnumHandler != nil {
+			checkEnums(resource, c.UnknownEnumHandler)
+		}
 	}
 	return nil
 }
@@ -994,14 +2149,17 @@ func (c *Client) checkSetup() {
 		c.HTTPClient = DefaultHTTPClient
 	}
 	if c.Version == "" {
-		c.Version = DefaultVersion
+		c.Version = c.defaultVersion()
 	}
 	if c.RootURL == "" {
-		c.RootURL = DefaultRootURL
+		c.RootURL = c.defaultRootURL()
 	}
 	if c.Limiter == nil {
 		c.Limiter = DefaultLimiter
 	}
+	if c.BulkConcurrency <= 0 {
+		c.BulkConcurrency = DefaultBulkConcurrency
+	}
 }
 
 func (c *Client) makeURL(resource string) (string, error) {
@@ -1009,11 +2167,42 @@ func (c *Client) makeURL(resource string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("could not parse RootURL %s", err)
 	}
+	query := url.Values{}
+	if i := strings.Index(resource, "?"); i >= 0 {
+		query, err = url.ParseQuery(resource[i+1:])
+		if err != nil {
+			return "", fmt.Errorf("could not parse query %s", err)
+		}
+		resource = resource[:i]
+	}
 	urlparts.Path = path.Join(urlparts.Path, c.Version, resource)
-	urlparts.RawQuery = "token=" + c.AuthToken
+	urlparts.RawQuery = query.Encode()
 	return urlparts.String(), nil
 }
 
+// authenticate puts token on req: in the token header for the client's
+// Flavor, or with TokenInQuery, in the token query param.
+func (c *Client) authenticate(req *http.Request, token string) {
+	if !c.TokenInQuery {
+		req.Header.Set(c.tokenHeader(), token)
+		return
+	}
+	query := req.URL.Query()
+	query.Set("token", token)
+	req.URL.RawQuery = query.Encode()
+}
+
+// scrubURLError replaces the URL in errors from the HTTP client, which
+// has the token in it with TokenInQuery, with the URL of req.
+func scrubURLError(err error, req *http.Request) error {
+	if e, ok := err.(*url.Error); ok {
+		scrubbed := *e
+		scrubbed.URL = req.URL.String()
+		return &scrubbed
+	}
+	return err
+}
+
 type nullable []struct {
 	in   interface{}
 	out  **json.RawMessage
@@ -1033,16 +2222,3 @@ func (n nullable) Do() {
 		}
 	}
 }
-
-var debuglogger = log.New(os.Stderr, "debug:", log.Lshortfile)
-
-func debugf(format string, v ...interface{}) {
-	if os.Getenv("CLUBHOUSE_DEBUG") == "true" {
-		debuglogger.Printf(format, v...)
-	}
-}
-func debug(v ...interface{}) {
-	if os.Getenv("CLUBHOUSE_DEBUG") == "true" {
-		debuglogger.Println(v...)
-	}
-}
@@ -1,14 +1,22 @@
 package clubhouse
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,34 +40,15 @@ func TestMain(m *testing.M) {
 		log.Fatal("error setting environment", err)
 	}
 
-	members, err := c.ListMembers()
-	if err != nil {
-		log.Fatal("couldn't get member list", err)
+	if err := c.CheckSandbox(); err != nil {
+		log.Fatalf("**SAFETY GUARD** refusing to run tests: %s", err)
 	}
-	var (
-		activemembers = []Member{}
-		names         = []string{}
-		namelist      string
-	)
-
-	for _, m := range members {
-		if m.Disabled {
-			continue
-		}
-		activemembers = append(activemembers, m)
-		names = append(names, "\t- "+m.Profile.MentionName)
-	}
-
-	if len(activemembers) > 1 {
-		log.Fatalf(`
-**SAFETY GUARD**
-Refusing to continue on Clubhouse with more than 1 active member.
-Member count: %d
-Member list:
-%v`, len(members), namelist)
+	member, err := c.CurrentMember()
+	if err != nil {
+		log.Fatal("couldn't get current member", err)
 	}
 
-	memberUUID = activemembers[0].ID
+	memberUUID = member.ID
 	m.Run()
 }
 
@@ -188,6 +177,18 @@ func TestUpdateEpicParams(t *testing.T) {
 		Name:   "BeforeID",
 		Params: UpdateEpicParams{BeforeID: ID(113)},
 		Expect: `{"before_id":113}`,
+	}, {
+		Name:   "GroupID",
+		Params: UpdateEpicParams{GroupID: String("5f3c-aa")},
+		Expect: `{"group_id":"5f3c-aa"}`,
+	}, {
+		Name:   "GroupID: reset",
+		Params: UpdateEpicParams{GroupID: ResetGroupID},
+		Expect: `{"group_id":null}`,
+	}, {
+		Name:   "EpicStateID",
+		Params: UpdateEpicParams{EpicStateID: ID(500000021)},
+		Expect: `{"epic_state_id":500000021}`,
 	}, {
 		Name:   "Archived: unarchived",
 		Params: UpdateEpicParams{Archived: Unarchived},
@@ -550,6 +551,50 @@ func TestCRUDFiles(t *testing.T) {
 	})
 }
 
+func TestCreateIterationParams(t *testing.T) {
+	fieldtest{{
+		Name:   "required",
+		Params: CreateIterationParams{Name: "sprint", StartDate: "2019-01-07", EndDate: "2019-01-18"},
+		Expect: `{"end_date":"2019-01-18","name":"sprint","start_date":"2019-01-07"}`,
+	}, {
+		Name: "FollowerIDs",
+		Params: CreateIterationParams{
+			Name: "sprint", StartDate: "2019-01-07", EndDate: "2019-01-18",
+			FollowerIDs: []string{"abc"},
+		},
+		Expect: `{"end_date":"2019-01-18","follower_ids":["abc"],"name":"sprint","start_date":"2019-01-07"}`,
+	}, {
+		Name: "GroupIDs",
+		Params: CreateIterationParams{
+			Name: "sprint", StartDate: "2019-01-07", EndDate: "2019-01-18",
+			GroupIDs: []string{"def"},
+		},
+		Expect: `{"end_date":"2019-01-18","group_ids":["def"],"name":"sprint","start_date":"2019-01-07"}`,
+	},
+	}.Test(t)
+}
+
+func TestUpdateIterationParams(t *testing.T) {
+	fieldtest{{
+		Name:   "empty",
+		Params: UpdateIterationParams{},
+		Expect: `{}`,
+	}, {
+		Name:   "Description: empty",
+		Params: UpdateIterationParams{Description: EmptyString},
+		Expect: `{"description":""}`,
+	}, {
+		Name:   "Dates",
+		Params: UpdateIterationParams{StartDate: "2019-01-07", EndDate: "2019-01-18"},
+		Expect: `{"end_date":"2019-01-18","start_date":"2019-01-07"}`,
+	}, {
+		Name:   "GroupIDs",
+		Params: UpdateIterationParams{GroupIDs: []string{"def"}},
+		Expect: `{"group_ids":["def"]}`,
+	},
+	}.Test(t)
+}
+
 func TestCreateLabelParams(t *testing.T) {
 	fieldtest{{
 		Name:   "empty",
@@ -1162,6 +1207,14 @@ func TestUpdateStoriesParams(t *testing.T) {
 		Name:   "FollowerIDsRemove",
 		Params: UpdateStoriesParams{FollowerIDsRemove: []string{"unyo"}},
 		Expect: `{"follower_ids_remove":["unyo"]}`,
+	}, {
+		Name:   "IterationID",
+		Params: UpdateStoriesParams{IterationID: ID(7)},
+		Expect: `{"iteration_id":7}`,
+	}, {
+		Name:   "IterationID: reset",
+		Params: UpdateStoriesParams{IterationID: ResetID},
+		Expect: `{"iteration_id":null}`,
 	}, {
 		Name:   "LabelsAdd",
 		Params: UpdateStoriesParams{LabelsAdd: []CreateLabelParams{{Name: "hi"}}},
@@ -1206,6 +1259,14 @@ func TestUpdateStoryParams(t *testing.T) {
 		Name:   "AfterID",
 		Params: UpdateStoryParams{AfterID: Int(10)},
 		Expect: `{"after_id":10}`,
+	}, {
+		Name:   "GroupID",
+		Params: UpdateStoryParams{GroupID: String("5f3c-aa")},
+		Expect: `{"group_id":"5f3c-aa"}`,
+	}, {
+		Name:   "GroupID: reset",
+		Params: UpdateStoryParams{GroupID: ResetGroupID},
+		Expect: `{"group_id":null}`,
 	}, {
 		Name:   "Archived",
 		Params: UpdateStoryParams{Archived: Unarchived},
@@ -1293,6 +1354,31 @@ func TestUpdateStoryParams(t *testing.T) {
 	}}.Test(t)
 }
 
+func TestUpdateTaskParams(t *testing.T) {
+	fieldtest{{
+		Name:   "empty",
+		Params: UpdateTaskParams{},
+		Expect: `{}`,
+	}, {
+		Name:   "AfterID",
+		Params: UpdateTaskParams{AfterID: ID(2)},
+		Expect: `{"after_id":2}`,
+	}, {
+		Name:   "Complete: false",
+		Params: UpdateTaskParams{Complete: Incomplete},
+		Expect: `{"complete":false}`,
+	}, {
+		Name:   "Description",
+		Params: UpdateTaskParams{Description: String("do it")},
+		Expect: `{"description":"do it"}`,
+	}, {
+		Name:   "OwnerIDs",
+		Params: UpdateTaskParams{OwnerIDs: []string{"abc"}},
+		Expect: `{"owner_ids":["abc"]}`,
+	},
+	}.Test(t)
+}
+
 func TestCRUDStories(t *testing.T) {
 	c := makeClient()
 	proj, err := c.CreateProject(&CreateProjectParams{
@@ -1586,6 +1672,10 @@ func TestSearchQuery(t *testing.T) {
 		Name:   "Type",
 		Params: SearchQuery{Type: "bug"},
 		Expect: `"type:bug"`,
+	}, {
+		Name:   "UpdatedSince",
+		Params: SearchQuery{UpdatedSince: time.Date(2019, 4, 2, 23, 0, 0, 0, time.UTC)},
+		Expect: `"updated:2019-04-02..*"`,
 	}, {
 		Name: "Inversion: Epic",
 		Params: SearchQuery{Inversions: SearchQueryInversions{
@@ -1748,10 +1838,16 @@ func TestSearchStories(t *testing.T) {
 	deadlineStory := stories[0]
 	choreStory := stories[1]
 
-	// must sleep in order to give Clubhouse time to index the new
+	// must wait in order to give Clubhouse time to index the new
 	// stories, otherwise no results will be found
-	fmt.Println("SearchResults: sleeping for 60s")
-	time.Sleep(60 * time.Second)
+	_, err := c.SearchStoriesEventually(&SearchParams{
+		Query: &SearchQuery{
+			Project: proj.Name,
+		},
+	}, 90*time.Second, len(stories))
+	if err != nil {
+		t.Fatal("stories never showed up in search", err)
+	}
 
 	t.Run("search stories: all", func(t *testing.T) {
 		all, err := c.SearchStoriesAll(&SearchParams{
@@ -1816,6 +1912,132 @@ func TestSearchStories(t *testing.T) {
 	})
 }
 
+func TestSearchStoriesAllDuplicates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body SearchParams
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Next == "" {
+			w.Write([]byte(`{"data":[{"id":1,"name":"a"},{"id":2,"name":"b"}],` +
+				`"next":"/api/v2/search/stories?next=2"}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":2,"name":"b2"},{"id":3,"name":"c"}]}`))
+	}))
+	defer srv.Close()
+
+	collisions := []string{}
+	c := &Client{
+		AuthToken: "token",
+		RootURL:   srv.URL,
+		Limiter:   RateLimiter(0),
+		SearchCollisionHandler: func(earlier, later StorySearch) {
+			collisions = append(collisions, earlier.Name+"->"+later.Name)
+		},
+	}
+	stories, err := c.SearchStoriesAll(&SearchParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, s := range stories {
+		names = append(names, s.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b2", "c"}) {
+		t.Errorf("expected a, b2, c, got %v", names)
+	}
+	if !reflect.DeepEqual(collisions, []string{"b->b2"}) {
+		t.Errorf("expected one collision, got %v", collisions)
+	}
+}
+
+func TestResponseErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		header string
+		body   string
+		want   error
+	}{
+		{403, "application/json", `{"message":"nope"}`, ErrForbidden},
+		{409, "application/json", `{}`, ErrConflict},
+		{413, "application/json", `{}`, ErrEntityTooLarge},
+		{502, "text/html", `<html></html>`, ErrServerError},
+		{503, "application/json", `{}`, ErrUnavailable},
+		{503, "text/html", `<html>maintenance</html>`, ErrMaintenance},
+		{503, "", "\n<!DOCTYPE html>", ErrMaintenance},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tt.header != "" {
+				w.Header().Set("Content-Type", tt.header)
+			}
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+		c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+		_, err := c.GetStory(1)
+		srv.Close()
+		if e, ok := err.(ErrClientRequest); !ok || e.Err != tt.want {
+			t.Errorf("%d %s: expected %v, got %v", tt.status, tt.body, tt.want, err)
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{422, `{"message":"bad params","errors":{"name":"missing-required-key"}}`,
+			ErrValidation{ErrUnprocessable, "bad params", map[string]interface{}{"name": "missing-required-key"}}},
+		{400, `{"message":"schema","errors":{"estimate":["not an integer"]}}`,
+			ErrValidation{ErrSchemaMismatch, "schema", map[string]interface{}{"estimate": []interface{}{"not an integer"}}}},
+		{422, `{"message":"taken","errors":"a string"}`, ErrValidation{Err: ErrUnprocessable, Message: "taken"}},
+		{422, `not json`, ErrUnprocessable},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+		c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+		_, err := c.GetStory(1)
+		srv.Close()
+		if e, ok := err.(ErrClientRequest); !ok || !reflect.DeepEqual(e.Err, tt.want) {
+			t.Errorf("%d %s: expected %#v, got %#v", tt.status, tt.body, tt.want, err)
+		}
+	}
+
+	err := ErrValidation{ErrUnprocessable, "bad params", map[string]interface{}{"name": "missing", "estimate": "bad"}}
+	expect := "Unprocessable (422): bad params; estimate: bad; name: missing"
+	if err.Error() != expect {
+		t.Errorf("expected %q, got %q", expect, err.Error())
+	}
+
+	wrapped := ErrClientRequest{Err: err, Stage: ErrStageResponse}
+	if !errors.Is(wrapped, ErrUnprocessable) || errors.Is(wrapped, ErrSchemaMismatch) {
+		t.Error("expected errors.Is to see the ErrResponse behind the validation error")
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":1,"name":"a long enough name"}`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), MaxResponseBytes: 16}
+
+	_, err := c.GetStory(1)
+	if e, ok := err.(ErrClientRequest); !ok || e.Err != (ErrResponseTooLarge{Limit: 16}) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	c.MaxResponseBytes = 1024
+	if _, err := c.GetStory(1); err != nil {
+		t.Errorf("expected response under the limit to succeed, got %v", err)
+	}
+}
+
 func TestStoryLinkParams(t *testing.T) {
 	fieldtest{{
 		Name:   "empty",
@@ -1847,13 +2069,13 @@ func TestCRUDStoryLinks(t *testing.T) {
 	defer cleanup()
 
 	os.Setenv("WIRETAP_DEBUG", "true")
-	os.Setenv("CLUBHOUSE_DEBUG", "true")
+	c.Logger = NewStdLogger(log.New(os.Stderr, "debug: ", log.Lshortfile), LogDebug)
 	storylink1, err := c.CreateStoryLink(&CreateStoryLinkParams{
 		SubjectID: stories[0].ID,
 		ObjectID:  stories[1].ID,
 		Verb:      VerbBlocks,
 	})
-	os.Setenv("CLUBHOUSE_DEBUG", "false")
+	c.Logger = nil
 	os.Setenv("WIRETAP_DEBUG", "false")
 
 	if err != nil {
@@ -2145,6 +2367,59 @@ func TestCRUDLinkedFiles(t *testing.T) {
 	})
 }
 
+func TestMakeURL(t *testing.T) {
+	c := &Client{AuthToken: "tkn", RootURL: "https://example.com/api/", Version: "v2"}
+	for _, test := range []struct {
+		In     string
+		Expect string
+	}{
+		{"stories/1", "https://example.com/api/v2/stories/1"},
+		{"stories?page=2", "https://example.com/api/v2/stories?page=2"},
+	} {
+		out, err := c.makeURL(test.In)
+		if err != nil {
+			t.Fatal("did not expect error", err)
+		}
+		if out != test.Expect {
+			t.Errorf("%s != %s", out, test.Expect)
+		}
+	}
+}
+
+func TestTokenPlacement(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.WriteHeader(404)
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "s3cret", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	_, err := c.GetStory(1)
+	if got.Header.Get("Clubhouse-Token") != "s3cret" || got.URL.RawQuery != "" {
+		t.Errorf("expected the token in the header only, got %v %q", got.Header, got.URL.RawQuery)
+	}
+	e := err.(ErrClientRequest)
+	if strings.Contains(fmt.Sprintf("%v %v %v", e.URL, e.Request.Header, e.Response.Request.Header), "s3cret") {
+		t.Errorf("expected the token to be scrubbed from %+v", e)
+	}
+
+	c.TokenInQuery = true
+	_, err = c.GetStory(1)
+	if got.URL.Query().Get("token") != "s3cret" || got.Header.Get("Clubhouse-Token") != "" {
+		t.Errorf("expected the token in the query only, got %v %q", got.Header, got.URL.RawQuery)
+	}
+	if e := err.(ErrClientRequest); strings.Contains(e.URL+e.Request.URL.String(), "s3cret") {
+		t.Errorf("expected the token to be scrubbed from %s", e.URL)
+	}
+
+	srv.Close()
+	_, err = c.GetStory(1)
+	if err == nil || strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("expected the token to be scrubbed from %v", err)
+	}
+}
+
 /* helpers */
 
 func tempProjAndStories(t *testing.T) (*Project, []StorySlim, func()) {
@@ -2242,3 +2517,260 @@ func (ft fieldtest) Test(t *testing.T) {
 		})
 	}
 }
+
+func TestCurrentMember(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.Write([]byte(`{"id":"abc","mention_name":"brian","workspace2":{"url_slug":"acme"}}`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.CurrentMember(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	url, err := c.StoryURL(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://app.clubhouse.io/acme/story/12" {
+		t.Errorf("unexpected story URL %s", url)
+	}
+	if requests != 1 {
+		t.Errorf("expected the member to be fetched once, got %d requests", requests)
+	}
+
+	c.InvalidateCurrentMember()
+	if _, err := c.WorkspaceSlug(); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("expected invalidation to refetch, got %d requests", requests)
+	}
+}
+
+func TestGetCurrentMember(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/v2/member" {
+			w.WriteHeader(404)
+			return
+		}
+		if requests == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"abc","mention_name":"brian","name":"Brian",
+			"workspace2":{"url_slug":"acme","estimate_scale":[1,2,4]}}`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	if _, err := c.WorkspaceSlug(); err == nil {
+		t.Fatal("expected the failed fetch to be returned")
+	}
+	slug, err := c.WorkspaceSlug()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slug != "acme" || requests != 2 {
+		t.Errorf("expected a failed fetch not to be cached, got %q after %d requests", slug, requests)
+	}
+
+	member, err := c.GetCurrentMember()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := &MemberInfo{ID: "abc", MentionName: "brian", Name: "Brian",
+		Workspace: WorkspaceInfo{URLSlug: "acme", EstimateScale: []int{1, 2, 4}}}
+	if !reflect.DeepEqual(member, expect) {
+		t.Errorf("expected %+v, got %+v", expect, member)
+	}
+	if requests != 3 {
+		t.Errorf("expected GetCurrentMember to always fetch, got %d requests", requests)
+	}
+}
+
+func TestListMilestoneEpics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/milestones/4/epics" {
+			w.WriteHeader(404)
+			return
+		}
+		w.Write([]byte(`[{"id": 10, "milestone_id": 4}, {"id": 11, "milestone_id": 4}]`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	epics, err := c.ListMilestoneEpics(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(epics) != 2 || epics[0].ID != 10 || epics[1].MilestoneID != 4 {
+		t.Errorf("unexpected epics %+v", epics)
+	}
+}
+
+func TestEpicHealth(t *testing.T) {
+	var posted CreateHealthParams
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v3/epics/7/health":
+			json.NewDecoder(r.Body).Decode(&posted)
+			w.Write([]byte(`{"id": "h1", "epic_id": 7, "status": "At Risk", "text": "vendor slipped"}`))
+		case "GET /v3/epics/7/health-history":
+			w.Write([]byte(`[{"id": "h1", "status": "At Risk"}, {"id": "h0", "status": "On Track"}]`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Version: "v3", Limiter: RateLimiter(0)}
+
+	health, err := c.CreateEpicHealth(7, &CreateHealthParams{Status: HealthAtRisk, Text: "vendor slipped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posted.Status != HealthAtRisk || health.ID != "h1" || health.Status != HealthAtRisk {
+		t.Errorf("unexpected health %+v from %+v", health, posted)
+	}
+	history, err := c.ListEpicHealths(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[1].Status != HealthOnTrack {
+		t.Errorf("unexpected history %+v", history)
+	}
+}
+
+func TestEntityTemplates(t *testing.T) {
+	requests := []string{}
+	var created map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v3/entity-templates":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"id": "t1", "name": "bug report", "story_contents": {"story_type": "bug"}}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Version: "v3", Limiter: RateLimiter(0)}
+
+	template, err := c.CreateEntityTemplate(&CreateEntityTemplateParams{
+		Name:          "bug report",
+		StoryContents: CreateStoryContents{StoryType: StoryTypeBug},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.ID != "t1" || template.StoryContents.StoryType != StoryTypeBug {
+		t.Errorf("unexpected template %+v", template)
+	}
+	contents, _ := json.Marshal(created["story_contents"])
+	if string(contents) != `{"story_type":"bug"}` {
+		t.Errorf("unexpected story contents %s", contents)
+	}
+	if err := c.DisableStoryTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.EnableStoryTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"POST /v3/entity-templates", "PUT /v3/entity-templates/disable", "PUT /v3/entity-templates/enable"}
+	if !reflect.DeepEqual(requests, expect) {
+		t.Errorf("expected %v, got %v", expect, requests)
+	}
+}
+
+func TestStoryReactions(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/stories/1/comments/2/reactions" {
+			w.WriteHeader(404)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, r.Method+" "+string(body))
+		if r.Method == "POST" {
+			w.Write([]byte(`[{"emoji": ":thumbsup:", "permission_ids": ["abc"]}]`))
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	reactions, err := c.CreateStoryReaction(1, 2, &CreateReactionParams{Emoji: ":thumbsup:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reactions) != 1 || reactions[0].Emoji != ":thumbsup:" || reactions[0].PermissionIDs[0] != "abc" {
+		t.Errorf("unexpected reactions %+v", reactions)
+	}
+	if err := c.DeleteStoryReaction(1, 2, &DeleteReactionParams{Emoji: ":thumbsup:"}); err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{`POST {"emoji":":thumbsup:"}`, `DELETE {"emoji":":thumbsup:"}`}
+	if !reflect.DeepEqual(bodies, expect) {
+		t.Errorf("expected %q, got %q", expect, bodies)
+	}
+}
+
+func TestCall(t *testing.T) {
+	var got *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		if r.URL.Path == "/v2/missing" {
+			w.WriteHeader(404)
+			return
+		}
+		w.Write([]byte(`{"id":7,"name":"thing"}`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	ctx := context.Background()
+
+	var out struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	query := url.Values{"page_size": {"5"}}
+	if err := c.Call(ctx, "GET", "things?archived=true", query, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got.URL.Path != "/v2/things" || got.URL.RawQuery != "archived=true&page_size=5" || body != "" {
+		t.Errorf("unexpected request %s %s?%s %q", got.Method, got.URL.Path, got.URL.RawQuery, body)
+	}
+	if out.ID != 7 || out.Name != "thing" {
+		t.Errorf("expected the response to be decoded, got %+v", out)
+	}
+
+	if err := c.Call(ctx, "POST", "things", map[string]string{"name": "new"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got.Method != "POST" || body != `{"name":"new"}` {
+		t.Errorf("expected params as the body, got %s %q", got.Method, body)
+	}
+
+	err := c.Call(ctx, "GET", "missing", nil, &out)
+	if !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,351 @@
+// Package clubhousetest simulates a misbehaving Clubhouse API, so code
+// built on the client can test its retry and backoff handling without
+// the real API.
+//
+// A Transport sits in front of whatever answers requests in the test
+// (usually an httptest.Server's transport) and plays a Scenario: 429s
+// with Retry-After, added latency, and requests that fail before or
+// after they reach the server. It can also enforce a Quota the way
+// Clubhouse's rate limit does.
+//
+//	srv := httptest.NewServer(fake)
+//	sim := &clubhousetest.Transport{
+//		Next:     http.DefaultTransport,
+//		Scenario: clubhousetest.MustParseScenario(`
+//			GET /api/v2/stories 429 retry-after=1s x2
+//			POST /api/v2/stories drop x1
+//		`),
+//	}
+//	c := &clubhouse.Client{
+//		AuthToken:  "token",
+//		RootURL:    srv.URL + "/api/",
+//		HTTPClient: &http.Client{Transport: sim},
+//		Retries:    3,
+//	}
+package clubhousetest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+// Step is one line of a Scenario. It applies to the requests it
+// matches, up to Times of them.
+type Step struct {
+	// Match is "METHOD /path" or just "/path"; requests match when their
+	// method is the same and their path starts with the given one. Empty
+	// matches every request.
+	Match string
+
+	// Times is how many requests the step applies to. Zero means every
+	// matching request.
+	Times int
+
+	// Latency is waited before anything else happens.
+	Latency time.Duration
+
+	// Status, if set, is sent instead of passing the request on. For
+	// 429s, RetryAfter is sent as the Retry-After header if it's set.
+	Status     int
+	RetryAfter time.Duration
+
+	// Fail fails the request before it's sent, like a refused
+	// connection. Drop sends it and then fails, like a connection that
+	// breaks mid-request: the server has acted on it, but the client
+	// never sees the response.
+	Fail bool
+	Drop bool
+}
+
+func (s Step) matches(req *http.Request) bool {
+	if s.Match == "" {
+		return true
+	}
+	method, prefix := "", s.Match
+	if i := strings.Index(s.Match, " "); i >= 0 {
+		method, prefix = s.Match[:i], strings.TrimSpace(s.Match[i+1:])
+	}
+	if method != "" && method != req.Method {
+		return false
+	}
+	return strings.HasPrefix(req.URL.Path, prefix)
+}
+
+// Scenario is the misbehavior a Transport plays. For each request, the
+// first step that matches and hasn't been used up applies; requests no
+// step applies to are passed on untouched.
+type Scenario []Step
+
+// ParseScenario reads a scenario script. Each line is a step: what it
+// matches, then what it does.
+//
+//	# comments and blank lines are ignored
+//	GET /api/v2/stories 429 retry-after=2s x3
+//	POST /api/v2/stories drop x1
+//	* latency=200ms
+//	/api/v2/epics 503 x1
+//	PUT /api/v2/stories fail
+//
+// The match is "*" for every request, a path prefix, or a method and a
+// path prefix. The actions are a status code, retry-after=DURATION,
+// latency=DURATION, fail, drop, and xN for the number of requests the
+// step applies to; without xN it applies to all of them.
+func ParseScenario(script string) (Scenario, error) {
+	scenario := Scenario{}
+	scanner := bufio.NewScanner(strings.NewReader(script))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		step, err := parseStep(strings.Fields(line))
+		if err != nil {
+			return nil, fmt.Errorf("clubhousetest: line %d: %s", n, err)
+		}
+		scenario = append(scenario, step)
+	}
+	return scenario, scanner.Err()
+}
+
+// MustParseScenario is ParseScenario for scripts in tests, panicking if
+// the script is invalid.
+func MustParseScenario(script string) Scenario {
+	scenario, err := ParseScenario(script)
+	if err != nil {
+		panic(err)
+	}
+	return scenario
+}
+
+func parseStep(fields []string) (Step, error) {
+	step := Step{}
+	switch {
+	case fields[0] == "*":
+		fields = fields[1:]
+	case strings.HasPrefix(fields[0], "/"):
+		step.Match, fields = fields[0], fields[1:]
+	case len(fields) > 1 && strings.HasPrefix(fields[1], "/"):
+		step.Match, fields = fields[0]+" "+fields[1], fields[2:]
+	default:
+		return step, fmt.Errorf("expected *, a path, or a method and path, got %q", fields[0])
+	}
+	if len(fields) == 0 {
+		return step, errors.New("expected at least one action")
+	}
+	for _, f := range fields {
+		var err error
+		switch {
+		case f == "fail":
+			step.Fail = true
+		case f == "drop":
+			step.Drop = true
+		case strings.HasPrefix(f, "retry-after="):
+			step.RetryAfter, err = time.ParseDuration(strings.TrimPrefix(f, "retry-after="))
+		case strings.HasPrefix(f, "latency="):
+			step.Latency, err = time.ParseDuration(strings.TrimPrefix(f, "latency="))
+		case strings.HasPrefix(f, "x"):
+			step.Times, err = strconv.Atoi(f[1:])
+		default:
+			step.Status, err = strconv.Atoi(f)
+			if err == nil && (step.Status < 100 || step.Status > 599) {
+				err = fmt.Errorf("bad status %d", step.Status)
+			}
+		}
+		if err != nil {
+			return step, fmt.Errorf("bad action %q: %s", f, err)
+		}
+	}
+	return step, nil
+}
+
+// Quota is a rate limit: Limit requests every Window, counted from the
+// first request. Requests over the limit get a 429 with Retry-After set
+// to the time left in the window, and every response gets
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset.
+type Quota struct {
+	Limit  int
+	Window time.Duration
+}
+
+// ErrSimulated is the error for requests a Scenario fails or drops.
+var ErrSimulated = errors.New("clubhousetest: simulated connection failure")
+
+// Outcome is what a Transport did with a request.
+type Outcome string
+
+// Valid values for Outcome
+const (
+	OutcomePassed  Outcome = "passed"
+	OutcomeStatus  Outcome = "status"
+	OutcomeFailed  Outcome = "failed"
+	OutcomeDropped Outcome = "dropped"
+	OutcomeQuota   Outcome = "quota"
+)
+
+// Record is a request a Transport handled.
+type Record struct {
+	Method  string
+	Path    string
+	At      time.Time
+	Outcome Outcome
+	Status  int
+}
+
+// Transport is an http.RoundTripper that plays a Scenario, and enforces
+// a Quota if it has one, in front of Next.
+type Transport struct {
+	Next     http.RoundTripper
+	Scenario Scenario
+	Quota    *Quota
+
+	// Clock, if set, is used for latency and the quota window instead
+	// of clubhouse.RealClock.
+	Clock clubhouse.Clock
+
+	mu          sync.Mutex
+	used        map[int]int
+	windowStart time.Time
+	windowCount int
+	records     []Record
+}
+
+// Records returns every request the transport has handled, in order.
+func (t *Transport) Records() []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Record{}, t.records...)
+}
+
+func (t *Transport) clock() clubhouse.Clock {
+	if t.Clock == nil {
+		return clubhouse.RealClock
+	}
+	return t.Clock
+}
+
+// step returns the step that applies to req, using it up, or nil.
+func (t *Transport) step(req *http.Request) *Step {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.used == nil {
+		t.used = map[int]int{}
+	}
+	for i, s := range t.Scenario {
+		if !s.matches(req) || (s.Times > 0 && t.used[i] >= s.Times) {
+			continue
+		}
+		t.used[i]++
+		return &t.Scenario[i]
+	}
+	return nil
+}
+
+// RoundTrip applies the scenario and quota to req.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	record := Record{Method: req.Method, Path: req.URL.Path, Outcome: OutcomePassed}
+	defer func() {
+		t.mu.Lock()
+		t.records = append(t.records, record)
+		t.mu.Unlock()
+	}()
+
+	step := t.step(req)
+	if step != nil && step.Latency > 0 {
+		select {
+		case <-t.clock().After(step.Latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	record.At = t.clock().Now()
+
+	if step != nil && step.Fail {
+		record.Outcome = OutcomeFailed
+		return nil, ErrSimulated
+	}
+	header, retryAfter, ok := t.takeQuota(record.At)
+	if !ok {
+		record.Outcome, record.Status = OutcomeQuota, http.StatusTooManyRequests
+		return tooManyRequests(req, header, retryAfter), nil
+	}
+	if step != nil && step.Status != 0 {
+		record.Outcome, record.Status = OutcomeStatus, step.Status
+		if step.Status == http.StatusTooManyRequests {
+			return tooManyRequests(req, header, step.RetryAfter), nil
+		}
+		return response(req, step.Status, header, `{"message": "simulated error"}`), nil
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	record.Status = resp.StatusCode
+	if step != nil && step.Drop {
+		resp.Body.Close()
+		record.Outcome = OutcomeDropped
+		return nil, ErrSimulated
+	}
+	for k, v := range header {
+		resp.Header[k] = v
+	}
+	return resp, nil
+}
+
+// takeQuota counts a request against the quota. It returns the rate
+// limit headers, and if the request is over the limit, how long until
+// the window resets.
+func (t *Transport) takeQuota(now time.Time) (http.Header, time.Duration, bool) {
+	header := http.Header{}
+	if t.Quota == nil {
+		return header, 0, true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.windowStart.IsZero() || now.Sub(t.windowStart) >= t.Quota.Window {
+		t.windowStart, t.windowCount = now, 0
+	}
+	reset := t.windowStart.Add(t.Quota.Window)
+	ok := t.windowCount < t.Quota.Limit
+	if ok {
+		t.windowCount++
+	}
+	header.Set("X-RateLimit-Limit", strconv.Itoa(t.Quota.Limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(t.Quota.Limit-t.windowCount))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	return header, reset.Sub(now), ok
+}
+
+func tooManyRequests(req *http.Request, header http.Header, retryAfter time.Duration) *http.Response {
+	if retryAfter > 0 {
+		// round up, so clients never retry early
+		secs := (retryAfter + time.Second - 1) / time.Second
+		header.Set("Retry-After", strconv.Itoa(int(secs)))
+	}
+	return response(req, http.StatusTooManyRequests, header, `{"message": "Too many requests"}`)
+}
+
+func response(req *http.Request, status int, header http.Header, body string) *http.Response {
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
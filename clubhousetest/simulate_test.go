@@ -0,0 +1,149 @@
+package clubhousetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+func TestParseScenario(t *testing.T) {
+	scenario, err := ParseScenario(`
+		# comment
+		GET /api/v2/stories 429 retry-after=2s x3
+		POST /api/v2/stories drop x1
+		* latency=200ms
+		/api/v2/epics 503 fail
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := Scenario{
+		{Match: "GET /api/v2/stories", Status: 429, RetryAfter: 2 * time.Second, Times: 3},
+		{Match: "POST /api/v2/stories", Drop: true, Times: 1},
+		{Latency: 200 * time.Millisecond},
+		{Match: "/api/v2/epics", Status: 503, Fail: true},
+	}
+	if !reflect.DeepEqual(scenario, expect) {
+		t.Errorf("expected %+v, got %+v", expect, scenario)
+	}
+
+	for _, bad := range []string{"GET", "/x", "/x 99", "/x latency=soon", "stories 429"} {
+		if _, err := ParseScenario(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func simulated(t *testing.T, sim *Transport) (*clubhouse.Client, *int, func()) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`[]`))
+	}))
+	sim.Next = http.DefaultTransport
+	c := &clubhouse.Client{
+		AuthToken:  "token",
+		RootURL:    srv.URL + "/api/",
+		HTTPClient: &http.Client{Transport: sim},
+		Limiter:    clubhouse.RateLimiter(0),
+	}
+	return c, &hits, srv.Close
+}
+
+func TestTransportScenario(t *testing.T) {
+	sim := &Transport{Scenario: MustParseScenario(`
+		GET /api/v2/epics 429 retry-after=1s x1
+		GET /api/v2/epics 503 x1
+		POST /api/v2/epics drop x1
+		GET /api/v2/labels fail
+	`)}
+	c, hits, done := simulated(t, sim)
+	defer done()
+
+	if _, err := c.ListEpics(); !isStatus(err, 429) {
+		t.Errorf("expected a 429, got %v", err)
+	}
+	if _, err := c.ListEpics(); !isStatus(err, 503) {
+		t.Errorf("expected a 503, got %v", err)
+	}
+	if _, err := c.ListEpics(); err != nil {
+		t.Errorf("expected the scenario to be used up, got %v", err)
+	}
+	if _, err := c.CreateEpic(&clubhouse.CreateEpicParams{Name: "x"}); err == nil {
+		t.Error("expected the dropped request to fail")
+	}
+	if _, err := c.ListLabels(); err == nil {
+		t.Error("expected the failed request to fail")
+	}
+	if *hits != 2 {
+		t.Errorf("expected only the passed and dropped requests to reach the server, got %d", *hits)
+	}
+
+	outcomes := []Outcome{}
+	for _, r := range sim.Records() {
+		outcomes = append(outcomes, r.Outcome)
+	}
+	expect := []Outcome{OutcomeStatus, OutcomeStatus, OutcomePassed, OutcomeDropped, OutcomeFailed}
+	if !reflect.DeepEqual(outcomes, expect) {
+		t.Errorf("expected %v, got %v", expect, outcomes)
+	}
+}
+
+func TestTransportQuota(t *testing.T) {
+	clock := clubhouse.NewFakeClock(time.Unix(1500000000, 0))
+	sim := &Transport{Quota: &Quota{Limit: 2, Window: time.Minute}, Clock: clock}
+	c, _, done := simulated(t, sim)
+	defer done()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.ListEpics(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock.Advance(15 * time.Second)
+	_, err := c.ListEpics()
+	if !isStatus(err, 429) {
+		t.Fatalf("expected the quota to run out, got %v", err)
+	}
+	resp := err.(clubhouse.ErrClientRequest).Response
+	if resp.Header.Get("Retry-After") != "45" || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("unexpected headers %v", resp.Header)
+	}
+
+	clock.Advance(45 * time.Second)
+	if _, err := c.ListEpics(); err != nil {
+		t.Errorf("expected a new window, got %v", err)
+	}
+}
+
+func TestTransportLatency(t *testing.T) {
+	clock := clubhouse.NewFakeClock(time.Now())
+	sim := &Transport{Scenario: Scenario{{Latency: time.Second}}, Clock: clock}
+	c, _, done := simulated(t, sim)
+	defer done()
+
+	errs := make(chan error)
+	go func() {
+		_, err := c.ListEpics()
+		errs <- err
+	}()
+	clock.BlockUntil(1)
+	select {
+	case <-errs:
+		t.Fatal("expected the request to be delayed")
+	default:
+	}
+	clock.Advance(time.Second)
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func isStatus(err error, code int) bool {
+	e, ok := err.(clubhouse.ErrClientRequest)
+	return ok && e.Response != nil && e.Response.StatusCode == code
+}
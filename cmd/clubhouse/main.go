@@ -0,0 +1,169 @@
+// Command clubhouse has tools for working with a Clubhouse workspace.
+// The client is configured from the CLUBHOUSE_* environment variables.
+//
+// Usage:
+//
+//	clubhouse gen ids [-package name] [-o file]
+//	clubhouse run [-dry-run] [-var name=value]... file
+//	clubhouse story id...
+//
+// gen ids writes a Go file with constants for the IDs of the workspace's
+// projects, workflow states, labels, teams and groups.
+//
+// run runs the operations in a batch file and reports what was done; see
+// package batch for the file format.
+//
+// story prints stories with clubhouse.FormatStory, in color when the
+// output is a terminal.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/brianloveswords/clubhouse"
+	"github.com/brianloveswords/clubhouse/batch"
+)
+
+const usage = `usage:
+  clubhouse gen ids [-package name] [-o file]
+  clubhouse run [-dry-run] [-var name=value]... file
+  clubhouse story id...`
+
+func main() {
+	var err error
+	switch {
+	case len(os.Args) >= 3 && os.Args[1] == "gen" && os.Args[2] == "ids":
+		err = genIDs(os.Args[3:])
+	case len(os.Args) >= 2 && os.Args[1] == "run":
+		err = run(os.Args[2:])
+	case len(os.Args) >= 3 && os.Args[1] == "story":
+		err = stories(os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clubhouse:", err)
+		os.Exit(1)
+	}
+}
+
+func client() (*clubhouse.Client, error) {
+	cfg, err := clubhouse.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Client(), nil
+}
+
+func genIDs(args []string) error {
+	flags := flag.NewFlagSet("gen ids", flag.ExitOnError)
+	pkg := flags.String("package", "ids", "package name of the generated file")
+	out := flags.String("o", "", "file to write, instead of stdout")
+	flags.Parse(args)
+
+	c, err := client()
+	if err != nil {
+		return err
+	}
+	ids, err := c.GetIDConstants()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := clubhouse.GenerateIDs(&buf, *pkg, ids); err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(*out, buf.Bytes(), 0644)
+}
+
+// varFlags collects repeated -var name=value flags.
+type varFlags map[string]string
+
+func (v varFlags) String() string { return "" }
+
+func (v varFlags) Set(s string) error {
+	i := strings.Index(s, "=")
+	if i < 0 {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	v[s[:i]] = s[i+1:]
+	return nil
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would be done without changing anything")
+	vars := varFlags{}
+	flags.Var(vars, "var", "set a variable, replacing the file's (repeatable)")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("run: expected one file")
+	}
+
+	file, err := batch.Load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	c, err := client()
+	if err != nil {
+		return err
+	}
+	// fetch the workspace slug now so the report has links
+	if _, err := c.WorkspaceSlug(); err != nil {
+		return err
+	}
+	runner := &batch.Runner{Client: c, DryRun: *dryRun, Vars: vars}
+	results, err := runner.Run(file)
+	if reportErr := batch.WriteReport(os.Stdout, results); reportErr != nil {
+		return reportErr
+	}
+	return err
+}
+
+func stories(args []string) error {
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil {
+			return fmt.Errorf("story: bad ID %q", arg)
+		}
+		ids[i] = id
+	}
+
+	c, err := client()
+	if err != nil {
+		return err
+	}
+	opts := clubhouse.FormatOptions{}
+	if opts.Members, err = c.ListMembers(); err != nil {
+		return err
+	}
+	if opts.Workflows, err = c.ListWorkflows(); err != nil {
+		return err
+	}
+	if info, err := os.Stdout.Stat(); err == nil {
+		opts.Color = info.Mode()&os.ModeCharDevice != 0
+	}
+	for i, id := range ids {
+		story, err := c.GetStory(id)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(clubhouse.FormatStory(*story, opts))
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package clubhouse
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// StoryRef returns a reference to a story that the Clubhouse UI will
+// turn into a link, e.g. "[story #123]".
+func StoryRef(id int) string {
+	return fmt.Sprintf("[story #%d]", id)
+}
+
+// EpicRef returns a reference to an epic that the Clubhouse UI will
+// turn into a link, e.g. "[epic #123]".
+func EpicRef(id int) string {
+	return fmt.Sprintf("[epic #%d]", id)
+}
+
+// Mention returns an @-mention for a member.
+func Mention(m Member) string {
+	return "@" + m.Profile.MentionName
+}
+
+// CommentTemplate renders comment text from a text/template, with
+// helpers for referencing entities so the comments bots produce are
+// readable and linked instead of being full of raw IDs.
+//
+// The following functions are available inside the template:
+//
+//	story   a story ID, Story, StorySlim or StorySearch -> "[story #123]"
+//	epic    an epic ID or Epic -> "[epic #123]"
+//	mention a Member -> "@mention-name"
+//
+// For example:
+//
+//	{{story .Story}} was moved to {{.State}} by {{mention .Member}}
+type CommentTemplate struct {
+	tmpl *template.Template
+}
+
+// NewCommentTemplate parses text into a CommentTemplate.
+func NewCommentTemplate(text string) (*CommentTemplate, error) {
+	tmpl, err := template.New("comment").Funcs(template.FuncMap{
+		"story":   storyRefFunc,
+		"epic":    epicRefFunc,
+		"mention": Mention,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("NewCommentTemplate: %s", err)
+	}
+	return &CommentTemplate{tmpl: tmpl}, nil
+}
+
+// MustCommentTemplate is like NewCommentTemplate but panics if the
+// template can't be parsed.
+func MustCommentTemplate(text string) *CommentTemplate {
+	t, err := NewCommentTemplate(text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Render executes the template with data and returns the comment text.
+func (t *CommentTemplate) Render(data interface{}) (string, error) {
+	buf := bytes.Buffer{}
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("CommentTemplate: %s", err)
+	}
+	return buf.String(), nil
+}
+
+// Params executes the template with data and returns parameters ready
+// to be passed to one of the comment creation methods.
+func (t *CommentTemplate) Params(data interface{}) (*CreateCommentParams, error) {
+	text, err := t.Render(data)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateCommentParams{Text: text}, nil
+}
+
+func storyRefFunc(v interface{}) (string, error) {
+	switch s := v.(type) {
+	case int:
+		return StoryRef(s), nil
+	case Story:
+		return StoryRef(s.ID), nil
+	case *Story:
+		return StoryRef(s.ID), nil
+	case StorySlim:
+		return StoryRef(s.ID), nil
+	case *StorySlim:
+		return StoryRef(s.ID), nil
+	case StorySearch:
+		return StoryRef(s.ID), nil
+	case *StorySearch:
+		return StoryRef(s.ID), nil
+	}
+	return "", fmt.Errorf("story: can't make a reference from %T", v)
+}
+
+func epicRefFunc(v interface{}) (string, error) {
+	switch e := v.(type) {
+	case int:
+		return EpicRef(e), nil
+	case Epic:
+		return EpicRef(e.ID), nil
+	case *Epic:
+		return EpicRef(e.ID), nil
+	}
+	return "", fmt.Errorf("epic: can't make a reference from %T", v)
+}
@@ -0,0 +1,31 @@
+package clubhouse
+
+import "testing"
+
+func TestCommentTemplate(t *testing.T) {
+	tmpl := MustCommentTemplate(
+		"{{story .Story}} in {{epic .EpicID}} moved by {{mention .Member}}",
+	)
+	member := Member{Profile: Profile{MentionName: "brian"}}
+	text, err := tmpl.Render(map[string]interface{}{
+		"Story":  &Story{ID: 123},
+		"EpicID": 45,
+		"Member": member,
+	})
+	if err != nil {
+		t.Fatal("did not expect error", err)
+	}
+	expect := "[story #123] in [epic #45] moved by @brian"
+	if text != expect {
+		t.Errorf("%s != %s", text, expect)
+	}
+
+	_, err = tmpl.Render(map[string]interface{}{
+		"Story":  "nope",
+		"EpicID": 45,
+		"Member": member,
+	})
+	if err == nil {
+		t.Error("expected error for unreferenceable value")
+	}
+}
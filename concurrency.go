@@ -0,0 +1,86 @@
+package clubhouse
+
+import (
+	"context"
+	"sync"
+)
+
+// Go runs fns concurrently, at most limit at a time, and waits for them
+// to finish. The first error cancels the context passed to the other
+// functions, stops any that haven't started yet from starting, and is
+// returned.
+//
+// limit bounds the number of requests in flight; the rate they're sent
+// at is still governed by each client's Limiter, so any number of Go
+// calls can share a client without going over the API rate limit. A
+// limit of zero or less means DefaultBulkConcurrency.
+func Go(ctx context.Context, limit int, fns ...func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once  sync.Once
+		first error
+	)
+	GoEach(ctx, limit, len(fns), func(ctx context.Context, i int) error {
+		err := fns[i](ctx)
+		if err != nil {
+			once.Do(func() {
+				first = err
+				cancel()
+			})
+		}
+		return err
+	})
+	return first
+}
+
+// GoEach calls fn for every i from 0 to n-1, at most limit at a time,
+// and waits for them to finish. Unlike Go, an error doesn't stop the
+// other calls; every error is collected. If ctx is done before a call
+// starts, that call isn't made and its error is ctx.Err().
+//
+// The returned slice has the error for each i, or is nil if every call
+// succeeded.
+func GoEach(ctx context.Context, limit int, n int, fn func(ctx context.Context, i int) error) []error {
+	if limit <= 0 {
+		limit = DefaultBulkConcurrency
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		sem    = make(chan struct{}, limit)
+		errs   []error
+		record = func(i int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if errs == nil {
+				errs = make([]error, n)
+			}
+			errs[i] = err
+		}
+	)
+	for i := 0; i < n; i++ {
+		if ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case sem <- struct{}{}:
+			}
+		}
+		if ctx.Err() != nil {
+			record(i, ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				record(i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,58 @@
+package clubhouse
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGoEachLimit(t *testing.T) {
+	var running, peak int32
+	errs := GoEach(context.Background(), 2, 10, func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if i == 3 {
+			return errors.New("three")
+		}
+		return nil
+	})
+	if peak > 2 {
+		t.Errorf("expected at most 2 at once, got %d", peak)
+	}
+	if len(errs) != 10 || errs[3] == nil || errs[4] != nil {
+		t.Errorf("expected only call 3 to fail, got %v", errs)
+	}
+	if errs := GoEach(context.Background(), 2, 3, func(context.Context, int) error { return nil }); errs != nil {
+		t.Errorf("expected nil errors, got %v", errs)
+	}
+}
+
+func TestGoCancelsOnError(t *testing.T) {
+	boom := errors.New("boom")
+	var started int32
+	fns := []func(context.Context) error{
+		func(ctx context.Context) error { return boom },
+	}
+	for i := 0; i < 5; i++ {
+		fns = append(fns, func(ctx context.Context) error {
+			atomic.AddInt32(&started, 1)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+	if err := Go(context.Background(), 1, fns...); err != boom {
+		t.Errorf("expected boom, got %v", err)
+	}
+	if started != 0 {
+		t.Errorf("expected no functions to start after the error, got %d", started)
+	}
+}
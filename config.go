@@ -0,0 +1,166 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config holds the settings shared by tools built on this package, so
+// they don't each have to invent their own format for them.
+type Config struct {
+	AuthToken string `json:"auth_token" yaml:"auth_token"`
+	RootURL   string `json:"root_url" yaml:"root_url"`
+	Version   string `json:"version" yaml:"version"`
+
+	// Flavor is passed through to Client.Flavor. Set it to "shortcut"
+	// for workspaces on the Shortcut API.
+	Flavor APIFlavor `json:"flavor" yaml:"flavor"`
+
+	// RateLimit is the number of requests per second. Zero means use
+	// DefaultLimiter.
+	RateLimit int `json:"rate_limit" yaml:"rate_limit"`
+
+	// AdaptiveRateLimit uses an AdaptiveLimiter, starting at RateLimit
+	// (or DefaultRequestsPerSecond) and then following the API's rate
+	// limit headers.
+	AdaptiveRateLimit bool `json:"adaptive_rate_limit" yaml:"adaptive_rate_limit"`
+
+	// Retries is passed through to Client.Retries.
+	Retries int `json:"retries" yaml:"retries"`
+
+	// Debug logs everything the client does to stderr.
+	Debug bool `json:"debug" yaml:"debug"`
+
+	// DefaultProjectID isn't used by the client itself, it's here for
+	// tools that need a project to put things in.
+	DefaultProjectID int `json:"default_project_id" yaml:"default_project_id"`
+}
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvAuthToken        = "CLUBHOUSE_API_TOKEN"
+	EnvRootURL          = "CLUBHOUSE_ROOT_URL"
+	EnvVersion          = "CLUBHOUSE_API_VERSION"
+	EnvFlavor           = "CLUBHOUSE_API_FLAVOR"
+	EnvRateLimit        = "CLUBHOUSE_RATE_LIMIT"
+	EnvAdaptiveLimit    = "CLUBHOUSE_ADAPTIVE_RATE_LIMIT"
+	EnvRetries          = "CLUBHOUSE_RETRIES"
+	EnvDebug            = "CLUBHOUSE_DEBUG"
+	EnvDefaultProjectID = "CLUBHOUSE_DEFAULT_PROJECT_ID"
+)
+
+// LoadConfig reads a Config from a YAML or JSON file. Files ending in
+// .json are read as JSON, everything else as YAML. An unknown flavor is
+// an error, as it is for ConfigFromEnv.
+func LoadConfig(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadConfig: %s", err)
+	}
+	cfg := Config{}
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(content, &cfg)
+	} else {
+		err = yaml.Unmarshal(content, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("LoadConfig: could not decode %s, %s", path, err)
+	}
+	if !validFlavor(cfg.Flavor) {
+		return nil, fmt.Errorf("LoadConfig: flavor in %s must be %s or %s, got %q",
+			path, FlavorClubhouse, FlavorShortcut, cfg.Flavor)
+	}
+	return &cfg, nil
+}
+
+// ConfigFromEnv builds a Config from the CLUBHOUSE_* environment
+// variables. Variables that aren't set are left as zero values.
+func ConfigFromEnv() (*Config, error) {
+	cfg := Config{
+		AuthToken: os.Getenv(EnvAuthToken),
+		RootURL:   os.Getenv(EnvRootURL),
+		Version:   os.Getenv(EnvVersion),
+		Flavor:    APIFlavor(os.Getenv(EnvFlavor)),
+	}
+	if !validFlavor(cfg.Flavor) {
+		return nil, fmt.Errorf("ConfigFromEnv: %s must be %s or %s, got %q",
+			EnvFlavor, FlavorClubhouse, FlavorShortcut, cfg.Flavor)
+	}
+	ints := []struct {
+		name string
+		out  *int
+	}{
+		{EnvRateLimit, &cfg.RateLimit},
+		{EnvRetries, &cfg.Retries},
+		{EnvDefaultProjectID, &cfg.DefaultProjectID},
+	}
+	for _, v := range ints {
+		raw := os.Getenv(v.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ConfigFromEnv: %s must be a number, got %q", v.name, raw)
+		}
+		*v.out = n
+	}
+	bools := []struct {
+		name string
+		out  *bool
+	}{
+		{EnvAdaptiveLimit, &cfg.AdaptiveRateLimit},
+		{EnvDebug, &cfg.Debug},
+	}
+	for _, v := range bools {
+		raw := os.Getenv(v.name)
+		if raw == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ConfigFromEnv: %s must be true or false, got %q", v.name, raw)
+		}
+		*v.out = b
+	}
+	return &cfg, nil
+}
+
+// validFlavor reports whether f is unset or one of the known flavors.
+func validFlavor(f APIFlavor) bool {
+	switch f {
+	case "", FlavorClubhouse, FlavorShortcut:
+		return true
+	}
+	return false
+}
+
+// Client makes a new Client from the config.
+func (cfg *Config) Client() *Client {
+	c := &Client{
+		AuthToken: cfg.AuthToken,
+		RootURL:   cfg.RootURL,
+		Version:   cfg.Version,
+		Flavor:    cfg.Flavor,
+		Retries:   cfg.Retries,
+	}
+	if cfg.Debug {
+		c.Logger = NewStdLogger(log.New(os.Stderr, "clubhouse: ", log.LstdFlags), LogDebug)
+	}
+	switch {
+	case cfg.AdaptiveRateLimit && cfg.RateLimit > 0:
+		c.Limiter = NewAdaptiveLimiter(cfg.RateLimit)
+	case cfg.AdaptiveRateLimit:
+		c.Limiter = NewAdaptiveLimiter(DefaultRequestsPerSecond)
+	case cfg.RateLimit > 0:
+		c.Limiter = RateLimiter(cfg.RateLimit)
+	}
+	return c
+}
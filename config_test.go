@@ -0,0 +1,76 @@
+package clubhouse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	os.Setenv(EnvAuthToken, "tkn")
+	os.Setenv(EnvRetries, "3")
+	defer os.Unsetenv(EnvAuthToken)
+	defer os.Unsetenv(EnvRetries)
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal("did not expect error", err)
+	}
+	c := cfg.Client()
+	if c.AuthToken != "tkn" {
+		t.Error("wrong token, got", c.AuthToken)
+	}
+	if c.Retries != 3 {
+		t.Error("wrong retries, got", c.Retries)
+	}
+
+	os.Setenv(EnvAdaptiveLimit, "true")
+	defer os.Unsetenv(EnvAdaptiveLimit)
+	cfg, err = ConfigFromEnv()
+	if err != nil {
+		t.Fatal("did not expect error", err)
+	}
+	if _, ok := cfg.Client().Limiter.(*AdaptiveLimiter); !ok {
+		t.Error("expected an adaptive limiter")
+	}
+
+	os.Setenv(EnvDebug, "true")
+	defer os.Unsetenv(EnvDebug)
+	cfg, err = ConfigFromEnv()
+	if err != nil {
+		t.Fatal("did not expect error", err)
+	}
+	if cfg.Client().Logger == nil {
+		t.Error("expected a logger")
+	}
+
+	os.Setenv(EnvRetries, "lots")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("expected error for non-numeric retries")
+	}
+}
+
+func TestLoadConfigFlavor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clubhouse-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	good := filepath.Join(dir, "good.yaml")
+	ioutil.WriteFile(good, []byte("auth_token: tkn\nflavor: shortcut\n"), 0600)
+	cfg, err := LoadConfig(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Flavor != FlavorShortcut {
+		t.Errorf("expected the shortcut flavor, got %q", cfg.Flavor)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	ioutil.WriteFile(bad, []byte(`{"flavor":"shortcutt"}`), 0600)
+	if _, err := LoadConfig(bad); err == nil {
+		t.Error("expected an error for an unknown flavor")
+	}
+}
@@ -0,0 +1,125 @@
+package clubhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProjectConventions are a team's defaults and rules for new stories in
+// one project.
+type ProjectConventions struct {
+	// WorkflowStateID is the state new stories start in when they don't
+	// set one.
+	WorkflowStateID int
+
+	// Template fills in the fields a new story leaves empty, the way an
+	// entity template's story contents do. Its labels are added to the
+	// story's. Its ProjectID is ignored.
+	Template *CreateStoryContents
+
+	// RequiredLabels are the names of labels every new story must have.
+	// They're added to stories that don't have them, unless Enforce is
+	// set.
+	RequiredLabels []string
+
+	// Enforce makes CreateStory fail with an ErrConvention when a story
+	// is missing required labels, instead of adding them.
+	Enforce bool
+}
+
+// Conventions maps project IDs to their ProjectConventions. Set
+// Client.Conventions to have CreateStory and CreateStories apply them.
+type Conventions map[int]ProjectConventions
+
+// ErrConvention is returned when a new story breaks its project's
+// enforced conventions.
+type ErrConvention struct {
+	ProjectID     int
+	MissingLabels []string
+}
+
+func (e ErrConvention) Error() string {
+	return fmt.Sprintf("clubhouse: story in project %d is missing required labels: %s",
+		e.ProjectID, strings.Join(e.MissingLabels, ", "))
+}
+
+// Apply returns params with the conventions for its project applied.
+// Fields that are already set are never changed. params is left alone.
+func (cv Conventions) Apply(params CreateStoryParams) (CreateStoryParams, error) {
+	conv, ok := cv[params.ProjectID]
+	if !ok {
+		return params, nil
+	}
+	params.Labels = append([]CreateLabelParams{}, params.Labels...)
+	if t := conv.Template; t != nil {
+		if params.Deadline == nil {
+			params.Deadline = t.Deadline
+		}
+		if params.Description == "" {
+			params.Description = t.Description
+		}
+		if params.EpicID == 0 {
+			params.EpicID = t.EpicID
+		}
+		if params.Estimate == 0 {
+			params.Estimate = t.Estimate
+		}
+		if len(params.FollowerIDs) == 0 {
+			params.FollowerIDs = t.FollowerIDs
+		}
+		if params.GroupID == "" {
+			params.GroupID = t.GroupID
+		}
+		if params.Name == "" {
+			params.Name = t.Name
+		}
+		if len(params.OwnerIDs) == 0 {
+			params.OwnerIDs = t.OwnerIDs
+		}
+		if params.StoryType == "" {
+			params.StoryType = t.StoryType
+		}
+		if len(params.Tasks) == 0 {
+			params.Tasks = t.Tasks
+		}
+		if params.WorkflowStateID == 0 {
+			params.WorkflowStateID = t.WorkflowStateID
+		}
+		for _, l := range t.Labels {
+			if !hasLabelParam(params.Labels, l.Name) {
+				params.Labels = append(params.Labels, l)
+			}
+		}
+	}
+	if params.WorkflowStateID == 0 {
+		params.WorkflowStateID = conv.WorkflowStateID
+	}
+
+	missing := []string{}
+	for _, name := range conv.RequiredLabels {
+		if !hasLabelParam(params.Labels, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 && conv.Enforce {
+		return params, ErrConvention{params.ProjectID, missing}
+	}
+	for _, name := range missing {
+		params.Labels = append(params.Labels, CreateLabelParams{Name: name})
+	}
+	if len(params.Labels) == 0 {
+		params.Labels = nil
+	}
+	return params, nil
+}
+
+// hasLabelParam reports whether labels has one called name. Label names
+// are compared without case, like Clubhouse does.
+func hasLabelParam(labels []CreateLabelParams, name string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestConventionsApply(t *testing.T) {
+	cv := Conventions{
+		1: {
+			WorkflowStateID: 500,
+			Template: &CreateStoryContents{
+				Description: "## Acceptance criteria",
+				StoryType:   StoryTypeFeature,
+				Labels:      []CreateLabelParams{{Name: "mobile"}},
+			},
+			RequiredLabels: []string{"triage"},
+		},
+		2: {RequiredLabels: []string{"security"}, Enforce: true},
+	}
+
+	params := CreateStoryParams{ProjectID: 1, Name: "x", StoryType: StoryTypeBug, Labels: []CreateLabelParams{{Name: "Mobile"}}}
+	applied, err := cv.Apply(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := CreateStoryParams{
+		ProjectID:       1,
+		Name:            "x",
+		StoryType:       StoryTypeBug,
+		Description:     "## Acceptance criteria",
+		WorkflowStateID: 500,
+		Labels:          []CreateLabelParams{{Name: "Mobile"}, {Name: "triage"}},
+	}
+	if !reflect.DeepEqual(applied, expect) {
+		t.Errorf("expected %+v, got %+v", expect, applied)
+	}
+	if len(params.Labels) != 1 {
+		t.Errorf("expected params to be left alone, got %+v", params.Labels)
+	}
+
+	params = CreateStoryParams{ProjectID: 1, WorkflowStateID: 501}
+	if applied, _ := cv.Apply(params); applied.WorkflowStateID != 501 {
+		t.Errorf("expected the story's state to be kept, got %d", applied.WorkflowStateID)
+	}
+
+	_, err = cv.Apply(CreateStoryParams{ProjectID: 2})
+	if e, ok := err.(ErrConvention); !ok || !reflect.DeepEqual(e.MissingLabels, []string{"security"}) {
+		t.Errorf("expected ErrConvention, got %v", err)
+	}
+	if _, err := cv.Apply(CreateStoryParams{ProjectID: 2, Labels: []CreateLabelParams{{Name: "security"}}}); err != nil {
+		t.Errorf("expected labelled story to pass, got %v", err)
+	}
+	if applied, _ := cv.Apply(CreateStoryParams{ProjectID: 3}); applied.Labels != nil {
+		t.Errorf("expected other projects to be left alone, got %+v", applied)
+	}
+}
+
+func TestCreateStoryConventions(t *testing.T) {
+	var posted CreateStoryParams
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&posted)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer srv.Close()
+	c := &Client{
+		AuthToken:   "token",
+		RootURL:     srv.URL,
+		Limiter:     RateLimiter(0),
+		Conventions: Conventions{1: {WorkflowStateID: 500, RequiredLabels: []string{"ops"}, Enforce: true}},
+	}
+
+	if _, err := c.CreateStory(&CreateStoryParams{ProjectID: 1, Name: "x"}); err == nil || requests != 0 {
+		t.Errorf("expected the story to be refused before sending, got %v", err)
+	}
+	_, err := c.CreateStory(&CreateStoryParams{ProjectID: 1, Name: "x", Labels: []CreateLabelParams{{Name: "ops"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posted.WorkflowStateID != 500 {
+		t.Errorf("expected the default state to be sent, got %+v", posted)
+	}
+}
@@ -0,0 +1,73 @@
+package clubhouse
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ConvertStoryTypeOptions describes the workspace conventions
+// ConvertStoryType follows. The zero value only changes the type.
+type ConvertStoryTypeOptions struct {
+	// UnestimatedTypes are story types that shouldn't carry an
+	// estimate, e.g. chores in workspaces that only point features and
+	// bugs. Converting to one of these clears the estimate.
+	UnestimatedTypes []StoryType
+
+	// TypeLabels maps story types to a label the workspace uses to mark
+	// them. Converting adds the label for the new type and removes the
+	// labels for every other type. All other labels are kept.
+	TypeLabels map[StoryType]string
+}
+
+// ConvertStoryType changes the type of a story, applying the side
+// effects described by opts. Owners, followers and labels not covered
+// by opts are left alone. opts may be nil.
+func (c *Client) ConvertStoryType(id int, to StoryType, opts *ConvertStoryTypeOptions) (*StorySlim, error) {
+	if opts == nil {
+		opts = &ConvertStoryTypeOptions{}
+	}
+	story, err := c.GetStory(id)
+	if err != nil {
+		return nil, err
+	}
+	// the bulk endpoint can add and remove labels without having to
+	// send the full list
+	updated, err := c.UpdateStories(convertStoryTypeParams(*story, to, opts))
+	if err != nil {
+		return nil, err
+	}
+	if len(updated) != 1 {
+		return nil, fmt.Errorf("ConvertStoryType: expected 1 story in response, got %d", len(updated))
+	}
+	return &updated[0], nil
+}
+
+func convertStoryTypeParams(story Story, to StoryType, opts *ConvertStoryTypeOptions) *UpdateStoriesParams {
+	params := &UpdateStoriesParams{StoryIDs: []int{story.ID}, StoryType: to}
+
+	for _, t := range opts.UnestimatedTypes {
+		if t == to && story.Estimate != 0 {
+			params.Estimate = ResetEstimate
+		}
+	}
+
+	has := map[string]bool{}
+	for _, l := range story.Labels {
+		has[l.Name] = true
+	}
+	types := make([]string, 0, len(opts.TypeLabels))
+	for t := range opts.TypeLabels {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		name := opts.TypeLabels[StoryType(t)]
+		switch {
+		case StoryType(t) == to && !has[name]:
+			params.LabelsAdd = append(params.LabelsAdd, CreateLabelParams{Name: name})
+		case StoryType(t) != to && has[name]:
+			params.LabelsRemove = append(params.LabelsRemove, CreateLabelParams{Name: name})
+		}
+	}
+	return params
+}
@@ -0,0 +1,34 @@
+package clubhouse
+
+import "testing"
+
+func TestConvertStoryTypeParams(t *testing.T) {
+	opts := &ConvertStoryTypeOptions{
+		UnestimatedTypes: []StoryType{StoryTypeChore},
+		TypeLabels: map[StoryType]string{
+			StoryTypeBug:   "bug",
+			StoryTypeChore: "maintenance",
+		},
+	}
+	story := Story{
+		ID:       5,
+		Estimate: 3,
+		Labels:   []Label{{Name: "bug"}, {Name: "frontend"}},
+	}
+
+	params := convertStoryTypeParams(story, StoryTypeChore, opts)
+	if params.StoryType != StoryTypeChore || params.Estimate != ResetEstimate {
+		t.Errorf("expected chore with estimate cleared, got %+v", params)
+	}
+	if len(params.LabelsAdd) != 1 || params.LabelsAdd[0].Name != "maintenance" {
+		t.Errorf("expected maintenance label added, got %+v", params.LabelsAdd)
+	}
+	if len(params.LabelsRemove) != 1 || params.LabelsRemove[0].Name != "bug" {
+		t.Errorf("expected bug label removed, got %+v", params.LabelsRemove)
+	}
+
+	params = convertStoryTypeParams(story, StoryTypeFeature, &ConvertStoryTypeOptions{})
+	if params.Estimate != nil || params.LabelsAdd != nil || params.LabelsRemove != nil {
+		t.Errorf("expected only the type to change, got %+v", params)
+	}
+}
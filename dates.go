@@ -0,0 +1,181 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateWarningKind identifies the kind of problem found by the date
+// validators.
+type DateWarningKind string
+
+// Valid values for DateWarningKind
+const (
+	DeadlineBeforeIteration DateWarningKind = "deadline_before_iteration"
+	DeadlineAfterIteration  DateWarningKind = "deadline_after_iteration"
+	DeadlineBeforeStart     DateWarningKind = "deadline_before_start"
+)
+
+// DateWarning is a structured warning about the dates on a story or
+// epic. Warnings are advisory: the API accepts all of these dates.
+type DateWarning struct {
+	Kind       DateWarningKind
+	EntityType string
+	ID         int
+	Message    string
+}
+
+func (w DateWarning) String() string {
+	return fmt.Sprintf("%s %d: %s", w.EntityType, w.ID, w.Message)
+}
+
+// IterationWindow is the span of dates an iteration covers. EndDate is
+// inclusive.
+type IterationWindow struct {
+	ID        int
+	StartDate Date
+	EndDate   Date
+}
+
+// CheckStoryDeadline checks that a story's deadline falls within the
+// window of its iteration. Stories without a deadline always pass.
+func CheckStoryDeadline(story Story, iteration IterationWindow) []DateWarning {
+	if story.Deadline.IsZero() {
+		return nil
+	}
+	warn := func(kind DateWarningKind, format string, v ...interface{}) []DateWarning {
+		return []DateWarning{{
+			Kind:       kind,
+			EntityType: "story",
+			ID:         story.ID,
+			Message:    fmt.Sprintf(format, v...),
+		}}
+	}
+	deadline := DateOf(story.Deadline)
+	if !iteration.StartDate.IsZero() && deadline.Before(iteration.StartDate) {
+		return warn(DeadlineBeforeIteration,
+			"deadline %s is before iteration %d starts on %s",
+			deadline, iteration.ID, iteration.StartDate)
+	}
+	if !iteration.EndDate.IsZero() && deadline.After(iteration.EndDate) {
+		return warn(DeadlineAfterIteration,
+			"deadline %s is after iteration %d ends on %s",
+			deadline, iteration.ID, iteration.EndDate)
+	}
+	return nil
+}
+
+// CheckEpicDates checks that an epic's deadline isn't before its planned
+// start date.
+func CheckEpicDates(epic Epic) []DateWarning {
+	if epic.Deadline.IsZero() || epic.PlannedStartDate.IsZero() {
+		return nil
+	}
+	if DateOf(epic.Deadline).Before(DateOf(epic.PlannedStartDate)) {
+		return []DateWarning{{
+			Kind:       DeadlineBeforeStart,
+			EntityType: "epic",
+			ID:         epic.ID,
+			Message: fmt.Sprintf("deadline %s is before planned start %s",
+				DateOf(epic.Deadline), DateOf(epic.PlannedStartDate)),
+		}}
+	}
+	return nil
+}
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date with no time of day or timezone, in the form
+// YYYY-MM-DD. Deadlines and iteration dates are days, not instants:
+// sent as midnight in a local zone, they land on the previous day for
+// anyone west of it. Date keeps the day as it was written.
+type Date string
+
+// ParseDate parses a date in the form YYYY-MM-DD.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return "", fmt.Errorf("clubhouse: bad date %q: %s", s, err)
+	}
+	return DateOf(t), nil
+}
+
+// DateOf returns the calendar date of t in t's own location.
+func DateOf(t time.Time) Date {
+	if t.IsZero() {
+		return ""
+	}
+	return Date(t.Format(dateLayout))
+}
+
+// IsZero reports whether d is unset.
+func (d Date) IsZero() bool {
+	return d == ""
+}
+
+// Time returns midnight at the start of d in loc, or the zero time if d
+// is unset or invalid.
+func (d Date) Time(loc *time.Location) time.Time {
+	t, err := time.ParseInLocation(dateLayout, string(d), loc)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Deadline returns d as a time for the Deadline fields of params: noon
+// UTC, which is the same day everywhere from UTC-11 to UTC+11, so the
+// deadline shows up on d whatever the viewer's timezone. DateOf turns
+// it back into d. It returns nil if d is unset or invalid.
+func (d Date) Deadline() *time.Time {
+	t := d.Time(time.UTC)
+	if t.IsZero() {
+		return nil
+	}
+	t = t.Add(12 * time.Hour)
+	return &t
+}
+
+// AddDays returns the date n days after d.
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.Time(time.UTC).AddDate(0, 0, n))
+}
+
+// Before reports whether d is before e.
+func (d Date) Before(e Date) bool {
+	return d < e
+}
+
+// After reports whether d is after e.
+func (d Date) After(e Date) bool {
+	return d > e
+}
+
+func (d Date) String() string {
+	return string(d)
+}
+
+// UnmarshalJSON accepts dates in the form YYYY-MM-DD, and also full
+// timestamps, which some endpoints return for dates. A timestamp is
+// taken as the date it falls on in its own offset.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == nil || *s == "" {
+		*d = ""
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, *s); err == nil {
+		*d = DateOf(t)
+		return nil
+	}
+	date, err := ParseDate(*s)
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
@@ -0,0 +1,87 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCheckStoryDeadline(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2018, 4, d, 12, 0, 0, 0, time.UTC)
+	}
+	iteration := IterationWindow{ID: 1, StartDate: DateOf(day(10)), EndDate: DateOf(day(20))}
+	for _, test := range []struct {
+		Name     string
+		Deadline time.Time
+		Expect   DateWarningKind
+	}{
+		{"no deadline", time.Time{}, ""},
+		{"inside", day(15), ""},
+		{"last day", day(20).Add(6 * time.Hour), ""},
+		{"before", day(9), DeadlineBeforeIteration},
+		{"after", day(21), DeadlineAfterIteration},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			warnings := CheckStoryDeadline(Story{ID: 5, Deadline: test.Deadline}, iteration)
+			var kind DateWarningKind
+			if len(warnings) > 0 {
+				kind = warnings[0].Kind
+			}
+			if kind != test.Expect {
+				t.Errorf("expected %q, got %q", test.Expect, kind)
+			}
+		})
+	}
+}
+
+func TestCheckEpicDates(t *testing.T) {
+	epic := Epic{
+		PlannedStartDate: time.Date(2018, 4, 10, 0, 0, 0, 0, time.UTC),
+		Deadline:         time.Date(2018, 4, 9, 0, 0, 0, 0, time.UTC),
+	}
+	if w := CheckEpicDates(epic); len(w) != 1 || w[0].Kind != DeadlineBeforeStart {
+		t.Error("expected deadline before start warning, got", w)
+	}
+}
+
+func TestDate(t *testing.T) {
+	d, err := ParseDate("2019-03-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseDate("2019-03-10T00:00:00Z"); err == nil {
+		t.Error("expected a timestamp not to parse as a date")
+	}
+
+	// midnight in a zone east of UTC is the previous day in UTC
+	berlin := time.FixedZone("CET", 60*60)
+	if got := DateOf(d.Time(berlin)); got != d {
+		t.Errorf("expected %s, got %s", d, got)
+	}
+	deadline := d.Deadline()
+	for _, offset := range []int{-11, -5, 0, 5, 11} {
+		zone := time.FixedZone("", offset*60*60)
+		if got := DateOf(deadline.In(zone)); got != d {
+			t.Errorf("expected the deadline to be %s at UTC%+d, got %s", d, offset, got)
+		}
+	}
+	if Date("").Deadline() != nil {
+		t.Error("expected no deadline for an unset date")
+	}
+	if got := d.AddDays(22); got != "2019-04-01" {
+		t.Errorf("expected 2019-04-01, got %s", got)
+	}
+
+	var it Iteration
+	err = json.Unmarshal([]byte(`{"start_date":"2019-03-10","end_date":"2019-03-22T00:00:00Z"}`), &it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.StartDate != "2019-03-10" || it.EndDate != "2019-03-22" {
+		t.Errorf("unexpected dates %s to %s", it.StartDate, it.EndDate)
+	}
+	if err := json.Unmarshal([]byte(`{"start_date":"March 10"}`), &it); err == nil {
+		t.Error("expected a bad date to fail")
+	}
+}
@@ -0,0 +1,83 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// DecodeErrorContext is the number of bytes of the response included on
+// either side of the failure point in ErrDecode.Snippet.
+var DecodeErrorContext = 40
+
+// ErrDecode is returned when a response can't be decoded into the Go
+// type for it, which usually means the API has changed.
+type ErrDecode struct {
+	Endpoint string
+
+	// Type is the Go type the response was being decoded into.
+	Type string
+
+	// Field is the dotted path of the field that failed, if known.
+	Field string
+
+	// Offset is the byte offset in the response where decoding failed,
+	// and Snippet the response around it.
+	Offset  int64
+	Snippet string
+
+	Err error
+}
+
+func (e ErrDecode) Error() string {
+	msg := fmt.Sprintf("clubhouse: error decoding %s response into %s", e.Endpoint, e.Type)
+	if e.Field != "" {
+		msg += fmt.Sprintf(" (field %s)", e.Field)
+	}
+	msg += fmt.Sprintf(" at offset %d: %s", e.Offset, e.Err)
+	if e.Snippet != "" {
+		msg += fmt.Sprintf(", near %q", e.Snippet)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying json error.
+func (e ErrDecode) Unwrap() error {
+	return e.Err
+}
+
+// decodeError wraps an error from json.Unmarshal(content, target) in an
+// ErrDecode.
+func decodeError(endpoint string, content []byte, target interface{}, err error) ErrDecode {
+	t := reflect.TypeOf(target)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	e := ErrDecode{Endpoint: endpoint, Type: fmt.Sprint(t), Err: err}
+	switch jerr := err.(type) {
+	case *json.SyntaxError:
+		e.Offset = jerr.Offset
+	case *json.UnmarshalTypeError:
+		e.Offset = jerr.Offset
+		e.Field = jerr.Field
+	default:
+		return e
+	}
+	e.Snippet = snippet(content, e.Offset, DecodeErrorContext)
+	return e
+}
+
+// snippet returns up to n bytes of content on either side of offset.
+func snippet(content []byte, offset int64, n int) string {
+	start, end := offset-int64(n), offset+int64(n)
+	if start < 0 {
+		start = 0
+	}
+	if end > int64(len(content)) {
+		end = int64(len(content))
+	}
+	if start > end {
+		return ""
+	}
+	return string(content[start:end])
+}
@@ -0,0 +1,32 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeError(t *testing.T) {
+	content := []byte(`{"id": 1, "name": "a story", "estimate": "three", "archived": false}`)
+	story := &Story{}
+	err := decodeError("stories/1", content, story, json.Unmarshal(content, story))
+
+	if err.Type != "clubhouse.Story" {
+		t.Errorf("expected type clubhouse.Story, got %s", err.Type)
+	}
+	if err.Field != "estimate" {
+		t.Errorf("expected field estimate, got %s", err.Field)
+	}
+	if !strings.Contains(err.Snippet, `"three"`) {
+		t.Errorf("expected snippet to include the bad value, got %q", err.Snippet)
+	}
+	if !strings.Contains(err.Error(), "stories/1") {
+		t.Errorf("expected error to mention the endpoint, got %s", err)
+	}
+
+	content = []byte(`{"id": 1,, }`)
+	err = decodeError("stories/1", content, story, json.Unmarshal(content, story))
+	if err.Offset != 10 || err.Snippet != string(content) {
+		t.Errorf("unexpected syntax error context: %d %q", err.Offset, err.Snippet)
+	}
+}
@@ -0,0 +1,162 @@
+package clubhouse
+
+import (
+	"strings"
+)
+
+// DescriptionSection is a chunk of a Markdown description that starts
+// with a heading (e.g. "## Acceptance Criteria") and runs until the
+// next heading. The text before the first heading is kept as a section
+// with an empty Title and a Level of 0.
+type DescriptionSection struct {
+	Title string
+	Level int
+	Body  string
+
+	// heading is the original heading line, including the newline, so
+	// untouched sections serialize exactly as they were parsed.
+	heading string
+}
+
+// Description is a Markdown description split into named sections.
+type Description struct {
+	Sections []DescriptionSection
+}
+
+// ParseDescription splits a Markdown description into sections. Headings
+// inside fenced code blocks are ignored. Calling String on the result
+// returns the original text unchanged.
+func ParseDescription(text string) *Description {
+	d := &Description{}
+	current := DescriptionSection{}
+	fenced := false
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+		if !fenced {
+			if level, title, ok := parseHeading(line); ok {
+				if current.heading != "" || current.Body != "" {
+					d.Sections = append(d.Sections, current)
+				}
+				current = DescriptionSection{
+					Title:   title,
+					Level:   level,
+					heading: line,
+				}
+				continue
+			}
+		}
+		current.Body += line
+	}
+	if current.heading != "" || current.Body != "" {
+		d.Sections = append(d.Sections, current)
+	}
+	return d
+}
+
+// parseHeading recognizes ATX style headings ("# Title" through
+// "###### Title").
+func parseHeading(line string) (int, string, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || line[level] != ' ' {
+		return 0, "", false
+	}
+	title := strings.TrimSpace(strings.TrimRight(line[level:], "# "))
+	return level, title, true
+}
+
+// Section returns the body of the section with the given title. Titles
+// are compared case-insensitively.
+func (d *Description) Section(title string) (string, bool) {
+	if i := d.index(title); i >= 0 {
+		return strings.TrimSpace(d.Sections[i].Body), true
+	}
+	return "", false
+}
+
+// SetSection replaces the body of the section with the given title,
+// leaving every other section as it was. If there is no such section,
+// a new level 2 section is added to the end of the description.
+func (d *Description) SetSection(title string, body string) {
+	body = "\n" + strings.TrimSpace(body) + "\n"
+
+	if i := d.index(title); i >= 0 {
+		if i < len(d.Sections)-1 {
+			body += "\n"
+		}
+		d.Sections[i].Body = body
+		return
+	}
+
+	if n := len(d.Sections); n > 0 {
+		last := &d.Sections[n-1]
+		if !strings.HasSuffix(last.Body, "\n") {
+			last.Body += "\n"
+		}
+		if !strings.HasSuffix(last.Body, "\n\n") {
+			last.Body += "\n"
+		}
+	}
+	d.Sections = append(d.Sections, DescriptionSection{
+		Title: title,
+		Level: 2,
+		Body:  body,
+	})
+}
+
+// RemoveSection removes the section with the given title, if it exists.
+func (d *Description) RemoveSection(title string) {
+	if i := d.index(title); i >= 0 {
+		d.Sections = append(d.Sections[:i], d.Sections[i+1:]...)
+	}
+}
+
+// String serializes the description back to Markdown.
+func (d *Description) String() string {
+	buf := strings.Builder{}
+	for _, s := range d.Sections {
+		switch {
+		case s.heading != "":
+			buf.WriteString(s.heading)
+		case s.Title != "":
+			buf.WriteString(strings.Repeat("#", s.Level))
+			buf.WriteString(" " + s.Title + "\n")
+		}
+		buf.WriteString(s.Body)
+	}
+	return buf.String()
+}
+
+func (d *Description) index(title string) int {
+	for i, s := range d.Sections {
+		if s.Level > 0 && strings.EqualFold(s.Title, strings.TrimSpace(title)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// UpdateStoryDescriptionSection replaces a single section of a story's
+// description, leaving the rest of the description untouched. See
+// Description.SetSection.
+func (c *Client) UpdateStoryDescriptionSection(id int, title string, body string) (*Story, error) {
+	story, err := c.GetStory(id)
+	if err != nil {
+		return nil, err
+	}
+	desc := ParseDescription(story.Description)
+	desc.SetSection(title, body)
+	return c.UpdateStory(id, &UpdateStoryParams{
+		Description: String(desc.String()),
+	})
+}
@@ -0,0 +1,39 @@
+package clubhouse
+
+import "testing"
+
+func TestDescriptionSections(t *testing.T) {
+	text := "Intro text\n\n## Acceptance Criteria\n\n- works\n\n```\n# not a heading\n```\n\n## QA Notes\n\nold notes\n"
+
+	d := ParseDescription(text)
+	t.Run("round trip", func(t *testing.T) {
+		if d.String() != text {
+			t.Errorf("%q != %q", d.String(), text)
+		}
+	})
+	t.Run("section", func(t *testing.T) {
+		body, ok := d.Section("acceptance criteria")
+		if !ok {
+			t.Fatal("expected to find section")
+		}
+		if body != "- works\n\n```\n# not a heading\n```" {
+			t.Errorf("wrong body %q", body)
+		}
+	})
+	t.Run("set existing", func(t *testing.T) {
+		d := ParseDescription(text)
+		d.SetSection("Acceptance Criteria", "- still works")
+		expect := "Intro text\n\n## Acceptance Criteria\n\n- still works\n\n## QA Notes\n\nold notes\n"
+		if d.String() != expect {
+			t.Errorf("%q != %q", d.String(), expect)
+		}
+	})
+	t.Run("set new", func(t *testing.T) {
+		d := ParseDescription("Intro text")
+		d.SetSection("QA Notes", "new notes")
+		expect := "Intro text\n\n## QA Notes\n\nnew notes\n"
+		if d.String() != expect {
+			t.Errorf("%q != %q", d.String(), expect)
+		}
+	})
+}
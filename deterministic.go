@@ -0,0 +1,193 @@
+package clubhouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CanonicalTimeFormat is the layout used for times when marshaling in
+// deterministic mode. Times are always converted to UTC first.
+const CanonicalTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// MarshalDeterministic marshals v to JSON the same way every time, no
+// matter the machine, time zone or Go version: fields keep their struct
+// order and every timestamp is rewritten in UTC using
+// CanonicalTimeFormat.
+//
+// Timestamps are recognized by type: only values that were a time.Time
+// are normalized, so a string that happens to look like a time, such as
+// a story's name, is left alone. Values inside an interface{} aren't
+// normalized, since their type isn't known.
+func MarshalDeterministic(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalizeJSON(raw, reflect.TypeOf(v))
+}
+
+func canonicalizeJSON(raw []byte, t reflect.Type) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	buf := bytes.Buffer{}
+	if err := writeCanonical(dec, &buf, t); err != nil {
+		return nil, fmt.Errorf("could not canonicalize json, %s", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// writeCanonical copies the next JSON value from dec to buf, token by
+// token, so that object keys stay in their original order. t is the Go
+// type the value was marshaled from, or nil if it isn't known.
+func writeCanonical(dec *json.Decoder, buf *bytes.Buffer, t reflect.Type) error {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		open, close := byte(tok), byte('}')
+		if tok == '[' {
+			close = ']'
+		}
+		buf.WriteByte(open)
+		for first := true; dec.More(); first = false {
+			if !first {
+				buf.WriteByte(',')
+			}
+			elem := elemType(t)
+			if open == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := writeJSON(buf, key); err != nil {
+					return err
+				}
+				buf.WriteByte(':')
+				if t != nil && t.Kind() == reflect.Struct {
+					elem = jsonFieldType(t, key.(string))
+				}
+			}
+			if err := writeCanonical(dec, buf, elem); err != nil {
+				return err
+			}
+		}
+		// consume the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		buf.WriteByte(close)
+	case json.Number:
+		buf.WriteString(tok.String())
+	case string:
+		if t == timeType {
+			if ts, err := time.Parse(time.RFC3339Nano, tok); err == nil {
+				tok = ts.UTC().Format(CanonicalTimeFormat)
+			}
+		}
+		return writeJSON(buf, tok)
+	default:
+		// bool or nil
+		return writeJSON(buf, tok)
+	}
+	return nil
+}
+
+// elemType is the type of the values of slice, array or map t, or nil.
+func elemType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// jsonFieldType is the type of the field of struct t that encoding/json
+// marshals as key, looking into embedded structs, or nil. Structs with
+// their own MarshalJSON, like UpdateStoryParams, write their untagged
+// fields under snake_case names, so those are matched too.
+func jsonFieldType(t reflect.Type, key string) reflect.Type {
+	if ft := taggedFieldType(t, key); ft != nil {
+		return ft
+	}
+	if !t.Implements(marshalerType) && !reflect.PtrTo(t).Implements(marshalerType) {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("json") == "" && snakeCase(field.Name) == key {
+			return field.Type
+		}
+	}
+	return nil
+}
+
+func taggedFieldType(t reflect.Type, key string) reflect.Type {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" && field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if ft := taggedFieldType(embedded, key); ft != nil {
+					return ft
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if name == key {
+			return field.Type
+		}
+	}
+	return nil
+}
+
+// snakeCase turns a Go field name into the API's spelling, e.g.
+// StartedAtOverride into started_at_override and EpicID into epic_id.
+func snakeCase(name string) string {
+	out := []rune{}
+	runes := []rune(name)
+	for i, r := range runes {
+		upper := unicode.IsUpper(r)
+		if upper && i > 0 && (!unicode.IsUpper(runes[i-1]) ||
+			i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			out = append(out, '_')
+		}
+		out = append(out, unicode.ToLower(r))
+	}
+	return string(out)
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
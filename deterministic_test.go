@@ -0,0 +1,52 @@
+package clubhouse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarshalDeterministic(t *testing.T) {
+	zone := time.FixedZone("test", 4*60*60)
+	when := time.Date(2018, 4, 20, 16, 20, 0, 123456789, zone)
+	b, err := MarshalDeterministic(&CreateCommentParams{
+		Text:      "hello <world>",
+		CreatedAt: &when,
+	})
+	if err != nil {
+		t.Fatal("did not expect error", err)
+	}
+	expect := `{"created_at":"2018-04-20T12:20:00.123Z","text":"hello \u003cworld\u003e"}`
+	if string(b) != expect {
+		t.Errorf("%s != %s", string(b), expect)
+	}
+}
+
+func TestMarshalDeterministicOnlyTimes(t *testing.T) {
+	when := time.Date(2018, 4, 20, 16, 20, 0, 0, time.FixedZone("test", 4*60*60))
+	b, err := MarshalDeterministic(&CreateCommentParams{
+		Text:      "2018-04-20T16:20:00+04:00",
+		CreatedAt: &when,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"created_at":"2018-04-20T12:20:00.000Z","text":"2018-04-20T16:20:00+04:00"}`
+	if string(b) != expect {
+		t.Errorf("%s != %s", string(b), expect)
+	}
+}
+
+func TestMarshalDeterministicUpdateParams(t *testing.T) {
+	when := time.Date(2018, 4, 20, 16, 20, 0, 0, time.FixedZone("test", 4*60*60))
+	b, err := MarshalDeterministic(&UpdateStoryParams{Deadline: &when, StartedAtOverride: &when})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"deadline":"2018-04-20T12:20:00.000Z","started_at_override":"2018-04-20T12:20:00.000Z"}`
+	if string(b) != expect {
+		t.Errorf("%s != %s", string(b), expect)
+	}
+	if got := snakeCase("EpicID"); got != "epic_id" {
+		t.Errorf("expected epic_id, got %s", got)
+	}
+}
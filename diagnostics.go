@@ -0,0 +1,155 @@
+package clubhouse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Diagnostics describes the health of a client and of its connection to
+// Clubhouse. Counters cover every request the client has made since it
+// was created.
+type Diagnostics struct {
+	// TokenValid is true when GET /member succeeded with the client's
+	// AuthToken. TokenError holds the error when it didn't.
+	TokenValid bool   `json:"token_valid"`
+	TokenError string `json:"token_error,omitempty"`
+	Member     string `json:"member,omitempty"`
+
+	Requests    int       `json:"requests"`
+	Failures    int       `json:"failures"`
+	Retries     int       `json:"retries"`
+	MaxRetries  int       `json:"max_retries"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitempty"`
+
+	// RateLimitWait is the total time requests have spent waiting on
+	// the Limiter, and AvgRateLimitWait the average per request.
+	RateLimitWait    time.Duration `json:"rate_limit_wait"`
+	AvgRateLimitWait time.Duration `json:"avg_rate_limit_wait"`
+
+	CacheEnabled bool    `json:"cache_enabled"`
+	CacheHits    int     `json:"cache_hits"`
+	CacheMisses  int     `json:"cache_misses"`
+	CacheHitRate float64 `json:"cache_hit_rate"`
+}
+
+// diagnosticCounters are updated by the request path and reported by
+// Diagnostics.
+type diagnosticCounters struct {
+	mu            sync.Mutex
+	requests      int
+	failures      int
+	retries       int
+	lastError     string
+	lastErrorAt   time.Time
+	limiterWait   time.Duration
+	limiterWaited int
+	cacheHits     int
+	cacheMisses   int
+}
+
+func (d *diagnosticCounters) request(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.requests++
+	if err != nil {
+		d.failures++
+		d.lastError = err.Error()
+		d.lastErrorAt = time.Now()
+	}
+}
+
+func (d *diagnosticCounters) retry() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.retries++
+}
+
+func (d *diagnosticCounters) retryCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.retries
+}
+
+func (d *diagnosticCounters) rateLimitWait(wait time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.limiterWait += wait
+	d.limiterWaited++
+}
+
+func (d *diagnosticCounters) cacheLookup(hit bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if hit {
+		d.cacheHits++
+	} else {
+		d.cacheMisses++
+	}
+}
+
+// fill copies the counters into diag.
+func (d *diagnosticCounters) fill(diag *Diagnostics) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	diag.Requests = d.requests
+	diag.Failures = d.failures
+	diag.Retries = d.retries
+	diag.LastError = d.lastError
+	diag.LastErrorAt = d.lastErrorAt
+	diag.RateLimitWait = d.limiterWait
+	if d.limiterWaited > 0 {
+		diag.AvgRateLimitWait = d.limiterWait / time.Duration(d.limiterWaited)
+	}
+	diag.CacheHits = d.cacheHits
+	diag.CacheMisses = d.cacheMisses
+	if lookups := d.cacheHits + d.cacheMisses; lookups > 0 {
+		diag.CacheHitRate = float64(d.cacheHits) / float64(lookups)
+	}
+}
+
+// Diagnostics checks the AuthToken with GET /member and reports it along
+// with the client's request, retry, rate limit and cache counters. The
+// token check always goes to the API, bypassing the Cache and retries,
+// but like every request it has to pass the client's Policy.
+// The client has no circuit breaker; Retries and Failures are the
+// closest thing to breaker state.
+func (c *Client) Diagnostics(ctx context.Context) *Diagnostics {
+	c.checkSetup()
+
+	diag := &Diagnostics{
+		MaxRetries:   c.Retries,
+		CacheEnabled: c.Cache != nil,
+	}
+	content, err := c.httpRequest(ctx, requestDirect, "GET", "member", nil, nil)
+	if err != nil {
+		diag.TokenError = err.Error()
+	} else {
+		member := MemberInfo{}
+		if err := json.Unmarshal(content, &member); err != nil {
+			diag.TokenError = err.Error()
+		} else {
+			diag.TokenValid = true
+			diag.Member = member.MentionName
+		}
+	}
+	c.counters.fill(diag)
+	return diag
+}
+
+// DiagnosticsHandler returns an http.Handler that serves Diagnostics as
+// JSON, for embedding in service health checks. It responds 200 when
+// the token is valid and 503 otherwise.
+func (c *Client) DiagnosticsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		diag := c.Diagnostics(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if !diag.TokenValid {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(diag)
+	})
+}
@@ -0,0 +1,77 @@
+package clubhouse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	valid := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !valid {
+			w.WriteHeader(401)
+			return
+		}
+		w.Write([]byte(`{"id":"abc","mention_name":"brian"}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	diag := c.Diagnostics(context.Background())
+	if !diag.TokenValid || diag.Member != "brian" {
+		t.Errorf("expected valid token for brian, got %+v", diag)
+	}
+	if diag.Requests != 1 || diag.Failures != 0 {
+		t.Errorf("expected 1 successful request, got %+v", diag)
+	}
+
+	valid = false
+	rec := httptest.NewRecorder()
+	c.DiagnosticsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 for invalid token, got %d", rec.Code)
+	}
+	diag = c.Diagnostics(context.Background())
+	if diag.TokenValid || diag.Requests != 3 || diag.Failures != 2 || diag.LastError == "" {
+		t.Errorf("expected failed token check to be counted, got %+v", diag)
+	}
+}
+
+func TestDiagnosticCountersCacheHitRate(t *testing.T) {
+	d := diagnosticCounters{}
+	d.cacheLookup(true)
+	d.cacheLookup(true)
+	d.cacheLookup(false)
+	d.cacheLookup(true)
+	diag := Diagnostics{}
+	d.fill(&diag)
+	if diag.CacheHits != 3 || diag.CacheMisses != 1 || diag.CacheHitRate != 0.75 {
+		t.Errorf("unexpected cache counters: %+v", diag)
+	}
+}
+
+func TestDiagnosticsRequestPath(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"id":"abc","mention_name":"brian"}`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), Cache: NewMemoryStore()}
+
+	for i := 0; i < 2; i++ {
+		if diag := c.Diagnostics(context.Background()); !diag.TokenValid {
+			t.Fatalf("expected a valid token, got %+v", diag)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected every check to bypass the cache, got %d requests", requests)
+	}
+
+	c.Policy = NewPolicy(AllowRead("stories"))
+	if diag := c.Diagnostics(context.Background()); diag.TokenValid || requests != 2 {
+		t.Errorf("expected the policy to refuse the check, got %+v after %d requests", diag, requests)
+	}
+}
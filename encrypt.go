@@ -0,0 +1,80 @@
+package clubhouse
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"time"
+)
+
+// EncryptedStore wraps a Store, encrypting values with AES-GCM before
+// they're stored. Cached responses contain real story text and member
+// emails, which shouldn't sit in plaintext on shared CI disks. Keys are
+// not encrypted, so they must not contain anything sensitive; the
+// response cache only uses hashes.
+type EncryptedStore struct {
+	// Logger, if set, is told about values that fail to decrypt.
+	Logger Logger
+
+	store Store
+	aead  cipher.AEAD
+}
+
+// NewEncryptedStore wraps store. key must be 16, 24 or 32 bytes long to
+// select AES-128, AES-192 or AES-256.
+func NewEncryptedStore(store Store, key []byte) (*EncryptedStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("NewEncryptedStore: %s", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("NewEncryptedStore: %s", err)
+	}
+	return &EncryptedStore{store: store, aead: aead}, nil
+}
+
+// Get decrypts the value for key. Values that can't be decrypted, e.g.
+// because they were written with a different key, are treated as
+// missing.
+func (s *EncryptedStore) Get(key string) ([]byte, bool) {
+	sealed, ok := s.store.Get(key)
+	if !ok {
+		return nil, false
+	}
+	n := s.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, false
+	}
+	// the key is the additional data, so values can't be swapped
+	// between keys
+	value, err := s.aead.Open(nil, sealed[:n], sealed[n:], []byte(key))
+	if err != nil {
+		if s.Logger != nil {
+			s.Logger.Log(LogEntry{Level: LogWarn, Message: fmt.Sprintf("EncryptedStore: could not decrypt %s: %s", key, err)})
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+// Set encrypts value and stores it under key.
+func (s *EncryptedStore) Set(key string, value []byte, ttl time.Duration) error {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	return s.store.Set(key, s.aead.Seal(nonce, nonce, value, []byte(key)), ttl)
+}
+
+// Delete ...
+func (s *EncryptedStore) Delete(key string) error {
+	return s.store.Delete(key)
+}
+
+// Keys ...
+func (s *EncryptedStore) Keys(prefix string) ([]string, error) {
+	return s.store.Keys(prefix)
+}
@@ -0,0 +1,37 @@
+package clubhouse
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptedStore(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), 32)
+	mem := NewMemoryStore()
+	store, err := NewEncryptedStore(mem, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, store)
+
+	store.Set("member", []byte("someone@example.com"), 0)
+	raw, _ := mem.Get("member")
+	if bytes.Contains(raw, []byte("someone")) {
+		t.Error("expected value to be encrypted at rest")
+	}
+
+	// values can't be moved between keys
+	mem.Set("other", raw, 0)
+	if _, ok := store.Get("other"); ok {
+		t.Error("expected value under the wrong key to be rejected")
+	}
+
+	wrong, _ := NewEncryptedStore(mem, bytes.Repeat([]byte("x"), 32))
+	if _, ok := wrong.Get("member"); ok {
+		t.Error("expected the wrong key to fail to decrypt")
+	}
+
+	if _, err := NewEncryptedStore(mem, []byte("short")); err == nil {
+		t.Error("expected an error for a bad key length")
+	}
+}
@@ -0,0 +1,209 @@
+package clubhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// hasStatus reports whether err is an ErrClientRequest for a response
+// with the given HTTP status code.
+func hasStatus(err error, code int) bool {
+	e, ok := err.(ErrClientRequest)
+	return ok && e.Response != nil && e.Response.StatusCode == code
+}
+
+// EnsureLabel returns the label called name, creating it with color if
+// it doesn't exist yet. Names are compared case-insensitively.
+//
+// If another client creates the label between the lookup and the
+// create, the API answers with a 422; in that case the labels are
+// listed again and the winner of the race is returned.
+func (c *Client) EnsureLabel(name, color string) (*Label, error) {
+	label, err := c.findLabel(name)
+	if err != nil || label != nil {
+		return label, err
+	}
+
+	label, err = c.CreateLabel(&CreateLabelParams{Name: name, Color: color})
+	if err == nil {
+		return label, nil
+	}
+	if !hasStatus(err, 422) {
+		return nil, err
+	}
+
+	existing, lerr := c.findLabel(name)
+	if lerr != nil {
+		return nil, lerr
+	}
+	if existing == nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (c *Client) findLabel(name string) (*Label, error) {
+	labels, err := c.ListLabels()
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labels {
+		if strings.EqualFold(l.Name, name) {
+			label := l
+			return &label, nil
+		}
+	}
+	return nil, nil
+}
+
+// EnsureProject returns the project with the same name as params,
+// creating it if it doesn't exist yet. Names are compared
+// case-insensitively. Like EnsureLabel, a 422 from a concurrent create
+// is resolved by listing the projects again.
+func (c *Client) EnsureProject(params *CreateProjectParams) (*Project, error) {
+	project, err := c.findProject(params.Name)
+	if err != nil || project != nil {
+		return project, err
+	}
+
+	project, err = c.CreateProject(params)
+	if err == nil {
+		return project, nil
+	}
+	if !hasStatus(err, 422) {
+		return nil, err
+	}
+
+	existing, lerr := c.findProject(params.Name)
+	if lerr != nil {
+		return nil, lerr
+	}
+	if existing == nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (c *Client) findProject(name string) (*Project, error) {
+	projects, err := c.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		if strings.EqualFold(p.Name, name) {
+			project := p
+			return &project, nil
+		}
+	}
+	return nil, nil
+}
+
+// ProjectSpec describes the scaffolding ProvisionProject creates.
+type ProjectSpec struct {
+	Project CreateProjectParams
+
+	// Labels are created if they don't already exist, and are applied
+	// to the kickoff epic and every starter story.
+	Labels []CreateLabelParams
+
+	// KickoffEpic is optional. If given, starter stories are added to
+	// it.
+	KickoffEpic *CreateEpicParams
+
+	// Stories are created in the new project. ProjectID and EpicID are
+	// filled in automatically.
+	Stories []CreateStoryParams
+}
+
+// ProvisionedProject is everything ProvisionProject created.
+type ProvisionedProject struct {
+	Project *Project
+	Labels  []Label
+	Epic    *Epic
+	Stories []StorySlim
+}
+
+// ProvisionProject creates a project along with its default labels, a
+// kickoff epic and starter stories, all from a single spec.
+//
+// If any step fails, everything created so far is deleted again before
+// the error is returned. Labels that already existed are left alone. A
+// failed delete doesn't stop the rest of the rollback; its error is
+// added to the one returned.
+func (c *Client) ProvisionProject(spec ProjectSpec) (*ProvisionedProject, error) {
+	var (
+		out      = &ProvisionedProject{}
+		rollback = []func() error{}
+		err      error
+	)
+	fail := func(err error) (*ProvisionedProject, error) {
+		// every step is undone, even if an earlier one couldn't be
+		msgs := []string{}
+		for i := len(rollback) - 1; i >= 0; i-- {
+			if rerr := rollback[i](); rerr != nil {
+				msgs = append(msgs, rerr.Error())
+			}
+		}
+		if len(msgs) > 0 {
+			return nil, fmt.Errorf("ProvisionProject: %s (rollback also failed: %s)", err, strings.Join(msgs, "; "))
+		}
+		return nil, err
+	}
+
+	out.Project, err = c.CreateProject(&spec.Project)
+	if err != nil {
+		return nil, err
+	}
+	rollback = append(rollback, func() error {
+		return c.DeleteProject(out.Project.ID)
+	})
+
+	for _, params := range spec.Labels {
+		label, err := c.findLabel(params.Name)
+		if err != nil {
+			return fail(err)
+		}
+		if label == nil {
+			p := params
+			label, err = c.CreateLabel(&p)
+			if err != nil {
+				return fail(err)
+			}
+			id := label.ID
+			rollback = append(rollback, func() error {
+				return c.DeleteLabel(id)
+			})
+		}
+		out.Labels = append(out.Labels, *label)
+	}
+
+	if spec.KickoffEpic != nil {
+		params := *spec.KickoffEpic
+		params.Labels = append(append([]CreateLabelParams{}, params.Labels...), spec.Labels...)
+		out.Epic, err = c.CreateEpic(&params)
+		if err != nil {
+			return fail(err)
+		}
+		rollback = append(rollback, func() error {
+			return c.DeleteEpic(out.Epic.ID)
+		})
+	}
+
+	if len(spec.Stories) > 0 {
+		stories := make([]CreateStoryParams, len(spec.Stories))
+		for i, s := range spec.Stories {
+			s.ProjectID = out.Project.ID
+			if out.Epic != nil {
+				s.EpicID = out.Epic.ID
+			}
+			s.Labels = append(append([]CreateLabelParams{}, s.Labels...), spec.Labels...)
+			stories[i] = s
+		}
+		out.Stories, err = c.CreateStories(stories)
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,83 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProvisionProjectRollback(t *testing.T) {
+	requests := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		requests = append(requests, key)
+		switch key {
+		case "POST /v2/projects":
+			w.Write([]byte(`{"id":1,"name":"Mobile"}`))
+		case "GET /v2/labels":
+			w.Write([]byte(`[]`))
+		case "POST /v2/labels":
+			w.Write([]byte(`{"id":10,"name":"mobile"}`))
+		case "DELETE /v2/projects/1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			// the epic, and deleting the label
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	_, err := c.ProvisionProject(ProjectSpec{
+		Project:     CreateProjectParams{Name: "Mobile"},
+		Labels:      []CreateLabelParams{{Name: "mobile"}},
+		KickoffEpic: &CreateEpicParams{Name: "Kickoff"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "rollback also failed") {
+		t.Fatalf("expected the rollback failure to be reported, got %v", err)
+	}
+	expect := []string{
+		"POST /v2/projects",
+		"GET /v2/labels",
+		"POST /v2/labels",
+		"POST /v2/epics",
+		"DELETE /v2/labels/10",
+		"DELETE /v2/projects/1",
+	}
+	if !reflect.DeepEqual(requests, expect) {
+		t.Errorf("expected %v, got %v", expect, requests)
+	}
+}
+
+func TestEnsureLabelRace(t *testing.T) {
+	lists := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v2/labels":
+			lists++
+			if lists == 1 {
+				w.Write([]byte(`[]`))
+				return
+			}
+			// the label another client created in the meantime
+			w.Write([]byte(`[{"id":3,"name":"Bug"}]`))
+		case "POST /v2/labels":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"name already taken"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	label, err := c.EnsureLabel("bug", "#ff0000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label.ID != 3 || lists != 2 {
+		t.Errorf("expected the winner of the race after listing again, got %+v after %d lists", label, lists)
+	}
+}
@@ -0,0 +1,120 @@
+package clubhouse
+
+import (
+	"reflect"
+)
+
+// UnknownEnumHandler is called when a response contains a value for
+// one of the enum types (StoryType, State, WorkflowStateType,
+// StoryVerb, HealthStatus, KeyResultType) that this package doesn't
+// know about. field is the qualified field name, e.g.
+// "Story.StoryType", and value is the raw value. The value is always
+// kept on the decoded resource as-is; the handler is just a signal that
+// the API has grown a new value.
+type UnknownEnumHandler func(field string, value string)
+
+type enum interface {
+	known() bool
+}
+
+// enumFielder is implemented by resources with enum fields that are
+// declared as plain strings, to keep them compatible.
+type enumFielder interface {
+	enumFields() map[string]enum
+}
+
+func (s WorkflowState) enumFields() map[string]enum {
+	return map[string]enum{"Type": s.StateType()}
+}
+
+func (t StoryType) known() bool {
+	switch t {
+	case "", StoryTypeBug, StoryTypeChore, StoryTypeFeature:
+		return true
+	}
+	return false
+}
+
+func (s State) known() bool {
+	switch s {
+	case "", StateDone, StateInProgress, StateToDo:
+		return true
+	}
+	return false
+}
+
+func (t WorkflowStateType) known() bool {
+	switch t {
+	case "", WorkflowStateTypeUnstarted, WorkflowStateTypeStarted, WorkflowStateTypeDone:
+		return true
+	}
+	return false
+}
+
+func (v StoryVerb) known() bool {
+	switch v {
+	case "", VerbBlocks, VerbDuplicates, VerbRelatesTo:
+		return true
+	}
+	return false
+}
+
+func (s HealthStatus) known() bool {
+	switch s {
+	case "", HealthOnTrack, HealthAtRisk, HealthOffTrack, HealthNone:
+		return true
+	}
+	return false
+}
+
+func (t KeyResultType) known() bool {
+	switch t {
+	case "", KeyResultNumeric, KeyResultPercent, KeyResultBoolean:
+		return true
+	}
+	return false
+}
+
+var enumType = reflect.TypeOf((*enum)(nil)).Elem()
+
+// checkEnums walks a decoded resource and calls handler for every enum
+// field holding a value that isn't known.
+func checkEnums(resource interface{}, handler UnknownEnumHandler) {
+	walkEnums(reflect.ValueOf(resource), "", handler)
+}
+
+func walkEnums(v reflect.Value, field string, handler UnknownEnumHandler) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			walkEnums(v.Elem(), field, handler)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkEnums(v.Index(i), field, handler)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		if f, ok := v.Interface().(enumFielder); ok {
+			for name, e := range f.enumFields() {
+				if !e.known() {
+					handler(t.Name()+"."+name, reflect.ValueOf(e).String())
+				}
+			}
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			walkEnums(v.Field(i), t.Name()+"."+f.Name, handler)
+		}
+	case reflect.String:
+		if !v.Type().Implements(enumType) {
+			return
+		}
+		if !v.Interface().(enum).known() {
+			handler(field, v.String())
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package clubhouse
+
+import "testing"
+
+func TestCheckEnums(t *testing.T) {
+	unknown := map[string]string{}
+	handler := func(field, value string) {
+		unknown[field] = value
+	}
+	stories := []Story{
+		{StoryType: StoryTypeBug},
+		{StoryType: "spike"},
+	}
+	workflow := &Workflow{
+		States: []WorkflowState{
+			{Type: "started"},
+			{Type: "blocked"},
+		},
+	}
+	checkEnums(&stories, handler)
+	checkEnums(&workflow, handler)
+
+	if len(unknown) != 2 {
+		t.Fatalf("expected 2 unknown values, got %v", unknown)
+	}
+	if unknown["Story.StoryType"] != "spike" {
+		t.Error("expected spike to be reported, got", unknown)
+	}
+	if unknown["WorkflowState.Type"] != "blocked" {
+		t.Error("expected blocked to be reported, got", unknown)
+	}
+	if workflow.States[0].StateType() != WorkflowStateTypeStarted {
+		t.Error("expected StateType to return the typed value")
+	}
+}
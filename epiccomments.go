@@ -0,0 +1,145 @@
+package clubhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+)
+
+// EpicCommentLoader loads an epic's comments lazily: nothing is fetched
+// until Each or All is called. Get one with Client.EpicComments.
+type EpicCommentLoader struct {
+	client *Client
+	epicID int
+
+	mu       sync.Mutex
+	comments []ThreadedComment
+}
+
+// EpicComments returns a lazy loader for an epic's comments.
+func (c *Client) EpicComments(epicID int) *EpicCommentLoader {
+	return &EpicCommentLoader{client: c, epicID: epicID}
+}
+
+// All fetches every comment on the epic. The result is kept, so later
+// calls to All and Each don't make another request.
+func (l *EpicCommentLoader) All() ([]ThreadedComment, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.comments != nil {
+		return l.comments, nil
+	}
+	comments, err := l.client.ListEpicComments(l.epicID)
+	if err != nil {
+		return nil, err
+	}
+	l.comments = comments
+	return comments, nil
+}
+
+// Each calls fn for every top-level comment on the epic. Replies are in
+// each comment's Comments. If the comments haven't been loaded by All,
+// the response is decoded one comment at a time instead of all at once.
+// If fn returns an error, iteration stops and the error is returned.
+func (l *EpicCommentLoader) Each(fn func(ThreadedComment) error) error {
+	l.mu.Lock()
+	loaded := l.comments
+	l.mu.Unlock()
+	if loaded != nil {
+		for _, comment := range loaded {
+			if err := fn(comment); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	uri := path.Join("epics", itoa(l.epicID), "comments")
+	body, err := l.client.streamHTTPRequest(context.Background(), "GET", uri)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	dec := json.NewDecoder(body)
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("EpicComments: error reading response: %s", err)
+	}
+	for dec.More() {
+		comment := ThreadedComment{}
+		if err := dec.Decode(&comment); err != nil {
+			return fmt.Errorf("EpicComments: error decoding comment: %s", err)
+		}
+		if err := fn(comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipJSON decodes any JSON value into nothing.
+type skipJSON struct{}
+
+func (skipJSON) UnmarshalJSON([]byte) error { return nil }
+
+// epicWithoutComments decodes an Epic while skipping its comments. The
+// outer Comments field hides Epic.Comments from the decoder.
+type epicWithoutComments struct {
+	Epic
+	Comments skipJSON `json:"comments"`
+}
+
+// ListEpicsWithoutComments is like ListEpics but doesn't decode comment
+// bodies, which makes it much lighter for epics with long discussions.
+// Use EpicComments to load the comments of an epic when they're needed.
+func (c *Client) ListEpicsWithoutComments() ([]Epic, error) {
+	resource := []epicWithoutComments{}
+	uri := "epics"
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	epics := make([]Epic, len(resource))
+	for i, e := range resource {
+		epics[i] = e.Epic
+	}
+	return epics, nil
+}
+
+// EachEpic calls fn for every epic, without its comments. The response
+// is streamed and decoded one epic at a time, so the whole list is
+// never held in memory. If fn returns an error, iteration stops and the
+// error is returned.
+func (c *Client) EachEpic(fn func(Epic) error) error {
+	body, err := c.streamHTTPRequest(context.Background(), "GET", "epics")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	dec := json.NewDecoder(body)
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("EachEpic: error reading response: %s", err)
+	}
+	for dec.More() {
+		epic := epicWithoutComments{}
+		if err := dec.Decode(&epic); err != nil {
+			return fmt.Errorf("EachEpic: error decoding epic: %s", err)
+		}
+		if err := fn(epic.Epic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEpicWithoutComments is like GetEpic but doesn't decode comments.
+func (c *Client) GetEpicWithoutComments(id int) (*Epic, error) {
+	resource := epicWithoutComments{}
+	uri := path.Join("epics", itoa(id))
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resource.Epic, nil
+}
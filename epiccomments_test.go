@@ -0,0 +1,86 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEpicComments(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/v2/epics":
+			w.Write([]byte(`[{"id":1,"name":"epic","comments":[{"id":9,"text":"long"}]}]`))
+		case "/v2/epics/1/comments":
+			w.Write([]byte(`[{"id":9,"text":"one","comments":[{"id":10,"text":"reply"}]},{"id":11,"text":"two"}]`))
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	epics, err := c.ListEpicsWithoutComments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(epics) != 1 || epics[0].Name != "epic" || epics[0].Comments != nil {
+		t.Errorf("expected epic without comments, got %+v", epics)
+	}
+
+	loader := c.EpicComments(1)
+	if requests != 1 {
+		t.Errorf("expected loader not to make a request, got %d", requests)
+	}
+	ids := []int{}
+	err = loader.Each(func(comment ThreadedComment) error {
+		ids = append(ids, comment.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != 9 || ids[1] != 11 {
+		t.Errorf("unexpected comments %v", ids)
+	}
+
+	all, err := loader.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader.All()
+	if len(all) != 2 || len(all[0].Comments) != 1 || requests != 3 {
+		t.Errorf("expected All to load once, got %d comments after %d requests", len(all), requests)
+	}
+}
+
+func TestEachEpic(t *testing.T) {
+	status := 200
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`[{"id":1,"comments":[{"id":9}]},{"id":2}]`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), MaxResponseBytes: 8}
+
+	ids := []int{}
+	err := c.EachEpic(func(e Epic) error {
+		if e.Comments != nil {
+			t.Errorf("expected epic %d without comments", e.ID)
+		}
+		ids = append(ids, e.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[1] != 2 {
+		t.Errorf("unexpected epics %v", ids)
+	}
+
+	status = 403
+	err = c.EachEpic(func(Epic) error { return nil })
+	if e, ok := err.(ErrClientRequest); !ok || e.Err != ErrForbidden {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package clubhouse
+
+import (
+	"errors"
+	"sort"
+)
+
+// StartEpic moves an epic to "in progress", clearing its completed date
+// override.
+//
+// Clubhouse fills in an epic's started_at and completed_at when its
+// state changes, but an override takes precedence over both, so an epic
+// that's moved back to an earlier state keeps showing the override's
+// date unless it's cleared too. StartEpic, CompleteEpic and ReopenEpic
+// change the state and clear any override that no longer makes sense.
+// Overrides that still do, e.g. a backdated start on an epic being
+// completed, are left alone.
+//
+// Each sets the fixed State and, when the workspace has an epic
+// workflow, EpicStateID to the first state of the matching type from
+// GetEpicWorkflow, so the two agree.
+func (c *Client) StartEpic(id int) (*Epic, error) {
+	return c.moveEpic(id, WorkflowStateTypeStarted, UpdateEpicParams{
+		State:               StateInProgress,
+		CompletedAtOverride: ResetTime,
+	})
+}
+
+// CompleteEpic moves an epic to "done". See StartEpic.
+func (c *Client) CompleteEpic(id int) (*Epic, error) {
+	return c.moveEpic(id, WorkflowStateTypeDone, UpdateEpicParams{
+		State: StateDone,
+	})
+}
+
+// ReopenEpic moves an epic back to "to do", clearing both its started
+// and completed date overrides. See StartEpic.
+func (c *Client) ReopenEpic(id int) (*Epic, error) {
+	return c.moveEpic(id, WorkflowStateTypeUnstarted, UpdateEpicParams{
+		State:               StateToDo,
+		StartedAtOverride:   ResetTime,
+		CompletedAtOverride: ResetTime,
+	})
+}
+
+// moveEpic updates an epic with params, adding the EpicStateID for
+// stateType. Workspaces without an epic workflow, where the endpoint
+// doesn't exist, only get the State.
+func (c *Client) moveEpic(id int, stateType WorkflowStateType, params UpdateEpicParams) (*Epic, error) {
+	workflow, err := c.GetEpicWorkflow()
+	switch {
+	case errors.Is(err, ErrResourceNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		if stateID, ok := epicStateOfType(workflow, stateType); ok {
+			params.EpicStateID = &stateID
+		}
+	}
+	return c.UpdateEpic(id, params)
+}
+
+// epicStateOfType picks the state of type t for the helpers: the
+// workflow's default state if it has that type, otherwise the first one
+// in position order.
+func epicStateOfType(workflow *EpicWorkflow, t WorkflowStateType) (int, bool) {
+	states := append([]EpicState{}, workflow.EpicStates...)
+	sort.SliceStable(states, func(i, j int) bool { return states[i].Position < states[j].Position })
+	found, id := false, 0
+	for _, s := range states {
+		if s.Type != t {
+			continue
+		}
+		if s.ID == workflow.DefaultEpicStateID {
+			return s.ID, true
+		}
+		if !found {
+			found, id = true, s.ID
+		}
+	}
+	return id, found
+}
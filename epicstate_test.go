@@ -0,0 +1,71 @@
+package clubhouse
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEpicStateHelpers(t *testing.T) {
+	var body string
+	workflow := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/epic-workflow" {
+			if workflow == "" {
+				w.WriteHeader(404)
+				return
+			}
+			w.Write([]byte(workflow))
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	// without an epic workflow, only State is set
+	tests := []struct {
+		name string
+		fn   func(int) (*Epic, error)
+		want string
+	}{
+		{"start", c.StartEpic, `{"completed_at_override":null,"state":"in progress"}`},
+		{"complete", c.CompleteEpic, `{"state":"done"}`},
+		{"reopen", c.ReopenEpic, `{"completed_at_override":null,"started_at_override":null,"state":"to do"}`},
+	}
+	for _, tt := range tests {
+		if _, err := tt.fn(1); err != nil {
+			t.Fatal(err)
+		}
+		if body != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, body)
+		}
+	}
+
+	workflow = `{"default_epic_state_id":11,"epic_states":[
+		{"id":12,"type":"unstarted","position":2},
+		{"id":11,"type":"unstarted","position":3},
+		{"id":22,"type":"started","position":5},
+		{"id":21,"type":"started","position":4},
+		{"id":31,"type":"done","position":6}]}`
+	tests = []struct {
+		name string
+		fn   func(int) (*Epic, error)
+		want string
+	}{
+		{"start", c.StartEpic, `{"completed_at_override":null,"epic_state_id":21,"state":"in progress"}`},
+		{"complete", c.CompleteEpic, `{"epic_state_id":31,"state":"done"}`},
+		{"reopen", c.ReopenEpic, `{"completed_at_override":null,"epic_state_id":11,"started_at_override":null,"state":"to do"}`},
+	}
+	for _, tt := range tests {
+		if _, err := tt.fn(1); err != nil {
+			t.Fatal(err)
+		}
+		if body != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, body)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package clubhouse
+
+import "path"
+
+// epicStatsListThreshold is the number of epics at which GetEpicsStats
+// lists every epic in one request instead of getting each one.
+var epicStatsListThreshold = 5
+
+// epicStatsOnly decodes just the stats of an epic.
+type epicStatsOnly struct {
+	ID    int       `json:"id"`
+	Stats EpicStats `json:"stats"`
+}
+
+// GetEpicsStats fetches the stats of several epics, keyed by epic ID.
+// Only the stats are decoded, so it's cheap enough for dashboards that
+// refresh lots of progress bars every minute. For a handful of epics
+// each one is fetched directly, up to BulkConcurrency at a time; for
+// more, every epic is listed in a single request.
+//
+// If some of the epics couldn't be fetched (or don't exist), the stats
+// that were fetched are returned along with an ErrBulk.
+func (c *Client) GetEpicsStats(ids []int) (map[int]EpicStats, error) {
+	stats := map[int]EpicStats{}
+	if len(ids) >= epicStatsListThreshold {
+		all := []epicStatsOnly{}
+		if err := c.RequestResource("GET", &all, "epics", nil); err != nil {
+			return nil, err
+		}
+		byID := map[int]EpicStats{}
+		for _, e := range all {
+			byID[e.ID] = e.Stats
+		}
+		var errs map[int]error
+		for _, id := range ids {
+			s, ok := byID[id]
+			if !ok {
+				if errs == nil {
+					errs = map[int]error{}
+				}
+				errs[id] = ErrResourceNotFound
+				continue
+			}
+			stats[id] = s
+		}
+		if errs != nil {
+			return stats, ErrBulk{Errors: errs}
+		}
+		return stats, nil
+	}
+
+	fetched := make([]EpicStats, len(ids))
+	result := c.bulk("GetEpicsStats", "epics", ids, func(i, id int) error {
+		epic := epicStatsOnly{}
+		err := c.RequestResource("GET", &epic, path.Join("epics", itoa(id)), nil)
+		fetched[i] = epic.Stats
+		return err
+	})
+	for i, id := range ids {
+		if _, failed := result.Failed[id]; !failed {
+			stats[id] = fetched[i]
+		}
+	}
+	return stats, result.Err()
+}
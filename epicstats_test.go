@@ -0,0 +1,43 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetEpicsStats(t *testing.T) {
+	paths := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/v2/epics":
+			w.Write([]byte(`[{"id":1,"stats":{"num_points":5}},{"id":2,"stats":{"num_points":8}}]`))
+		case "/v2/epics/1":
+			w.Write([]byte(`{"id":1,"stats":{"num_points":5}}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), BulkConcurrency: 1}
+
+	stats, err := c.GetEpicsStats([]int{1, 3})
+	if _, ok := err.(ErrBulk); !ok {
+		t.Errorf("expected ErrBulk for missing epic, got %v", err)
+	}
+	if len(stats) != 1 || stats[1].NumPoints != 5 || len(paths) != 2 {
+		t.Errorf("expected stats for epic 1 from 2 requests, got %+v from %v", stats, paths)
+	}
+
+	defer func(n int) { epicStatsListThreshold = n }(epicStatsListThreshold)
+	epicStatsListThreshold = 2
+	paths = nil
+	stats, err = c.GetEpicsStats([]int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 || stats[2].NumPoints != 8 || len(paths) != 1 {
+		t.Errorf("expected stats for both epics from 1 request, got %+v from %v", stats, paths)
+	}
+}
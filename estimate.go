@@ -0,0 +1,96 @@
+package clubhouse
+
+import "time"
+
+// DefaultCallLatency is the round-trip time assumed by EstimateCalls
+// when the plan doesn't give one.
+var DefaultCallLatency = 300 * time.Millisecond
+
+// CallPlan describes a big job in terms of the work it does, for
+// EstimateCalls. Zero fields are ignored or take their defaults.
+type CallPlan struct {
+	// GetStories is the number of stories fetched one at a time with
+	// GetStory, e.g. for an export or to hydrate search results.
+	GetStories int
+
+	// SearchResults is the number of stories a search will page
+	// through, SearchPageSize at a time (default 25).
+	SearchResults  int
+	SearchPageSize int
+
+	// BulkStoryUpdates is the number of stories updated with the bulk
+	// stories endpoint, which takes up to 100 at a time.
+	BulkStoryUpdates int
+
+	// SingleUpdates is the number of resources updated one request at
+	// a time, e.g. with UpdateEpics.
+	SingleUpdates int
+
+	// OtherCalls is added to the total as-is.
+	OtherCalls int
+
+	// RequestsPerSecond is the rate the limiter allows. The Limiter
+	// can't be inspected, so set this when using anything other than
+	// DefaultLimiter. Defaults to DefaultRequestsPerSecond.
+	RequestsPerSecond int
+
+	// Concurrency is the number of requests in flight at once. Defaults
+	// to DefaultBulkConcurrency.
+	Concurrency int
+
+	// Latency is the average round-trip time. Defaults to
+	// DefaultCallLatency.
+	Latency time.Duration
+}
+
+// CallEstimate is the result of EstimateCalls.
+type CallEstimate struct {
+	Calls    int
+	Duration time.Duration
+
+	// RateLimited is true when the limiter, rather than latency, is
+	// what bounds the duration.
+	RateLimited bool
+}
+
+// EstimateCalls estimates how many API calls a job will make and how
+// long it will take, so big jobs can be scheduled without guessing
+// whether they'll finish overnight. It doesn't account for retries.
+func EstimateCalls(plan CallPlan) CallEstimate {
+	pageSize := plan.SearchPageSize
+	if pageSize <= 0 {
+		pageSize = 25
+	}
+	rps := plan.RequestsPerSecond
+	if rps <= 0 {
+		rps = DefaultRequestsPerSecond
+	}
+	concurrency := plan.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+	latency := plan.Latency
+	if latency <= 0 {
+		latency = DefaultCallLatency
+	}
+
+	calls := plan.GetStories +
+		ceilDiv(plan.SearchResults, pageSize) +
+		ceilDiv(plan.BulkStoryUpdates, MaxBulkItems) +
+		plan.SingleUpdates +
+		plan.OtherCalls
+
+	limited := time.Duration(calls) * time.Second / time.Duration(rps)
+	unlimited := time.Duration(ceilDiv(calls, concurrency)) * latency
+	if limited >= unlimited {
+		return CallEstimate{Calls: calls, Duration: limited, RateLimited: true}
+	}
+	return CallEstimate{Calls: calls, Duration: unlimited}
+}
+
+func ceilDiv(n, d int) int {
+	if n <= 0 {
+		return 0
+	}
+	return (n + d - 1) / d
+}
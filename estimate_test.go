@@ -0,0 +1,36 @@
+package clubhouse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateCalls(t *testing.T) {
+	est := EstimateCalls(CallPlan{
+		GetStories:       600,
+		SearchResults:    600,
+		BulkStoryUpdates: 250,
+		SingleUpdates:    10,
+	})
+	// 600 + 24 pages + 3 chunks + 10
+	if est.Calls != 637 {
+		t.Errorf("expected 637 calls, got %d", est.Calls)
+	}
+	if !est.RateLimited || est.Duration != 637*time.Second/3 {
+		t.Errorf("expected rate limited duration, got %+v", est)
+	}
+
+	est = EstimateCalls(CallPlan{
+		OtherCalls:        10,
+		RequestsPerSecond: 100,
+		Concurrency:       2,
+		Latency:           time.Second,
+	})
+	if est.RateLimited || est.Duration != 5*time.Second {
+		t.Errorf("expected latency bound duration of 5s, got %+v", est)
+	}
+
+	if est := EstimateCalls(CallPlan{}); est.Calls != 0 || est.Duration != 0 {
+		t.Errorf("expected empty estimate, got %+v", est)
+	}
+}
@@ -0,0 +1,131 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CommentRecord is a line of ExportComments output.
+type CommentRecord struct {
+	StoryID    int       `json:"story_id"`
+	CommentID  int       `json:"comment_id"`
+	AuthorID   string    `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Text       string    `json:"text"`
+}
+
+// ExportComments writes every comment on the given stories to w as
+// JSON lines (see CommentRecord), with authors resolved to their names.
+// Stories are fetched up to BulkConcurrency at a time, and written in
+// the order of storyIDs with each story's comments oldest first.
+//
+// If some stories couldn't be fetched, the comments on the rest are
+// still written and an ErrBulk describing the failures is returned.
+func (c *Client) ExportComments(storyIDs []int, w io.Writer) error {
+	return c.ExportCommentsWithLinks(storyIDs, w, nil)
+}
+
+// ExportCommentsWithLinks is ExportComments with links to files in the
+// comment text rewritten by links.
+func (c *Client) ExportCommentsWithLinks(storyIDs []int, w io.Writer, links FileLinks) error {
+	members, err := c.ListMembers()
+	if err != nil {
+		return err
+	}
+	names := map[string]string{}
+	for _, m := range members {
+		names[m.ID] = m.Profile.Name
+	}
+
+	stories := make([]*Story, len(storyIDs))
+	result := c.bulk("ExportComments", "stories", storyIDs, func(i, id int) error {
+		story, err := c.GetStory(id)
+		stories[i] = story
+		return err
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, story := range stories {
+		if story == nil {
+			continue
+		}
+		comments := append([]Comment{}, story.Comments...)
+		sort.SliceStable(comments, func(i, j int) bool {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		})
+		for _, comment := range comments {
+			err := enc.Encode(CommentRecord{
+				StoryID:    story.ID,
+				CommentID:  comment.ID,
+				AuthorID:   comment.AuthorID,
+				AuthorName: names[comment.AuthorID],
+				CreatedAt:  comment.CreatedAt,
+				UpdatedAt:  comment.UpdatedAt,
+				Text:       links.Rewrite(comment.Text),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return result.Err()
+}
+
+// FileLinks maps the URLs of Clubhouse files to somewhere else, usually
+// the local paths they were downloaded to, so exported text still shows
+// images and links to attachments once the workspace, or the token the
+// URLs were signed for, is gone.
+type FileLinks map[string]string
+
+// NewFileLinks maps the URL of each file to localPath(f). With MirrorKey
+// as localPath, links point to where MirrorFiles put the files.
+func NewFileLinks(files []File, localPath func(File) string) FileLinks {
+	links := FileLinks{}
+	for _, f := range files {
+		if f.URL != "" {
+			links[stripURLQuery(f.URL)] = localPath(f)
+		}
+	}
+	return links
+}
+
+var linkURLPattern = regexp.MustCompile(`https?://[^\s()<>"'\]]+`)
+
+// Rewrite replaces every URL in text that's in links, ignoring any
+// query string or fragment on it. Other URLs are left alone.
+func (links FileLinks) Rewrite(text string) string {
+	if len(links) == 0 {
+		return text
+	}
+	return linkURLPattern.ReplaceAllStringFunc(text, func(u string) string {
+		// punctuation ending a sentence isn't part of the URL
+		trimmed := strings.TrimRight(u, ".,;:!")
+		if local, ok := links[stripURLQuery(trimmed)]; ok {
+			return local + u[len(trimmed):]
+		}
+		return u
+	})
+}
+
+// RewriteStory rewrites links in the description and comments of story
+// in place.
+func (links FileLinks) RewriteStory(story *Story) {
+	story.Description = links.Rewrite(story.Description)
+	for i := range story.Comments {
+		story.Comments[i].Text = links.Rewrite(story.Comments[i].Text)
+	}
+}
+
+func stripURLQuery(u string) string {
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		return u[:i]
+	}
+	return u
+}
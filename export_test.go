@@ -0,0 +1,79 @@
+package clubhouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExportComments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/members":
+			w.Write([]byte(`[{"id":"u1","profile":{"name":"Ada"}}]`))
+		case "/v2/stories/1":
+			w.Write([]byte(`{"id":1,"comments":[
+				{"id":11,"author_id":"u1","text":"second","created_at":"2019-01-02T00:00:00Z"},
+				{"id":10,"author_id":"u2","text":"<first>","created_at":"2019-01-01T00:00:00Z"}]}`))
+		case "/v2/stories/2":
+			w.Write([]byte(`{"id":2,"comments":[{"id":20,"author_id":"u1","text":"third"}]}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	var buf bytes.Buffer
+	err := c.ExportComments([]int{2, 3, 1}, &buf)
+	if e, ok := err.(ErrBulk); !ok || e.Errors[3] == nil {
+		t.Errorf("expected ErrBulk for story 3, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", buf.String())
+	}
+	records := make([]CommentRecord, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ids := []int{records[0].CommentID, records[1].CommentID, records[2].CommentID}
+	if ids[0] != 20 || ids[1] != 10 || ids[2] != 11 {
+		t.Errorf("expected comments 20, 10, 11, got %v", ids)
+	}
+	if records[0].StoryID != 2 || records[0].AuthorName != "Ada" || records[1].AuthorName != "" {
+		t.Errorf("unexpected records %+v", records)
+	}
+	if !strings.Contains(lines[1], `"text":"<first>"`) {
+		t.Errorf("expected text to be unescaped, got %s", lines[1])
+	}
+}
+
+func TestFileLinksRewrite(t *testing.T) {
+	links := NewFileLinks([]File{
+		{ID: 1, Name: "a.png", URL: "https://media.example/files/1/a.png"},
+		{ID: 2, Name: "b.txt"},
+	}, MirrorKey)
+
+	in := "![a](https://media.example/files/1/a.png?token=secret) and " +
+		"https://media.example/files/1/a.png. Not https://example.com/other."
+	want := "![a](files/1/a.png) and files/1/a.png. Not https://example.com/other."
+	if got := links.Rewrite(in); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	story := &Story{
+		Description: "<https://media.example/files/1/a.png>",
+		Comments:    []Comment{{Text: "see https://media.example/files/1/a.png"}},
+	}
+	links.RewriteStory(story)
+	if story.Description != "<files/1/a.png>" || story.Comments[0].Text != "see files/1/a.png" {
+		t.Errorf("unexpected story %+v", story)
+	}
+}
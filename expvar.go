@@ -0,0 +1,96 @@
+package clubhouse
+
+import (
+	"expvar"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// expvarMu guards publishing, since expvar.Publish panics if a name is
+// published twice.
+var expvarMu sync.Mutex
+
+// clientVars returns the expvar map for the client, publishing it the
+// first time. Clients with the same ExpvarName share counters.
+func (c *Client) clientVars() *expvar.Map {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if v, ok := expvar.Get(c.ExpvarName).(*expvar.Map); ok {
+		return v
+	}
+	vars := expvar.NewMap(c.ExpvarName)
+	for _, name := range []string{"requests", "errors", "created", "updated", "deleted"} {
+		vars.Set(name, new(expvar.Map).Init())
+	}
+	return vars
+}
+
+// recordExpvar counts a request, and the entity it changed, when
+// ExpvarName is set. Requests are counted by method and endpoint
+// pattern, e.g. "GET stories/:id"; changes are counted by resource,
+// e.g. "stories".
+func (c *Client) recordExpvar(method, endpoint string, err error) {
+	if c.ExpvarName == "" {
+		return
+	}
+	pattern, resource := endpointResource(endpoint)
+	vars := c.clientVars()
+	key := method + " " + pattern
+	vars.Get("requests").(*expvar.Map).Add(key, 1)
+	if err != nil {
+		vars.Get("errors").(*expvar.Map).Add(key, 1)
+		return
+	}
+	switch method {
+	case "POST":
+		vars.Get("created").(*expvar.Map).Add(resource, 1)
+	case "PUT":
+		vars.Get("updated").(*expvar.Map).Add(resource, 1)
+	case "DELETE":
+		vars.Get("deleted").(*expvar.Map).Add(resource, 1)
+	}
+}
+
+// endpointKeywords are the endpoint segments that name an action on a
+// collection, e.g. stories/bulk, rather than a resource or an ID.
+var endpointKeywords = map[string]bool{
+	"bulk":    true,
+	"disable": true,
+	"enable":  true,
+	"search":  true,
+}
+
+// endpointResource returns endpoint with its query removed and IDs
+// replaced by ":id", and the name of the resource it acts on. Nested
+// resources, e.g. stories/:id/comments, are the innermost resource.
+//
+// Endpoints alternate between collections and IDs, so the segment after
+// a collection is taken to be an ID whether it's a number or a UUID, as
+// for groups and key-results, unless it's one of endpointKeywords.
+func endpointResource(endpoint string) (pattern string, resource string) {
+	if i := strings.Index(endpoint, "?"); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+	parts := strings.Split(endpoint, "/")
+	afterCollection := false
+	for i, p := range parts {
+		_, err := strconv.Atoi(p)
+		switch {
+		case p == "":
+			continue
+		case err == nil, afterCollection && !endpointKeywords[p]:
+			parts[i] = ":id"
+			afterCollection = false
+		case endpointKeywords[p]:
+			afterCollection = false
+		default:
+			afterCollection = true
+			resource = p
+		}
+	}
+	if resource == "" {
+		resource = parts[len(parts)-1]
+	}
+	return strings.Join(parts, "/"), resource
+}
@@ -0,0 +1,45 @@
+package clubhouse
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExpvar(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/stories/3" {
+			w.WriteHeader(404)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), ExpvarName: "clubhouse_test"}
+
+	c.GetStory(1)
+	c.GetStory(2)
+	c.GetStory(3)
+	c.CreateStoryComment(1, &CreateCommentParams{Text: "hi"})
+	c.UpdateStories(&UpdateStoriesParams{StoryIDs: []int{1}})
+	c.DeleteEpic(4)
+
+	vars := expvar.Get("clubhouse_test").(*expvar.Map)
+	check := func(group, key, expect string) {
+		v := vars.Get(group).(*expvar.Map).Get(key)
+		if v == nil || v.String() != expect {
+			t.Errorf("%s[%s]: expected %s, got %v", group, key, expect, v)
+		}
+	}
+	check("requests", "GET stories/:id", "3")
+	check("errors", "GET stories/:id", "1")
+	check("created", "comments", "1")
+	check("updated", "stories", "1")
+	check("deleted", "epics", "1")
+
+	// a second client with the same name shares counters
+	other := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), ExpvarName: "clubhouse_test"}
+	other.GetStory(1)
+	check("requests", "GET stories/:id", "4")
+}
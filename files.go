@@ -0,0 +1,76 @@
+package clubhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FileFilter narrows down the files returned by ListFilesFiltered and
+// EachFile. The files endpoint doesn't support filtering, so filters
+// are applied client-side. Empty fields match everything.
+type FileFilter struct {
+	UploaderID   string
+	UpdatedSince time.Time
+
+	// ContentType matches by prefix, so "image/" matches every image.
+	ContentType string
+}
+
+// Match reports whether f passes the filter.
+func (filter FileFilter) Match(f File) bool {
+	if filter.UploaderID != "" && f.UploaderID != filter.UploaderID {
+		return false
+	}
+	if !filter.UpdatedSince.IsZero() && f.UpdatedAt.Before(filter.UpdatedSince) {
+		return false
+	}
+	if filter.ContentType != "" && !strings.HasPrefix(f.ContentType, filter.ContentType) {
+		return false
+	}
+	return true
+}
+
+// ListFilesFiltered returns the files that match filter.
+func (c *Client) ListFilesFiltered(filter FileFilter) ([]File, error) {
+	files := []File{}
+	err := c.EachFile(filter, func(f File) error {
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// EachFile calls fn for every file that matches filter. The response is
+// streamed and decoded one file at a time, so workspaces with thousands
+// of attachments never need the whole list in memory. If fn returns an
+// error, iteration stops and the error is returned.
+func (c *Client) EachFile(filter FileFilter, fn func(File) error) error {
+	body, err := c.streamHTTPRequest(context.Background(), "GET", "files")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	dec := json.NewDecoder(body)
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("EachFile: error reading response: %s", err)
+	}
+	for dec.More() {
+		f := File{}
+		if err := dec.Decode(&f); err != nil {
+			return fmt.Errorf("EachFile: error decoding file: %s", err)
+		}
+		if !filter.Match(f) {
+			continue
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
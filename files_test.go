@@ -0,0 +1,70 @@
+package clubhouse
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileFilterMatch(t *testing.T) {
+	since := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := File{UploaderID: "a", UpdatedAt: since, ContentType: "image/png"}
+	tests := []struct {
+		filter FileFilter
+		expect bool
+	}{
+		{FileFilter{}, true},
+		{FileFilter{UploaderID: "a"}, true},
+		{FileFilter{UploaderID: "b"}, false},
+		{FileFilter{UpdatedSince: since}, true},
+		{FileFilter{UpdatedSince: since.Add(time.Second)}, false},
+		{FileFilter{ContentType: "image/"}, true},
+		{FileFilter{ContentType: "text/"}, false},
+		{FileFilter{UploaderID: "a", ContentType: "text/"}, false},
+	}
+	for _, test := range tests {
+		if got := test.filter.Match(f); got != test.expect {
+			t.Errorf("%+v: expected %v, got %v", test.filter, test.expect, got)
+		}
+	}
+}
+
+func TestEachFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id":1,"content_type":"image/png"},
+			{"id":2,"content_type":"text/plain"},
+			{"id":3,"content_type":"image/gif"},
+			{"id":4,"content_type":"image/jpeg"}]`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	files, err := c.ListFilesFiltered(FileFilter{ContentType: "image/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []int{}
+	for _, f := range files {
+		ids = append(ids, f.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 3, 4}) {
+		t.Errorf("expected the images, got %v", ids)
+	}
+
+	stop := errors.New("stop")
+	seen := 0
+	err = c.EachFile(FileFilter{}, func(f File) error {
+		seen++
+		if f.ID == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || seen != 2 {
+		t.Errorf("expected fn's error to stop after 2 files, got %v after %d", err, seen)
+	}
+}
@@ -0,0 +1,50 @@
+package clubhouse
+
+// APIFlavor is which brand of the API a client talks to. Clubhouse is
+// now Shortcut: the API moved to api.app.shortcut.com, v3 is current,
+// and the token header and app links changed with the name. Legacy
+// Clubhouse workspaces keep working with the old defaults.
+type APIFlavor string
+
+// Valid values for APIFlavor
+const (
+	FlavorClubhouse APIFlavor = "clubhouse"
+	FlavorShortcut  APIFlavor = "shortcut"
+)
+
+// Defaults for clients with Flavor set to FlavorShortcut. The Default*
+// variables in clubhouse.go are the ones for FlavorClubhouse.
+var (
+	ShortcutRootURL     = "https://api.app.shortcut.com/api/"
+	ShortcutAppRootURL  = "https://app.shortcut.com/"
+	ShortcutVersion     = "v3"
+	ShortcutTokenHeader = "Shortcut-Token"
+)
+
+func (c *Client) defaultRootURL() string {
+	if c.Flavor == FlavorShortcut {
+		return ShortcutRootURL
+	}
+	return DefaultRootURL
+}
+
+func (c *Client) defaultVersion() string {
+	if c.Flavor == FlavorShortcut {
+		return ShortcutVersion
+	}
+	return DefaultVersion
+}
+
+func (c *Client) tokenHeader() string {
+	if c.Flavor == FlavorShortcut {
+		return ShortcutTokenHeader
+	}
+	return DefaultTokenHeader
+}
+
+func (c *Client) appRootURL() string {
+	if c.Flavor == FlavorShortcut {
+		return ShortcutAppRootURL
+	}
+	return AppRootURL
+}
@@ -0,0 +1,46 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFlavorShortcut(t *testing.T) {
+	c := &Client{AuthToken: "token", Flavor: FlavorShortcut}
+	c.checkSetup()
+	if u, _ := c.makeURL("stories/1"); u != "https://api.app.shortcut.com/api/v3/stories/1" {
+		t.Errorf("expected the Shortcut API, got %s", u)
+	}
+
+	c.setCurrentMember(&MemberInfo{Workspace: WorkspaceInfo{URLSlug: "acme"}})
+	if u := c.CachedStoryURL(5); u != "https://app.shortcut.com/acme/story/5" {
+		t.Errorf("expected a Shortcut app link, got %s", u)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Shortcut-Token") != "token" || r.Header.Get("Clubhouse-Token") != "" {
+			t.Errorf("expected the Shortcut token header, got %v", r.Header)
+		}
+		if r.URL.Path != "/v3/epics" {
+			t.Errorf("expected v3, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	c = &Client{AuthToken: "token", RootURL: srv.URL, Flavor: FlavorShortcut, Limiter: RateLimiter(0)}
+	if _, err := c.ListEpics(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFlavorClubhouseDefaults(t *testing.T) {
+	c := &Client{AuthToken: "token"}
+	c.checkSetup()
+	if u, _ := c.makeURL("stories/1"); u != "https://api.clubhouse.io/api/v2/stories/1" {
+		t.Errorf("expected the Clubhouse API, got %s", u)
+	}
+	if c.tokenHeader() != "Clubhouse-Token" {
+		t.Errorf("expected the Clubhouse token header, got %s", c.tokenHeader())
+	}
+}
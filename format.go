@@ -0,0 +1,168 @@
+package clubhouse
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// DefaultDeadlineWarning is how close a deadline has to be for
+// FormatStory to color it as due soon.
+var DefaultDeadlineWarning = 3 * 24 * time.Hour
+
+// FormatOptions configures FormatStory.
+type FormatOptions struct {
+	// Members are used to show owners by mention name. Owners that
+	// aren't in it are shown by ID.
+	Members []Member
+
+	// Workflows are used to show the story's state by name. If the state
+	// isn't in any of them, its ID is shown.
+	Workflows []Workflow
+
+	// Color adds ANSI colors for terminals: the state by its type, and
+	// the deadline red when it has passed or yellow when it's within
+	// DeadlineWarning.
+	Color bool
+
+	// DeadlineWarning is how close a deadline counts as due soon. If
+	// zero, DefaultDeadlineWarning is used.
+	DeadlineWarning time.Duration
+
+	// Now is the time deadlines are compared to. If zero, the current
+	// time is used.
+	Now time.Time
+}
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// FormatStory renders a story for a terminal, with its fields in an
+// aligned list under the title, e.g.
+//
+//	#123 Fix the login page
+//	  type      bug
+//	  state     In Progress
+//	  estimate  3
+//	  owners    @brian, @sam
+//	  labels    frontend, urgent
+//	  deadline  2019-06-01 (overdue)
+//
+// Fields that aren't set are left out.
+func FormatStory(story Story, opts FormatOptions) string {
+	paint := func(color, s string) string {
+		if !opts.Color || color == "" {
+			return s
+		}
+		return color + s + ansiReset
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\n", paint(ansiBold, "#"+itoa(story.ID)), story.Name)
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	field := func(name, value string) {
+		fmt.Fprintf(tw, "  %s\t%s\n", name, value)
+	}
+
+	if story.StoryType != "" {
+		field("type", string(story.StoryType))
+	}
+	if story.WorkflowStateID != 0 {
+		state, ok := findWorkflowState(opts.Workflows, story.WorkflowStateID)
+		if ok {
+			field("state", paint(stateColor(state.StateType()), state.Name))
+		} else {
+			field("state", itoa(story.WorkflowStateID))
+		}
+	}
+	if story.Estimate > 0 {
+		field("estimate", itoa(story.Estimate))
+	}
+	if len(story.OwnerIDs) > 0 {
+		field("owners", strings.Join(ownerNames(opts.Members, story.OwnerIDs), ", "))
+	}
+	if len(story.Labels) > 0 {
+		names := make([]string, len(story.Labels))
+		for i, l := range story.Labels {
+			names[i] = l.Name
+		}
+		field("labels", strings.Join(names, ", "))
+	}
+	if !story.Deadline.IsZero() {
+		field("deadline", formatDeadline(story, opts, paint))
+	}
+	if story.Blocked {
+		field("blocked", paint(ansiRed, "yes"))
+	}
+	tw.Flush()
+	return buf.String()
+}
+
+func findWorkflowState(workflows []Workflow, id int) (WorkflowState, bool) {
+	for _, wf := range workflows {
+		for _, s := range wf.States {
+			if s.ID == id {
+				return s, true
+			}
+		}
+	}
+	return WorkflowState{}, false
+}
+
+func stateColor(t WorkflowStateType) string {
+	switch t {
+	case WorkflowStateTypeStarted:
+		return ansiCyan
+	case WorkflowStateTypeDone:
+		return ansiGreen
+	}
+	return ""
+}
+
+// ownerNames returns "@mention" for each owner found in members, and
+// the ID for the rest.
+func ownerNames(members []Member, ids []string) []string {
+	mentions := map[string]string{}
+	for _, m := range members {
+		mentions[m.ID] = m.Profile.MentionName
+	}
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		if mention := mentions[id]; mention != "" {
+			names[i] = "@" + mention
+		} else {
+			names[i] = id
+		}
+	}
+	return names
+}
+
+func formatDeadline(story Story, opts FormatOptions, paint func(color, s string) string) string {
+	date := story.Deadline.Format("2006-01-02")
+	if story.Completed {
+		return date
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	warning := opts.DeadlineWarning
+	if warning == 0 {
+		warning = DefaultDeadlineWarning
+	}
+	switch {
+	case story.Deadline.Before(now):
+		return paint(ansiRed, date+" (overdue)")
+	case story.Deadline.Sub(now) <= warning:
+		return paint(ansiYellow, date+" (due soon)")
+	}
+	return date
+}
@@ -0,0 +1,59 @@
+package clubhouse
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatStory(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	story := Story{
+		ID:              123,
+		Name:            "Fix the login page",
+		StoryType:       StoryTypeBug,
+		WorkflowStateID: 500,
+		Estimate:        3,
+		OwnerIDs:        []string{"u1", "u2"},
+		Labels:          []Label{{Name: "frontend"}, {Name: "urgent"}},
+		Deadline:        now.Add(-time.Hour),
+	}
+	member := Member{ID: "u1"}
+	member.Profile.MentionName = "brian"
+	opts := FormatOptions{
+		Members:   []Member{member},
+		Workflows: []Workflow{{States: []WorkflowState{{ID: 500, Name: "In Progress", Type: "started"}}}},
+		Now:       now,
+	}
+
+	expect := strings.Join([]string{
+		"#123 Fix the login page",
+		"  type      bug",
+		"  state     In Progress",
+		"  estimate  3",
+		"  owners    @brian, u2",
+		"  labels    frontend, urgent",
+		"  deadline  2019-06-01 (overdue)",
+		"",
+	}, "\n")
+	if got := FormatStory(story, opts); got != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, got)
+	}
+
+	opts.Color = true
+	story.Deadline = now.Add(24 * time.Hour)
+	got := FormatStory(story, opts)
+	for _, s := range []string{ansiCyan + "In Progress" + ansiReset, ansiYellow + "2019-06-02 (due soon)" + ansiReset} {
+		if !strings.Contains(got, s) {
+			t.Errorf("expected %q in:\n%s", s, got)
+		}
+	}
+
+	story.Completed = true
+	if got := FormatStory(story, opts); !strings.Contains(got, "deadline  2019-06-02\n") {
+		t.Errorf("expected completed story's deadline not to be flagged:\n%s", got)
+	}
+	if got := FormatStory(Story{ID: 1, Name: "bare"}, FormatOptions{}); got != "#1 bare\n" {
+		t.Errorf("expected unset fields to be left out, got %q", got)
+	}
+}
@@ -0,0 +1,206 @@
+package clubhouse
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// IDConstants is the workspace entities GenerateIDs writes constants
+// for.
+type IDConstants struct {
+	Projects  []Project
+	Workflows []Workflow
+	Labels    []Label
+	Teams     []Team
+	Groups    []Group
+}
+
+// GetIDConstants fetches everything GenerateIDs needs. Archived
+// projects, labels and groups are left out. Groups are only fetched if
+// the API version has them.
+func (c *Client) GetIDConstants() (*IDConstants, error) {
+	ids := IDConstants{}
+	projects, err := c.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		if !p.Archived {
+			ids.Projects = append(ids.Projects, p)
+		}
+	}
+	labels, err := c.ListLabels()
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labels {
+		if !l.Archived {
+			ids.Labels = append(ids.Labels, l)
+		}
+	}
+	if ids.Workflows, err = c.ListWorkflows(); err != nil {
+		return nil, err
+	}
+	if ids.Teams, err = c.ListTeams(); err != nil {
+		return nil, err
+	}
+	groups, err := c.ListGroups()
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	for _, g := range groups {
+		if !g.Archived {
+			ids.Groups = append(ids.Groups, g)
+		}
+	}
+	return &ids, nil
+}
+
+// GenerateIDs writes a gofmt'd Go file for package pkg to w, with a
+// typed constant for each project, workflow state, label, team and
+// group, e.g. ProjectMobileApp ProjectID = 12. Automation can then
+// refer to them by name and fail to compile, rather than misbehave,
+// when one is renamed or removed after regenerating.
+//
+// Names are turned into identifiers by dropping everything but letters
+// and digits and capitalizing each word. State names are prefixed with
+// their workflow's name if there's more than one workflow. When two
+// entities end up with the same identifier, or one ends up with the
+// name of a generated type (e.g. a project called "ID"), their IDs are
+// appended. If that still leaves a collision, GenerateIDs returns an
+// error.
+func GenerateIDs(w io.Writer, pkg string, ids *IDConstants) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by clubhouse gen ids; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	projects := []idConstant{}
+	for _, p := range ids.Projects {
+		projects = append(projects, intConstant("Project"+goIdentifier(p.Name), p.ID))
+	}
+	states := []idConstant{}
+	for _, wf := range ids.Workflows {
+		prefix := "State"
+		if len(ids.Workflows) > 1 {
+			prefix += goIdentifier(wf.Name)
+		}
+		for _, s := range wf.States {
+			states = append(states, intConstant(prefix+goIdentifier(s.Name), s.ID))
+		}
+	}
+	labels := []idConstant{}
+	for _, l := range ids.Labels {
+		labels = append(labels, intConstant("Label"+goIdentifier(l.Name), l.ID))
+	}
+	teams := []idConstant{}
+	for _, t := range ids.Teams {
+		teams = append(teams, intConstant("Team"+goIdentifier(t.Name), t.ID))
+	}
+	groups := []idConstant{}
+	for _, g := range ids.Groups {
+		groups = append(groups, idConstant{
+			name:   "Group" + goIdentifier(g.Name),
+			value:  strconv.Quote(g.ID),
+			suffix: goIdentifier(g.ID),
+		})
+	}
+
+	types := []struct {
+		name, underlying, doc string
+		consts                []idConstant
+	}{
+		{"ProjectID", "int", "the ID of a project", projects},
+		{"WorkflowStateID", "int", "the ID of a workflow state", states},
+		{"LabelID", "int", "the ID of a label", labels},
+		{"TeamID", "int", "the ID of a team", teams},
+		{"GroupID", "string", "the ID of a group", groups},
+	}
+	declared := map[string]bool{}
+	for _, t := range types {
+		declared[t.name] = true
+	}
+	for _, t := range types {
+		if err := writeIDConstants(&buf, t.name, t.underlying, t.doc, t.consts, declared); err != nil {
+			return fmt.Errorf("GenerateIDs: %s", err)
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("GenerateIDs: %s", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// idConstant is a constant called name set to the Go literal value.
+// suffix is added to the name when it would otherwise collide.
+type idConstant struct {
+	name   string
+	value  string
+	suffix string
+}
+
+func intConstant(name string, id int) idConstant {
+	return idConstant{name: name, value: strconv.Itoa(id), suffix: strconv.Itoa(id)}
+}
+
+// writeIDConstants writes typ and its constants to buf. declared holds
+// the identifiers already taken in the file, and gets consts' names
+// added to it.
+func writeIDConstants(buf *bytes.Buffer, typ, underlying, doc string, consts []idConstant, declared map[string]bool) error {
+	fmt.Fprintf(buf, "// %s is %s.\ntype %s %s\n\n", typ, doc, typ, underlying)
+	if len(consts) == 0 {
+		return nil
+	}
+	counts := map[string]int{}
+	for _, c := range consts {
+		counts[c.name]++
+	}
+	for i, c := range consts {
+		if counts[c.name] > 1 || declared[c.name] {
+			consts[i].name += c.suffix
+		}
+	}
+	for _, c := range consts {
+		if declared[c.name] {
+			return fmt.Errorf("%s is declared more than once", c.name)
+		}
+		declared[c.name] = true
+	}
+	sort.Slice(consts, func(i, j int) bool {
+		if consts[i].name != consts[j].name {
+			return consts[i].name < consts[j].name
+		}
+		return consts[i].value < consts[j].value
+	})
+	fmt.Fprintf(buf, "// %s values\nconst (\n", typ)
+	for _, c := range consts {
+		fmt.Fprintf(buf, "\t%s %s = %s\n", c.name, typ, c.value)
+	}
+	fmt.Fprintf(buf, ")\n\n")
+	return nil
+}
+
+// goIdentifier turns a name like "in progress" or "mobile-app v2" into
+// "InProgress" or "MobileAppV2". A name with no letters or digits becomes
+// "Unnamed".
+func goIdentifier(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+	})
+	if len(words) == 0 {
+		return "Unnamed"
+	}
+	var b strings.Builder
+	for _, word := range words {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package clubhouse
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenerateIDs(t *testing.T) {
+	ids := &IDConstants{
+		Projects: []Project{{ID: 12, Name: "mobile-app v2"}, {ID: 3, Name: "API"}},
+		Workflows: []Workflow{
+			{Name: "Engineering", States: []WorkflowState{{ID: 500, Name: "In Progress"}}},
+			{Name: "Design", States: []WorkflowState{{ID: 600, Name: "In Progress"}}},
+		},
+		Labels: []Label{{ID: 7, Name: "bug"}, {ID: 8, Name: "Bug!"}},
+		Groups: []Group{{ID: "5f3c-aa", Name: "Platform"}},
+	}
+	var buf bytes.Buffer
+	if err := GenerateIDs(&buf, "ids", ids); err != nil {
+		t.Fatal(err)
+	}
+	expect := `// Code generated by clubhouse gen ids; DO NOT EDIT.
+
+package ids
+
+// ProjectID is the ID of a project.
+type ProjectID int
+
+// ProjectID values
+const (
+	ProjectAPI         ProjectID = 3
+	ProjectMobileAppV2 ProjectID = 12
+)
+
+// WorkflowStateID is the ID of a workflow state.
+type WorkflowStateID int
+
+// WorkflowStateID values
+const (
+	StateDesignInProgress      WorkflowStateID = 600
+	StateEngineeringInProgress WorkflowStateID = 500
+)
+
+// LabelID is the ID of a label.
+type LabelID int
+
+// LabelID values
+const (
+	LabelBug7 LabelID = 7
+	LabelBug8 LabelID = 8
+)
+
+// TeamID is the ID of a team.
+type TeamID int
+
+// GroupID is the ID of a group.
+type GroupID string
+
+// GroupID values
+const (
+	GroupPlatform GroupID = "5f3c-aa"
+)
+`
+	if buf.String() != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+}
+
+func TestGoIdentifier(t *testing.T) {
+	for name, expect := range map[string]string{
+		"in progress":   "InProgress",
+		"mobile-app v2": "MobileAppV2",
+		"2021 Q1":       "2021Q1",
+		"✨":             "Unnamed",
+	} {
+		if got := goIdentifier(name); got != expect {
+			t.Errorf("goIdentifier(%q): expected %q, got %q", name, expect, got)
+		}
+	}
+}
+
+func TestGenerateIDsTypeCollision(t *testing.T) {
+	ids := &IDConstants{
+		Projects: []Project{{ID: 4, Name: "ID"}},
+		Teams:    []Team{{ID: 9, Name: "ID"}},
+	}
+	var buf bytes.Buffer
+	if err := GenerateIDs(&buf, "ids", ids); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"ProjectID4 ProjectID = 4", "TeamID9 TeamID = 9"} {
+		if !strings.Contains(buf.String(), expect) {
+			t.Errorf("expected %q in\n%s", expect, buf.String())
+		}
+	}
+
+	ids.Projects = append(ids.Projects, Project{ID: 5, Name: "ID4"})
+	if err := GenerateIDs(&buf, "ids", ids); err == nil {
+		t.Error("expected an error for a collision the suffix can't fix")
+	}
+}
@@ -0,0 +1,54 @@
+package clubhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrUnsafeWorkspace is returned by CheckWorkspace and CheckSandbox when
+// the client's token belongs to a workspace it shouldn't be changing.
+type ErrUnsafeWorkspace struct {
+	Reason string
+}
+
+func (e ErrUnsafeWorkspace) Error() string {
+	return "clubhouse: unsafe workspace: " + e.Reason
+}
+
+// CheckWorkspace returns an ErrUnsafeWorkspace unless the client's token
+// belongs to the workspace with the URL slug slug. Call it before doing
+// anything destructive, so that a token for the wrong workspace fails
+// straight away instead of changing it. The member is fetched with
+// CurrentMember.
+func (c *Client) CheckWorkspace(slug string) error {
+	member, err := c.CurrentMember()
+	if err != nil {
+		return err
+	}
+	if member.Workspace.URLSlug != slug {
+		return ErrUnsafeWorkspace{fmt.Sprintf("token is for workspace %q, expected %q",
+			member.Workspace.URLSlug, slug)}
+	}
+	return nil
+}
+
+// CheckSandbox returns an ErrUnsafeWorkspace if the workspace has more
+// than one active member, i.e. if it looks like a team's real workspace
+// rather than a throwaway one for tests or experiments.
+func (c *Client) CheckSandbox() error {
+	members, err := c.ListMembers()
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for _, m := range members {
+		if !m.Disabled {
+			names = append(names, m.Profile.MentionName)
+		}
+	}
+	if len(names) > 1 {
+		return ErrUnsafeWorkspace{fmt.Sprintf("workspace has %d active members: %s",
+			len(names), strings.Join(names, ", "))}
+	}
+	return nil
+}
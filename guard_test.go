@@ -0,0 +1,39 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWorkspaceGuards(t *testing.T) {
+	members := `[{"id": "a", "profile": {"mention_name": "brian"}}, {"id": "b", "disabled": true, "profile": {"mention_name": "old"}}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/member":
+			w.Write([]byte(`{"id": "a", "workspace2": {"url_slug": "sandbox"}}`))
+		case "/v2/members":
+			w.Write([]byte(members))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	if err := c.CheckWorkspace("sandbox"); err != nil {
+		t.Errorf("expected the right workspace to pass, got %v", err)
+	}
+	if err, ok := c.CheckWorkspace("acme").(ErrUnsafeWorkspace); !ok || !strings.Contains(err.Reason, `"sandbox"`) {
+		t.Errorf("expected the wrong workspace to fail, got %v", err)
+	}
+
+	if err := c.CheckSandbox(); err != nil {
+		t.Errorf("expected disabled members to be ignored, got %v", err)
+	}
+	members = `[{"id": "a", "profile": {"mention_name": "brian"}}, {"id": "c", "profile": {"mention_name": "sam"}}]`
+	if err, ok := c.CheckSandbox().(ErrUnsafeWorkspace); !ok || !strings.Contains(err.Reason, "brian, sam") {
+		t.Errorf("expected a shared workspace to fail, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package clubhouse
+
+// MissingPolicy says what the hydration helpers do with IDs that no
+// longer exist, e.g. because the story was deleted partway through a
+// long run.
+type MissingPolicy int
+
+// Valid values for MissingPolicy
+const (
+	// MissingFail reports a missing ID as a failure in the ErrBulk.
+	MissingFail MissingPolicy = iota
+	// MissingSkip leaves missing IDs out of the results.
+	MissingSkip
+	// MissingTombstone puts a tombstone in the results in place of each
+	// missing ID: a resource with only its ID set and an EntityType of
+	// TombstoneEntityType.
+	MissingTombstone
+)
+
+// TombstoneEntityType is the EntityType of tombstones.
+const TombstoneEntityType = "tombstone"
+
+// IsNotFound reports whether err is a 404 from the API.
+func IsNotFound(err error) bool {
+	if e, ok := err.(ErrClientRequest); ok {
+		err = e.Err
+	}
+	return err == ErrResourceNotFound
+}
+
+// HydrateStories fetches the full version of each story, e.g. for slims
+// from a listing or IDs from a search, up to BulkConcurrency at a time.
+// Stories are returned in the same order as ids, with missing ones
+// handled according to policy. If any other fetches failed, the error
+// is an ErrBulk describing which.
+func (c *Client) HydrateStories(ids []int, policy MissingPolicy) ([]Story, error) {
+	return hydrate(c, "HydrateStories", "stories", ids, policy, c.GetStory,
+		func(id int) Story { return Story{ID: id, EntityType: TombstoneEntityType} })
+}
+
+// HydrateEpics fetches each epic, e.g. by following the EpicIDs of
+// stories, in the same way as HydrateStories.
+func (c *Client) HydrateEpics(ids []int, policy MissingPolicy) ([]Epic, error) {
+	return hydrate(c, "HydrateEpics", "epics", ids, policy, c.GetEpic,
+		func(id int) Epic { return Epic{ID: id, EntityType: TombstoneEntityType} })
+}
+
+// HydrateFiles fetches each file, e.g. by following the FileIDs of a
+// story, in the same way as HydrateStories.
+func (c *Client) HydrateFiles(ids []int, policy MissingPolicy) ([]File, error) {
+	return hydrate(c, "HydrateFiles", "files", ids, policy, c.GetFile,
+		func(id int) File { return File{ID: id, EntityType: TombstoneEntityType} })
+}
+
+func hydrate[T any](
+	c *Client,
+	operation string,
+	endpoint string,
+	ids []int,
+	policy MissingPolicy,
+	get func(int) (*T, error),
+	tombstone func(int) T,
+) ([]T, error) {
+	fetched := make([]*T, len(ids))
+	result := c.bulk(operation, endpoint, ids, func(i, id int) error {
+		resource, err := get(id)
+		switch {
+		case err == nil:
+			fetched[i] = resource
+		case IsNotFound(err) && policy == MissingSkip:
+			c.logf(LogInfo, "", "", "%s: skipping missing %s/%d", operation, endpoint, id)
+		case IsNotFound(err) && policy == MissingTombstone:
+			t := tombstone(id)
+			fetched[i] = &t
+		default:
+			return err
+		}
+		return nil
+	})
+
+	resources := []T{}
+	for _, r := range fetched {
+		if r != nil {
+			resources = append(resources, *r)
+		}
+	}
+	return resources, result.Err()
+}
@@ -0,0 +1,41 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHydrateStories(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/stories/1":
+			w.Write([]byte(`{"id":1,"name":"a"}`))
+		case "/v2/stories/3":
+			w.WriteHeader(500)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	stories, err := c.HydrateStories([]int{1, 2}, MissingSkip)
+	if err != nil || len(stories) != 1 || stories[0].Name != "a" {
+		t.Errorf("expected story 1 only, got %+v, %v", stories, err)
+	}
+
+	stories, err = c.HydrateStories([]int{2, 1}, MissingTombstone)
+	if err != nil || len(stories) != 2 || stories[0].ID != 2 || stories[0].EntityType != TombstoneEntityType {
+		t.Errorf("expected tombstone for story 2, got %+v, %v", stories, err)
+	}
+
+	stories, err = c.HydrateStories([]int{1, 2, 3}, MissingFail)
+	e, ok := err.(ErrBulk)
+	if !ok || !IsNotFound(e.Errors[2]) || e.Errors[3] == nil || IsNotFound(e.Errors[3]) {
+		t.Errorf("expected ErrBulk for stories 2 and 3, got %v", err)
+	}
+	if len(stories) != 1 {
+		t.Errorf("expected story 1, got %+v", stories)
+	}
+}
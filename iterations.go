@@ -0,0 +1,154 @@
+package clubhouse
+
+import (
+	"sort"
+)
+
+// IterationAssignScope selects the stories AssignStoriesToIterations
+// places. Only stories that aren't in an iteration, completed or
+// archived are ever moved.
+type IterationAssignScope struct {
+	// Stories narrows the stories by search.
+	Stories SearchQuery
+	// Unscheduled also puts stories without a deadline into the active
+	// iteration, i.e. the started one whose dates include today.
+	Unscheduled bool
+	// DryRun reports what would be assigned without updating anything.
+	DryRun bool
+}
+
+// IterationAssignment is a story AssignStoriesToIterations placed, or
+// would place, in an iteration.
+type IterationAssignment struct {
+	StoryID       int
+	StoryName     string
+	IterationID   int
+	IterationName string
+	// ByDeadline is true when the story's deadline falls in the
+	// iteration, and false when it went to the active iteration.
+	ByDeadline bool
+}
+
+// AssignStoriesToIterations puts every story in scope into the
+// iteration whose dates include its deadline. When iterations overlap,
+// the one that starts first wins. Stories whose deadline isn't in any
+// iteration are left alone, as are stories without a deadline unless
+// scope.Unscheduled is set. Stories are moved with the bulk endpoint,
+// one batch per iteration.
+//
+// The assignments that were made are returned, or in dry-run mode the
+// ones that would have been. If some updates failed, the error is an
+// ErrBulk describing which.
+func (c *Client) AssignStoriesToIterations(scope IterationAssignScope) ([]IterationAssignment, error) {
+	iterations, err := c.ListIterations()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(iterations, func(i, j int) bool {
+		return iterations[i].StartDate.Before(iterations[j].StartDate)
+	})
+	active := activeIteration(iterations, DateOf(c.clock().Now()))
+
+	query := scope.Stories
+	query.Inversions.IsDone = true
+	query.Inversions.IsArchived = true
+	if !scope.Unscheduled {
+		query.HasDeadline = true
+	}
+	stories, err := c.SearchStoriesAll(&SearchParams{PageSize: 25, Query: &query})
+	if err != nil {
+		return nil, err
+	}
+
+	planned := []IterationAssignment{}
+	for _, s := range stories {
+		if s.IterationID != 0 || s.Completed || s.Archived {
+			continue
+		}
+		if s.Deadline.IsZero() {
+			if scope.Unscheduled && active != nil {
+				planned = append(planned, IterationAssignment{s.ID, s.Name, active.ID, active.Name, false})
+			}
+			continue
+		}
+		if it := iterationContaining(iterations, DateOf(s.Deadline)); it != nil {
+			planned = append(planned, IterationAssignment{s.ID, s.Name, it.ID, it.Name, true})
+		}
+	}
+	if scope.DryRun {
+		return planned, nil
+	}
+
+	byIteration := map[int][]int{}
+	order := []int{}
+	for _, a := range planned {
+		if _, ok := byIteration[a.IterationID]; !ok {
+			order = append(order, a.IterationID)
+		}
+		byIteration[a.IterationID] = append(byIteration[a.IterationID], a.StoryID)
+	}
+	errs := map[int]error{}
+	for _, iterationID := range order {
+		ids := byIteration[iterationID]
+		_, err := c.MoveStoriesToIteration(ids, iterationID)
+		if err == nil {
+			continue
+		}
+		// stories after a failed chunk weren't attempted, so anything
+		// that didn't succeed counts as failed
+		succeeded := map[int]bool{}
+		e, _ := err.(ErrBulk)
+		if e.Result != nil {
+			for _, id := range e.Result.Succeeded {
+				succeeded[id] = true
+			}
+		}
+		for _, id := range ids {
+			if succeeded[id] {
+				continue
+			}
+			if storyErr, ok := e.Errors[id]; ok {
+				errs[id] = storyErr
+			} else {
+				errs[id] = err
+			}
+		}
+	}
+
+	assigned := []IterationAssignment{}
+	for _, a := range planned {
+		if _, failed := errs[a.StoryID]; !failed {
+			assigned = append(assigned, a)
+		}
+	}
+	if len(errs) > 0 {
+		return assigned, ErrBulk{Errors: errs}
+	}
+	return assigned, nil
+}
+
+// iterationContaining returns the first iteration whose dates include
+// day, or nil.
+func iterationContaining(iterations []Iteration, day Date) *Iteration {
+	for i, it := range iterations {
+		if iterationIncludes(it, day) {
+			return &iterations[i]
+		}
+	}
+	return nil
+}
+
+// activeIteration returns the started iteration whose dates include
+// today, or nil.
+func activeIteration(iterations []Iteration, today Date) *Iteration {
+	for i, it := range iterations {
+		if it.Status == IterationStatusStarted && iterationIncludes(it, today) {
+			return &iterations[i]
+		}
+	}
+	return nil
+}
+
+func iterationIncludes(it Iteration, day Date) bool {
+	return !day.Before(it.StartDate) && !day.After(it.EndDate)
+}
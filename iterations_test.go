@@ -0,0 +1,73 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAssignStoriesToIterations(t *testing.T) {
+	var mu sync.Mutex
+	moved := map[int][]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v2/iterations":
+			w.Write([]byte(`[
+				{"id":20,"name":"sprint 2","status":"unstarted","start_date":"2019-05-13T00:00:00Z","end_date":"2019-05-24T00:00:00Z"},
+				{"id":10,"name":"sprint 1","status":"started","start_date":"2019-04-29T00:00:00Z","end_date":"2019-05-10T00:00:00Z"}]`))
+		case "GET /v2/search/stories":
+			w.Write([]byte(`{"data":[
+				{"id":1,"name":"due in sprint 1","deadline":"2019-05-10T18:00:00Z"},
+				{"id":2,"name":"due in sprint 2","deadline":"2019-05-13T09:00:00Z"},
+				{"id":3,"name":"already assigned","deadline":"2019-05-13T09:00:00Z","iteration_id":10},
+				{"id":4,"name":"due later","deadline":"2019-07-01T00:00:00Z"},
+				{"id":5,"name":"unscheduled"}]}`))
+		case "PUT /v2/stories/bulk":
+			var body struct {
+				StoryIDs    []int `json:"story_ids"`
+				IterationID int   `json:"iteration_id"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			moved[body.IterationID] = body.StoryIDs
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	clock := NewFakeClock(time.Date(2019, 5, 6, 12, 0, 0, 0, time.UTC))
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0), Clock: clock}
+
+	planned, err := c.AssignStoriesToIterations(IterationAssignScope{Unscheduled: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []IterationAssignment{
+		{1, "due in sprint 1", 10, "sprint 1", true},
+		{2, "due in sprint 2", 20, "sprint 2", true},
+		{5, "unscheduled", 10, "sprint 1", false},
+	}
+	if !reflect.DeepEqual(planned, expect) {
+		t.Errorf("expected %+v, got %+v", expect, planned)
+	}
+	if len(moved) != 0 {
+		t.Fatalf("dry run moved stories: %v", moved)
+	}
+
+	assigned, err := c.AssignStoriesToIterations(IterationAssignScope{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned) != 2 {
+		t.Errorf("expected unscheduled stories to be left alone, got %+v", assigned)
+	}
+	if !reflect.DeepEqual(moved, map[int][]int{10: {1}, 20: {2}}) {
+		t.Errorf("unexpected bulk updates %v", moved)
+	}
+}
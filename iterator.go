@@ -0,0 +1,184 @@
+package clubhouse
+
+import (
+	"context"
+	"path"
+)
+
+// StoryIterator walks the results of a story search, following the
+// search's pages as it goes, so only one page is held in memory at a
+// time.
+//
+//	it := c.Stories(ctx, &SearchParams{Query: &SearchQuery{HasDeadline: true}})
+//	for it.Next() {
+//		fmt.Println(it.Story().Name)
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+//
+// Unlike SearchStoriesAll, stories that move between pages while they're
+// being fetched aren't merged, so they can come up more than once.
+type StoryIterator struct {
+	pager searchPager
+	page  []StorySearch
+	i     int
+}
+
+// Stories starts iterating over the results of a search. params isn't
+// modified. Nothing is fetched until the first call to Next or NextPage.
+func (c *Client) Stories(ctx context.Context, params *SearchParams) *StoryIterator {
+	return &StoryIterator{pager: newSearchPager(c, ctx, "stories", params), i: -1}
+}
+
+// Next moves to the next story, fetching the next page when the current
+// one runs out. It returns false when there are no more stories or a
+// page couldn't be fetched; check Err to tell which.
+func (it *StoryIterator) Next() bool {
+	for {
+		it.i++
+		if it.i < len(it.page) {
+			return true
+		}
+		if !it.NextPage() {
+			return false
+		}
+	}
+}
+
+// Story returns the story Next moved to.
+func (it *StoryIterator) Story() StorySearch {
+	return it.page[it.i]
+}
+
+// NextPage fetches the next page of results, for callers that handle a
+// page at a time; Page returns it. Any stories left on the current page
+// are skipped. It returns false when there are no more pages or a page
+// couldn't be fetched; check Err to tell which.
+func (it *StoryIterator) NextPage() bool {
+	results := SearchResults{}
+	if !it.pager.fetch(&results, &results.Next, &results.Total) {
+		return false
+	}
+	it.page, it.i = results.Data, -1
+	return true
+}
+
+// Page returns the page NextPage fetched.
+func (it *StoryIterator) Page() []StorySearch {
+	return it.page
+}
+
+// Total returns the total number of results the search reported, once
+// a page has been fetched.
+func (it *StoryIterator) Total() int {
+	return it.pager.total
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *StoryIterator) Err() error {
+	return it.pager.err
+}
+
+// EpicIterator walks the results of an epic search a page at a time,
+// like StoryIterator does for stories.
+type EpicIterator struct {
+	pager searchPager
+	page  []EpicSearch
+	i     int
+}
+
+// Epics starts iterating over the results of an epic search. params
+// isn't modified. Nothing is fetched until the first call to Next or
+// NextPage.
+func (c *Client) Epics(ctx context.Context, params *SearchParams) *EpicIterator {
+	return &EpicIterator{pager: newSearchPager(c, ctx, "epics", params), i: -1}
+}
+
+// Next moves to the next epic, fetching the next page when the current
+// one runs out. It returns false when there are no more epics or a page
+// couldn't be fetched; check Err to tell which.
+func (it *EpicIterator) Next() bool {
+	for {
+		it.i++
+		if it.i < len(it.page) {
+			return true
+		}
+		if !it.NextPage() {
+			return false
+		}
+	}
+}
+
+// Epic returns the epic Next moved to.
+func (it *EpicIterator) Epic() EpicSearch {
+	return it.page[it.i]
+}
+
+// NextPage fetches the next page of results; Page returns it. Any epics
+// left on the current page are skipped.
+func (it *EpicIterator) NextPage() bool {
+	results := EpicSearchResults{}
+	if !it.pager.fetch(&results, &results.Next, &results.Total) {
+		return false
+	}
+	it.page, it.i = results.Data, -1
+	return true
+}
+
+// Page returns the page NextPage fetched.
+func (it *EpicIterator) Page() []EpicSearch {
+	return it.page
+}
+
+// Total returns the total number of results the search reported, once
+// a page has been fetched.
+func (it *EpicIterator) Total() int {
+	return it.pager.total
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *EpicIterator) Err() error {
+	return it.pager.err
+}
+
+// searchPager follows the "next" tokens of one of the search endpoints,
+// for the iterators to share.
+type searchPager struct {
+	c      *Client
+	ctx    context.Context
+	uri    string
+	params SearchParams
+
+	total int
+	done  bool
+	err   error
+}
+
+func newSearchPager(c *Client, ctx context.Context, kind string, params *SearchParams) searchPager {
+	return searchPager{c: c, ctx: ctx, uri: path.Join("search", kind), params: *params}
+}
+
+// fetch decodes the next page into results, whose next and total fields
+// are passed alongside it so the pager can move on. It returns false
+// when there are no more pages or one couldn't be fetched.
+func (p *searchPager) fetch(results interface{}, next *string, total *int) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+	if err := p.c.requestResource(p.ctx, "GET", results, p.uri, &p.params); err != nil {
+		p.err = err
+		return false
+	}
+	p.total = *total
+	if *next == "" {
+		p.done = true
+	} else if p.params.Next, p.err = nextPageToken(*next); p.err != nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package clubhouse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestStoryIterator(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data":[{"id":1},{"id":2}],"next":"/api/v2/search/stories?next=p2","total":3}`,
+		"p2": `{"data":[],"next":"/api/v2/search/stories?next=p3","total":3}`,
+		"p3": `{"data":[{"id":3}],"total":3}`,
+	}
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var body SearchParams
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(pages[body.Next]))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	params := &SearchParams{PageSize: 2}
+	it := c.Stories(context.Background(), params)
+	if requests != 0 {
+		t.Error("expected nothing to be fetched up front")
+	}
+	ids := []int{}
+	for it.Next() {
+		ids = append(ids, it.Story().ID)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2, 3}) || it.Total() != 3 {
+		t.Errorf("expected 1, 2, 3 of 3, got %v of %d", ids, it.Total())
+	}
+	if params.Next != "" {
+		t.Error("expected params to be left alone")
+	}
+
+	sizes := []int{}
+	it = c.Stories(context.Background(), params)
+	for it.NextPage() {
+		sizes = append(sizes, len(it.Page()))
+	}
+	if !reflect.DeepEqual(sizes, []int{2, 0, 1}) {
+		t.Errorf("expected pages of 2, 0 and 1, got %v", sizes)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = c.Stories(ctx, params)
+	if it.Next() || it.Err() != context.Canceled {
+		t.Errorf("expected the canceled context to stop the iterator, got %v", it.Err())
+	}
+}
+
+func TestEpicIterator(t *testing.T) {
+	paths := map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths[r.URL.Path] = true
+		var body SearchParams
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Next == "" {
+			w.Write([]byte(`{"data":[{"id":1}],"next":"/api/v2/search/epics?next=p2","total":2}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":2}],"total":2}`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	it := c.Epics(context.Background(), &SearchParams{})
+	ids := []int{}
+	for it.Next() {
+		ids = append(ids, it.Epic().ID)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2}) || it.Total() != 2 {
+		t.Errorf("expected 1, 2 of 2, got %v of %d", ids, it.Total())
+	}
+	if !reflect.DeepEqual(paths, map[string]bool{"/v2/search/epics": true}) {
+		t.Errorf("expected only epic searches, got %v", paths)
+	}
+}
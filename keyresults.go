@@ -0,0 +1,100 @@
+package clubhouse
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+)
+
+// Value is a typed key result value. Make one with NumericValue,
+// PercentValue or BoolValue.
+type Value struct {
+	kind    KeyResultType
+	number  float64
+	boolean bool
+}
+
+// NumericValue is a value for a numeric key result.
+func NumericValue(n float64) Value {
+	return Value{kind: KeyResultNumeric, number: n}
+}
+
+// PercentValue is a value for a percent key result, from 0 to 100.
+func PercentValue(p float64) Value {
+	return Value{kind: KeyResultPercent, number: p}
+}
+
+// BoolValue is a value for a boolean key result.
+func BoolValue(b bool) Value {
+	return Value{kind: KeyResultBoolean, boolean: b}
+}
+
+// Type is the kind of key result the value is for.
+func (v Value) Type() KeyResultType {
+	return v.kind
+}
+
+// check returns an error if v can't be used for a key result of type t.
+func (v Value) check(t KeyResultType) error {
+	if v.kind == "" {
+		return fmt.Errorf("value has no type; use NumericValue, PercentValue or BoolValue")
+	}
+	if v.kind != t {
+		return fmt.Errorf("key result is %s, got a %s value", t, v.kind)
+	}
+	if v.kind == KeyResultPercent && (v.number < 0 || v.number > 100) {
+		return fmt.Errorf("percent value %v is not between 0 and 100", v.number)
+	}
+	return nil
+}
+
+// KeyResultValue converts v to the form the API sends and receives.
+func (v Value) KeyResultValue() KeyResultValue {
+	if v.kind == KeyResultBoolean {
+		b := v.boolean
+		return KeyResultValue{BooleanValue: &b}
+	}
+	return KeyResultValue{NumericValue: strconv.FormatFloat(v.number, 'f', -1, 64)}
+}
+
+// GetKeyResult ...
+func (c *Client) GetKeyResult(id string) (*KeyResult, error) {
+	resource := KeyResult{}
+	uri := path.Join("key-results", id)
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UpdateKeyResult ...
+func (c *Client) UpdateKeyResult(id string, params *UpdateKeyResultParams) (*KeyResult, error) {
+	resource := KeyResult{}
+	uri := path.Join("key-results", id)
+	err := c.RequestResource("PUT", &resource, uri, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UpdateKeyResultProgress sets the current observed value of a key
+// result. The key result is fetched first, and current must be of the
+// same type: NumericValue for numeric key results, PercentValue (0 to
+// 100) for percent ones and BoolValue for boolean ones. Nothing is
+// updated if it isn't.
+//
+// Key results are only in the v3 API, so this needs a client with
+// Version set to "v3".
+func (c *Client) UpdateKeyResultProgress(id string, current Value) (*KeyResult, error) {
+	kr, err := c.GetKeyResult(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := current.check(kr.Type); err != nil {
+		return nil, fmt.Errorf("UpdateKeyResultProgress: %s: %s", id, err)
+	}
+	observed := current.KeyResultValue()
+	return c.UpdateKeyResult(id, &UpdateKeyResultParams{ObservedValue: &observed})
+}
@@ -0,0 +1,66 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateKeyResultProgress(t *testing.T) {
+	types := map[string]string{"kr-num": "numeric", "kr-pct": "percent", "kr-bool": "boolean"}
+	puts := map[string]map[string]interface{}{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[len("/v3/key-results/"):]
+		if r.Method == "PUT" {
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			puts[id] = body
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "type": types[id]})
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Version: "v3", Limiter: RateLimiter(0)}
+
+	for id, value := range map[string]Value{
+		"kr-num":  NumericValue(12.5),
+		"kr-pct":  PercentValue(40),
+		"kr-bool": BoolValue(true),
+	} {
+		kr, err := c.UpdateKeyResultProgress(id, value)
+		if err != nil {
+			t.Fatalf("%s: %s", id, err)
+		}
+		if kr.Type != value.Type() {
+			t.Errorf("%s: unexpected type %s", id, kr.Type)
+		}
+	}
+	expect := map[string]string{
+		"kr-num":  `{"observed_value":{"numeric_value":"12.5"}}`,
+		"kr-pct":  `{"observed_value":{"numeric_value":"40"}}`,
+		"kr-bool": `{"observed_value":{"boolean_value":true}}`,
+	}
+	for id, want := range expect {
+		got, _ := json.Marshal(puts[id])
+		if string(got) != want {
+			t.Errorf("%s: expected %s, got %s", id, want, got)
+		}
+	}
+
+	puts = map[string]map[string]interface{}{}
+	for id, value := range map[string]Value{
+		"kr-num":  BoolValue(false),
+		"kr-pct":  PercentValue(140),
+		"kr-bool": NumericValue(1),
+	} {
+		if _, err := c.UpdateKeyResultProgress(id, value); err == nil {
+			t.Errorf("%s: expected %v to be rejected", id, value)
+		}
+	}
+	if _, err := c.UpdateKeyResultProgress("kr-num", Value{}); err == nil {
+		t.Error("expected zero Value to be rejected")
+	}
+	if len(puts) != 0 {
+		t.Errorf("expected no updates, got %v", puts)
+	}
+}
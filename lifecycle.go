@@ -0,0 +1,168 @@
+package clubhouse
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Service is the lifecycle shared by the package's background
+// subsystems: Watcher, StatsRecorder, FileMirror and webhook.Outbox.
+// Services embedded in a server can all be started and shut down the
+// same way.
+type Service interface {
+	// Start runs the service in the background until Stop is called or
+	// ctx is done. It returns an error if the service is already running.
+	Start(ctx context.Context) error
+
+	// Stop stops the service, waiting for the work in flight to finish
+	// and for anything queued to be drained. If ctx is done first, Stop
+	// gives up and returns ctx's error.
+	Stop(ctx context.Context) error
+
+	// Errors receives the errors from the service's background work.
+	// Errors don't stop the service; it carries on at its next interval.
+	// The channel is closed once the service stops. Errors are dropped
+	// if nobody is reading and the channel's buffer is full.
+	Errors() <-chan error
+}
+
+var (
+	_ Service = (*Loop)(nil)
+	_ Service = (*Watcher)(nil)
+	_ Service = (*StatsRecorder)(nil)
+	_ Service = (*FileMirror)(nil)
+)
+
+// ErrServiceRunning is returned by Start when the service is already
+// running.
+var ErrServiceRunning = errors.New("clubhouse: service already running")
+
+// serviceErrorBuffer is the number of errors a Loop holds for a slow
+// reader before dropping them.
+const serviceErrorBuffer = 16
+
+// Loop is a Service that calls Step right away and then every Interval.
+// It's how the package's background subsystems implement Service, and
+// can be used for new ones.
+type Loop struct {
+	// Step does one round of work. Its context is the one passed to
+	// Start; stopping the loop doesn't cancel it, so work in flight
+	// gets to finish.
+	Step func(ctx context.Context) error
+
+	// Interval is the time between Steps. It must be positive.
+	Interval time.Duration
+
+	// Clock, if set, is used to wait between Steps instead of RealClock.
+	Clock Clock
+
+	// Drain, if set, is called by Stop after the last Step has
+	// finished, to flush anything that's queued.
+	Drain func(ctx context.Context) error
+
+	mu     sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+	errs   chan error
+	closed bool // errs was closed when the last run stopped
+}
+
+// Start starts the loop. It stops when Stop is called or ctx is done.
+func (l *Loop) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.Step == nil {
+		return errors.New("clubhouse: Loop has no Step")
+	}
+	if l.Interval <= 0 {
+		return errors.New("clubhouse: Loop needs a positive Interval")
+	}
+	if l.done != nil {
+		select {
+		case <-l.done:
+		default:
+			return ErrServiceRunning
+		}
+	}
+	stepFn, interval, clock := l.Step, l.Interval, l.Clock
+	if clock == nil {
+		clock = RealClock
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	if l.errs == nil || l.closed {
+		l.errs, l.closed = make(chan error, serviceErrorBuffer), false
+	}
+	errs := l.errs
+	l.stop, l.done = stop, done
+
+	go func() {
+		defer close(done)
+		defer func() {
+			l.mu.Lock()
+			close(errs)
+			l.closed = true
+			l.mu.Unlock()
+		}()
+		step := func() {
+			if err := stepFn(ctx); err != nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+		}
+		step()
+		ticker := clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				step()
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the loop, waits for a Step in flight to finish, and then
+// calls Drain. Stopping a loop that isn't running only calls Drain.
+func (l *Loop) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	stop, done, drain := l.stop, l.done, l.Drain
+	l.stop = nil
+	l.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+	}
+	if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if drain != nil {
+		return drain(ctx)
+	}
+	return nil
+}
+
+// Errors returns the channel that receives the errors from Step. Call
+// it before or after Start; each run of the loop closes its channel
+// when it stops, and the next Start makes a new one. Until then, Errors
+// keeps returning the closed channel.
+func (l *Loop) Errors() <-chan error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.errs == nil {
+		l.errs = make(chan error, serviceErrorBuffer)
+	}
+	return l.errs
+}
@@ -0,0 +1,125 @@
+package clubhouse
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoop(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	steps := make(chan int, 10)
+	release := make(chan struct{})
+	n := 0
+	drained := false
+	loop := &Loop{
+		Interval: time.Minute,
+		Clock:    clock,
+		Step: func(ctx context.Context) error {
+			n++
+			steps <- n
+			if n == 2 {
+				return errors.New("boom")
+			}
+			if n == 3 {
+				<-release
+			}
+			return nil
+		},
+		Drain: func(ctx context.Context) error {
+			drained = true
+			return nil
+		},
+	}
+	errs := loop.Errors()
+	if err := loop.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := loop.Start(context.Background()); err != ErrServiceRunning {
+		t.Errorf("expected ErrServiceRunning, got %v", err)
+	}
+	<-steps
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	<-steps
+	if err := <-errs; err == nil || err.Error() != "boom" {
+		t.Errorf("expected the step's error, got %v", err)
+	}
+
+	// stop while a step is in flight: it has to finish first
+	clock.Advance(time.Minute)
+	<-steps
+	stopped := make(chan error)
+	go func() { stopped <- loop.Stop(context.Background()) }()
+	select {
+	case <-stopped:
+		t.Fatal("expected Stop to wait for the step in flight")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+	if err := <-stopped; err != nil {
+		t.Fatal(err)
+	}
+	if !drained {
+		t.Error("expected Stop to drain")
+	}
+	if _, open := <-errs; open {
+		t.Error("expected errors to be closed after Stop")
+	}
+
+	if err := loop.Start(context.Background()); err != nil {
+		t.Errorf("expected a stopped loop to start again, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := loop.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoopStopTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	loop := &Loop{Interval: time.Minute, Step: func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}}
+	loop.Start(context.Background())
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := loop.Stop(ctx); err != context.Canceled {
+		t.Errorf("expected Stop to give up, got %v", err)
+	}
+}
+
+func TestLoopStopped(t *testing.T) {
+	loop := &Loop{Step: func(context.Context) error { return nil }}
+	if err := loop.Start(context.Background()); err == nil {
+		t.Error("expected an error for a Loop without an Interval")
+	}
+
+	loop.Interval = time.Minute
+	loop.Clock = NewFakeClock(time.Now())
+	if err := loop.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := loop.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, open := <-loop.Errors(); open {
+		t.Error("expected Errors after Stop to be closed")
+	}
+	if err := loop.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer loop.Stop(context.Background())
+	select {
+	case <-loop.Errors():
+		t.Error("expected a restarted loop to have an open Errors channel")
+	default:
+	}
+}
@@ -0,0 +1,124 @@
+package clubhouse
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/ratelimit"
+)
+
+// SharedLimiter lets interactive work, like a person waiting on the
+// CLI, cut in front of background jobs that share the same rate limit
+// budget, instead of both competing blindly for it. Give background
+// clients the SharedLimiter itself and interactive clients its
+// Interactive view:
+//
+//	shared := NewSharedLimiter(DefaultLimiter)
+//	sync := &Client{AuthToken: token, Limiter: shared}
+//	cli := &Client{AuthToken: token, Limiter: shared.Interactive()}
+//
+// Background requests wait while the limiter is paused, while an
+// interactive request is waiting for its turn, and while any budget is
+// reserved. Interactive requests are never held back by any of these.
+type SharedLimiter struct {
+	limiter ratelimit.Limiter
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	paused   bool
+	waiting  int
+	reserved int
+}
+
+// NewSharedLimiter wraps limiter, which sets the overall rate.
+func NewSharedLimiter(limiter ratelimit.Limiter) *SharedLimiter {
+	l := &SharedLimiter{limiter: limiter}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// Take blocks until a background request can be made.
+func (l *SharedLimiter) Take() time.Time {
+	l.mu.Lock()
+	for l.paused || l.waiting > 0 || l.reserved > 0 {
+		l.cond.Wait()
+	}
+	l.mu.Unlock()
+	return l.limiter.Take()
+}
+
+// Pause holds back background requests until Resume is called.
+func (l *SharedLimiter) Pause() {
+	l.mu.Lock()
+	l.paused = true
+	l.mu.Unlock()
+}
+
+// Resume lets background requests continue after Pause.
+func (l *SharedLimiter) Resume() {
+	l.mu.Lock()
+	l.paused = false
+	l.cond.Broadcast()
+	l.mu.Unlock()
+}
+
+// Reserve holds the next n requests for interactive use, so a burst of
+// interactive requests isn't interleaved with background ones. Each
+// interactive request uses up one; release gives back whatever wasn't
+// used, and must be called once the interactive work is done.
+// Reservations are pooled, so interactive requests use up whichever
+// reservation is outstanding.
+func (l *SharedLimiter) Reserve(n int) (release func()) {
+	l.mu.Lock()
+	l.reserved += n
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			if n > l.reserved {
+				n = l.reserved
+			}
+			l.reserved -= n
+			l.cond.Broadcast()
+			l.mu.Unlock()
+		})
+	}
+}
+
+// Observe passes resp on to the wrapped limiter if it's a
+// ResponseObserver, e.g. an AdaptiveLimiter.
+func (l *SharedLimiter) Observe(resp *http.Response) {
+	if o, ok := l.limiter.(ResponseObserver); ok {
+		o.Observe(resp)
+	}
+}
+
+// Interactive returns a limiter for requests that take priority over
+// background ones.
+func (l *SharedLimiter) Interactive() ratelimit.Limiter {
+	return interactiveLimiter{l}
+}
+
+type interactiveLimiter struct {
+	*SharedLimiter
+}
+
+func (l interactiveLimiter) Take() time.Time {
+	l.mu.Lock()
+	l.waiting++
+	l.mu.Unlock()
+
+	t := l.limiter.Take()
+
+	l.mu.Lock()
+	l.waiting--
+	if l.reserved > 0 {
+		l.reserved--
+	}
+	l.cond.Broadcast()
+	l.mu.Unlock()
+	return t
+}
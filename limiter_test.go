@@ -0,0 +1,65 @@
+package clubhouse
+
+import (
+	"testing"
+	"time"
+)
+
+// taken starts a background Take, returning a channel that is closed
+// once it returns.
+func taken(l *SharedLimiter) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		l.Take()
+		close(done)
+	}()
+	return done
+}
+
+func TestSharedLimiter(t *testing.T) {
+	l := NewSharedLimiter(RateLimiter(0))
+	wait := 20 * time.Millisecond
+
+	l.Pause()
+	done := taken(l)
+	l.Interactive().Take()
+	select {
+	case <-done:
+		t.Fatal("expected background take to wait while paused")
+	case <-time.After(wait):
+	}
+	l.Resume()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected background take after resume")
+	}
+
+	release := l.Reserve(2)
+	done = taken(l)
+	l.Interactive().Take()
+	select {
+	case <-done:
+		t.Fatal("expected background take to wait while budget is reserved")
+	case <-time.After(wait):
+	}
+	release()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected background take after release")
+	}
+	release()
+	if l.reserved != 0 {
+		t.Errorf("expected no reservation left, got %d", l.reserved)
+	}
+
+	l.Reserve(1)
+	done = taken(l)
+	l.Interactive().Take()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected background take once reservation was used")
+	}
+}
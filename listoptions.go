@@ -0,0 +1,39 @@
+package clubhouse
+
+import "net/url"
+
+// ListOptions trims what list endpoints return, which in large
+// workspaces can be megabytes of descriptions nobody reads. List methods
+// that take options accept any number of them, e.g.
+//
+//	epics, err := c.ListEpics(ListOptions{OmitDescriptions: true})
+type ListOptions struct {
+	// OmitDescriptions leaves each item's description out of the
+	// response (includes_description=false).
+	OmitDescriptions bool
+
+	// Params are added to the query string as they are, for options
+	// this type doesn't have a field for.
+	Params url.Values
+}
+
+// listEndpoint adds the query params for opts to uri.
+func listEndpoint(uri string, opts []ListOptions) string {
+	query := url.Values{}
+	for _, o := range opts {
+		if o.OmitDescriptions {
+			query.Set("includes_description", "false")
+		}
+		for name, values := range o.Params {
+			query[name] = append(query[name], values...)
+		}
+	}
+	if len(query) == 0 {
+		return uri
+	}
+	return uri + "?" + query.Encode()
+}
+
+// briefOptions are the options for the Briefs methods, which never
+// decode descriptions, so never ask for them.
+var briefOptions = []ListOptions{{OmitDescriptions: true}}
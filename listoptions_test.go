@@ -0,0 +1,38 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestListOptions(t *testing.T) {
+	queries := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	c.ListEpics()
+	c.ListEpics(ListOptions{OmitDescriptions: true})
+	c.ListProjectStories(5, ListOptions{Params: url.Values{"archived": {"false"}}}, ListOptions{OmitDescriptions: true})
+	c.ListEpicStoryBriefs(6)
+
+	expect := []string{
+		"/v2/epics?",
+		"/v2/epics?includes_description=false",
+		"/v2/projects/5/stories?archived=false&includes_description=false",
+		"/v2/epics/6/stories?includes_description=false",
+	}
+	if len(queries) != len(expect) {
+		t.Fatalf("expected %v, got %v", expect, queries)
+	}
+	for i := range expect {
+		if queries[i] != expect[i] {
+			t.Errorf("expected %s, got %s", expect[i], queries[i])
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package clubhouse
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// LogLevel is how important a log entry is.
+type LogLevel int
+
+// Valid values for LogLevel
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// LogEntry is one message from the client. Method and Endpoint are set
+// for messages about a request.
+type LogEntry struct {
+	Level    LogLevel
+	Message  string
+	Method   string
+	Endpoint string
+}
+
+func (e LogEntry) String() string {
+	if e.Method == "" {
+		return fmt.Sprintf("%s: %s", e.Level, e.Message)
+	}
+	return fmt.Sprintf("%s: %s %s: %s", e.Level, e.Method, e.Endpoint, e.Message)
+}
+
+// Logger receives the client's log messages, e.g. to route them into an
+// application's own logging. The auth token is redacted from entries
+// before they're logged.
+type Logger interface {
+	Log(entry LogEntry)
+}
+
+// LoggerFunc is a func that's a Logger.
+type LoggerFunc func(entry LogEntry)
+
+// Log calls f(entry).
+func (f LoggerFunc) Log(entry LogEntry) {
+	f(entry)
+}
+
+// NewStdLogger returns a Logger that prints entries at level min and
+// above to l.
+func NewStdLogger(l *log.Logger, min LogLevel) Logger {
+	return LoggerFunc(func(entry LogEntry) {
+		if entry.Level >= min {
+			l.Println(entry)
+		}
+	})
+}
+
+// redactedToken replaces the auth token in logged messages.
+const redactedToken = "[REDACTED]"
+
+// logf logs a message about a request to c.Logger, if it's set. method
+// and endpoint may be empty for messages that aren't about one request.
+func (c *Client) logf(level LogLevel, method, endpoint, format string, v ...interface{}) {
+	if c.Logger == nil {
+		return
+	}
+	entry := LogEntry{
+		Level:    level,
+		Message:  fmt.Sprintf(format, v...),
+		Method:   method,
+		Endpoint: endpoint,
+	}
+	if c.AuthToken != "" {
+		entry.Message = strings.Replace(entry.Message, c.AuthToken, redactedToken, -1)
+		entry.Endpoint = strings.Replace(entry.Endpoint, c.AuthToken, redactedToken, -1)
+	}
+	c.Logger.Log(entry)
+}
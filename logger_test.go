@@ -0,0 +1,50 @@
+package clubhouse
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		w.Write([]byte(`{"message":"bad"}`))
+	}))
+	defer srv.Close()
+
+	entries := []LogEntry{}
+	c := &Client{
+		AuthToken: "s3cret",
+		RootURL:   srv.URL,
+		Limiter:   RateLimiter(0),
+		Logger:    LoggerFunc(func(e LogEntry) { entries = append(entries, e) }),
+	}
+	c.CreateLabel(&CreateLabelParams{Name: "s3cret label"})
+
+	if len(entries) != 3 {
+		t.Fatalf("expected the request body, status and response body, got %v", entries)
+	}
+	for _, e := range entries {
+		if e.Level != LogDebug || e.Method != "POST" || e.Endpoint != "labels" {
+			t.Errorf("unexpected entry %+v", e)
+		}
+		if strings.Contains(e.String(), "s3cret") {
+			t.Errorf("expected the token to be redacted, got %q", e)
+		}
+	}
+	if !strings.Contains(entries[0].Message, "[REDACTED] label") {
+		t.Errorf("expected the request body, got %q", entries[0].Message)
+	}
+
+	buf := &bytes.Buffer{}
+	l := NewStdLogger(log.New(buf, "", 0), LogWarn)
+	l.Log(LogEntry{Level: LogDebug, Message: "quiet"})
+	l.Log(LogEntry{Level: LogWarn, Message: "loud", Method: "GET", Endpoint: "epics"})
+	if buf.String() != "warn: GET epics: loud\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,109 @@
+package clubhouse
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Length limits the API enforces on Markdown fields, in characters.
+// Longer text is rejected with an ErrUnprocessable (see ErrValidation).
+const (
+	MaxDescriptionLength = 100000
+	MaxCommentLength     = 100000
+)
+
+// TruncatedMarker is appended to text cut short by SanitizeMarkdown.
+var TruncatedMarker = "\n\n_(truncated)_"
+
+// AllowedHTML is the set of HTML elements SanitizeMarkdown leaves in
+// place. Every other tag is removed, keeping the text between tags.
+var AllowedHTML = map[string]bool{
+	"b": true, "br": true, "code": true, "del": true, "details": true,
+	"em": true, "i": true, "kbd": true, "pre": true, "strong": true,
+	"sub": true, "summary": true, "sup": true,
+}
+
+var (
+	// elements whose content should go too, not just the tags
+	dropElementPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?is)<script\b.*?</script\s*>`),
+		regexp.MustCompile(`(?is)<style\b.*?</style\s*>`),
+		regexp.MustCompile(`(?is)<iframe\b.*?</iframe\s*>`),
+	}
+	htmlTagPattern = regexp.MustCompile(`</?([a-zA-Z][a-zA-Z0-9]*)(?:\s[^>]*)?/?>`)
+)
+
+// SanitizeMarkdown prepares Markdown written by a program for a story
+// description or comment: line endings are normalized to "\n",
+// disallowed HTML is stripped outside of code blocks, and the result is
+// truncated to limit characters (with TruncatedMarker) so it can't be
+// rejected for being too long. truncated reports whether text was cut.
+// A limit of zero or less means no limit.
+func SanitizeMarkdown(text string, limit int) (sanitized string, truncated bool) {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	text = strings.Replace(text, "\r", "\n", -1)
+	text = stripHTML(text)
+	return truncateMarkdown(text, limit)
+}
+
+// stripHTML removes disallowed HTML from everything outside of fenced
+// code blocks.
+func stripHTML(text string) string {
+	lines := strings.SplitAfter(text, "\n")
+	out := strings.Builder{}
+	prose := strings.Builder{}
+	flush := func() {
+		s := prose.String()
+		for _, p := range dropElementPatterns {
+			s = p.ReplaceAllString(s, "")
+		}
+		s = htmlTagPattern.ReplaceAllStringFunc(s, func(tag string) string {
+			name := htmlTagPattern.FindStringSubmatch(tag)[1]
+			if AllowedHTML[strings.ToLower(name)] {
+				return tag
+			}
+			return ""
+		})
+		out.WriteString(s)
+		prose.Reset()
+	}
+
+	fence := ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			flush()
+			fence = trimmed[:3]
+			out.WriteString(line)
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			out.WriteString(line)
+		default:
+			prose.WriteString(line)
+		}
+	}
+	flush()
+	return out.String()
+}
+
+func truncateMarkdown(text string, limit int) (string, bool) {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return text, false
+	}
+	keep := limit - utf8.RuneCountInString(TruncatedMarker)
+	if keep < 0 {
+		keep = 0
+	}
+	runes := 0
+	for i := range text {
+		if runes == keep {
+			return text[:i] + TruncatedMarker, true
+		}
+		runes++
+	}
+	return text + TruncatedMarker, true
+}
@@ -0,0 +1,46 @@
+package clubhouse
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeMarkdown(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{"one\r\ntwo\rthree", "one\ntwo\nthree"},
+		{"hi <script>alert(1)</script>there", "hi there"},
+		{`<div class="x">keep <b>bold</b></div>`, "keep <b>bold</b>"},
+		{"<img src=x onerror=alert(1)>text", "text"},
+		{"```html\n<div>code</div>\n```\n<div>prose</div>", "```html\n<div>code</div>\n```\nprose"},
+		{"a < b and c > d", "a < b and c > d"},
+		{"see <https://example.com/a?b=c> or <br/>", "see <https://example.com/a?b=c> or <br/>"},
+	}
+	for _, test := range tests {
+		got, truncated := SanitizeMarkdown(test.in, 0)
+		if got != test.expect || truncated {
+			t.Errorf("%q: expected %q, got %q (truncated %v)", test.in, test.expect, got, truncated)
+		}
+	}
+}
+
+func TestSanitizeMarkdownTruncates(t *testing.T) {
+	text := strings.Repeat("é", 100)
+	got, truncated := SanitizeMarkdown(text, 50)
+	if !truncated {
+		t.Error("expected text to be truncated")
+	}
+	if n := utf8.RuneCountInString(got); n != 50 {
+		t.Errorf("expected 50 characters, got %d", n)
+	}
+	if !strings.HasSuffix(got, TruncatedMarker) {
+		t.Errorf("expected truncated marker, got %q", got)
+	}
+
+	if got, truncated := SanitizeMarkdown("short", 50); got != "short" || truncated {
+		t.Errorf("expected short text untouched, got %q", got)
+	}
+}
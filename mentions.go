@@ -0,0 +1,198 @@
+package clubhouse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MentionScope selects what AuditMentions scans.
+type MentionScope struct {
+	// Stories selects stories by search; nil means no stories. The
+	// description and comments of each story are scanned.
+	Stories *SearchQuery
+	// Epics scans the description and comments of every epic.
+	Epics bool
+}
+
+// MentionAuditOptions controls what AuditMentions does with the
+// mentions it finds.
+type MentionAuditOptions struct {
+	// Successors maps the IDs of disabled members to the IDs of the
+	// members who should be mentioned instead. Mentions of disabled
+	// members without a successor are only reported.
+	Successors map[string]string
+
+	// DryRun reports what would be rewritten without updating anything.
+	DryRun bool
+}
+
+// StaleMention is a mention of a disabled member found by
+// AuditMentions.
+type StaleMention struct {
+	// EntityType is "story" or "epic", and ID is the story or epic the
+	// mention is in. CommentID is set if it's in a comment rather than
+	// the description.
+	EntityType string
+	ID         int
+	CommentID  int
+
+	MemberID    string
+	MentionName string
+
+	// ReplacedWith is the mention name of the successor, if the mention
+	// was rewritten (or would be, in dry-run mode).
+	ReplacedWith string
+}
+
+var mentionPattern = regexp.MustCompile(`@[\w.\-]+`)
+
+// mentionRewriter finds and replaces mentions of disabled members.
+type mentionRewriter struct {
+	disabled   map[string]Member
+	successors map[string]string
+}
+
+// scan returns the stale mentions in text, and text with the ones that
+// have a successor replaced.
+func (r mentionRewriter) scan(text string) ([]StaleMention, string) {
+	found := []StaleMention{}
+	rewritten := mentionPattern.ReplaceAllStringFunc(text, func(m string) string {
+		// punctuation ending a sentence isn't part of the name
+		name := strings.TrimRight(m[1:], ".-")
+		member, ok := r.disabled[name]
+		if !ok {
+			return m
+		}
+		stale := StaleMention{MemberID: member.ID, MentionName: name}
+		successor, ok := r.successors[member.ID]
+		if ok {
+			stale.ReplacedWith = successor
+		}
+		found = append(found, stale)
+		if !ok {
+			return m
+		}
+		return "@" + successor + m[1+len(name):]
+	})
+	return found, rewritten
+}
+
+// AuditMentions scans stories and epics for @-mentions of disabled
+// members, which is part of cleaning up after someone leaves. Mentions
+// of members with a successor in opts are rewritten to mention the
+// successor instead.
+//
+// The mentions are returned in the order they were found, as far as
+// the scan got if an error is returned.
+func (c *Client) AuditMentions(scope MentionScope, opts MentionAuditOptions) ([]StaleMention, error) {
+	members, err := c.ListMembers()
+	if err != nil {
+		return nil, err
+	}
+	r := mentionRewriter{disabled: map[string]Member{}, successors: map[string]string{}}
+	mentionNames := map[string]string{}
+	for _, m := range members {
+		mentionNames[m.ID] = m.Profile.MentionName
+		if m.Disabled || m.Profile.Deactivated {
+			r.disabled[m.Profile.MentionName] = m
+		}
+	}
+	for from, to := range opts.Successors {
+		name, ok := mentionNames[to]
+		if !ok {
+			return nil, fmt.Errorf("AuditMentions: no member %s to succeed %s", to, from)
+		}
+		r.successors[from] = name
+	}
+
+	found := []StaleMention{}
+	// check scans text and, if it needs rewriting, calls update with the
+	// new text
+	check := func(entityType string, id, commentID int, text string, update func(string) error) error {
+		stale, rewritten := r.scan(text)
+		for _, s := range stale {
+			s.EntityType, s.ID, s.CommentID = entityType, id, commentID
+			found = append(found, s)
+		}
+		if rewritten == text || opts.DryRun {
+			return nil
+		}
+		return update(rewritten)
+	}
+
+	if scope.Stories != nil {
+		ids, err := c.searchStoryIDs(*scope.Stories)
+		if err != nil {
+			return nil, err
+		}
+		stories, err := c.HydrateStories(ids, MissingSkip)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range stories {
+			id := s.ID
+			err := check("story", id, 0, s.Description, func(text string) error {
+				_, err := c.UpdateStory(id, &UpdateStoryParams{Description: &text})
+				return err
+			})
+			if err != nil {
+				return found, err
+			}
+			for _, comment := range s.Comments {
+				commentID := comment.ID
+				err := check("story", id, commentID, comment.Text, func(text string) error {
+					_, err := c.UpdateStoryComment(id, commentID, &UpdateCommentParams{Text: text})
+					return err
+				})
+				if err != nil {
+					return found, err
+				}
+			}
+		}
+	}
+
+	if scope.Epics {
+		epics, err := c.ListEpics()
+		if err != nil {
+			return found, err
+		}
+		for _, e := range epics {
+			id := e.ID
+			err := check("epic", id, 0, e.Description, func(text string) error {
+				_, err := c.UpdateEpic(id, UpdateEpicParams{Description: &text})
+				return err
+			})
+			if err != nil {
+				return found, err
+			}
+			err = eachThreadedComment(e.Comments, func(comment ThreadedComment) error {
+				commentID := comment.ID
+				return check("epic", id, commentID, comment.Text, func(text string) error {
+					_, err := c.UpdateEpicComment(id, commentID, &UpdateCommentParams{Text: text})
+					return err
+				})
+			})
+			if err != nil {
+				return found, err
+			}
+		}
+	}
+	return found, nil
+}
+
+// eachThreadedComment calls fn for every comment and reply, depth first.
+func eachThreadedComment(comments []ThreadedComment, fn func(ThreadedComment) error) error {
+	for _, comment := range comments {
+		if comment.Deleted {
+			continue
+		}
+		if err := fn(comment); err != nil {
+			return err
+		}
+		if err := eachThreadedComment(comment.Comments, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAuditMentions(t *testing.T) {
+	updates := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			var body struct{ Description, Text string }
+			json.NewDecoder(r.Body).Decode(&body)
+			updates[r.URL.Path] = body.Description + body.Text
+			w.Write([]byte(`{}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/members":
+			w.Write([]byte(`[
+				{"id":"u1","disabled":true,"profile":{"mention_name":"ada"}},
+				{"id":"u2","profile":{"deactivated":true,"mention_name":"bob.b"}},
+				{"id":"u3","profile":{"mention_name":"cy"}}]`))
+		case "/v2/search/stories":
+			w.Write([]byte(`{"data":[{"id":1}]}`))
+		case "/v2/stories/1":
+			w.Write([]byte(`{"id":1,"description":"ask @ada.","comments":[{"id":5,"text":"@cy @bob.b"}]}`))
+		case "/v2/epics":
+			w.Write([]byte(`[{"id":2,"description":"@adam","comments":[
+				{"id":6,"text":"ok","comments":[{"id":7,"text":"cc @ada"}]}]}]`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	scope := MentionScope{Stories: &SearchQuery{Project: "web"}, Epics: true}
+	opts := MentionAuditOptions{Successors: map[string]string{"u1": "u3"}}
+	found, err := c.AuditMentions(scope, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []StaleMention{
+		{EntityType: "story", ID: 1, MemberID: "u1", MentionName: "ada", ReplacedWith: "cy"},
+		{EntityType: "story", ID: 1, CommentID: 5, MemberID: "u2", MentionName: "bob.b"},
+		{EntityType: "epic", ID: 2, CommentID: 7, MemberID: "u1", MentionName: "ada", ReplacedWith: "cy"},
+	}
+	if !reflect.DeepEqual(found, want) {
+		t.Errorf("expected %+v, got %+v", want, found)
+	}
+	wantUpdates := map[string]string{
+		"/v2/stories/1":          "ask @cy.",
+		"/v2/epics/2/comments/7": "cc @cy",
+	}
+	if !reflect.DeepEqual(updates, wantUpdates) {
+		t.Errorf("expected updates %v, got %v", wantUpdates, updates)
+	}
+
+	updates = map[string]string{}
+	opts.DryRun = true
+	if _, err := c.AuditMentions(scope, opts); err != nil || len(updates) != 0 {
+		t.Errorf("expected dry run not to update, got %v, %v", updates, err)
+	}
+}
@@ -0,0 +1,31 @@
+package clubhouse
+
+import "net/http"
+
+// RoundTripFunc sends a request to the API and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of API requests, e.g. to add headers,
+// tracing or metrics. It gets the next RoundTripFunc in the chain and
+// returns one that calls it, or answers the request itself.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use adds middleware around every request the client sends to the API.
+// The first middleware added is the outermost. Middleware runs after
+// rate limiting, once for each attempt when requests are retried, and
+// not at all for responses served from the Cache.
+//
+// Use isn't safe to call while the client is making requests; add
+// middleware when setting the client up.
+func (c *Client) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// roundTrip sends req with c.HTTPClient through the middleware chain.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	send := RoundTripFunc(c.HTTPClient.Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		send = c.middleware[i](send)
+	}
+	return send(req)
+}
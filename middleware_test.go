@@ -0,0 +1,62 @@
+package clubhouse
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") != "abc" {
+			t.Errorf("expected the trace header, got %q", r.Header.Get("X-Trace"))
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	calls := []string{}
+	trace := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			calls = append(calls, "trace")
+			req.Header.Set("X-Trace", "abc")
+			return next(req)
+		}
+	}
+	statuses := []int{}
+	metrics := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			calls = append(calls, "metrics")
+			resp, err := next(req)
+			if err == nil {
+				statuses = append(statuses, resp.StatusCode)
+			}
+			return resp, err
+		}
+	}
+	c.Use(trace, metrics)
+
+	if _, err := c.ListEpics(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(calls, []string{"trace", "metrics"}) {
+		t.Errorf("expected middleware in the order it was added, got %v", calls)
+	}
+	if !reflect.DeepEqual(statuses, []int{200}) {
+		t.Errorf("expected the response to pass back through, got %v", statuses)
+	}
+
+	refused := errors.New("refused")
+	c.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, refused
+		}
+	})
+	_, err := c.ListEpics()
+	if e, ok := err.(ErrClientRequest); !ok || e.Err != refused || e.Stage != ErrStageSendRequest {
+		t.Errorf("expected the middleware's error, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package clubhouse
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+// BlobStore is somewhere MirrorFiles can copy files to, e.g. an S3 or
+// GCS bucket.
+type BlobStore interface {
+	// Put writes everything read from r to key.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Exists reports whether key has already been written.
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// MirrorResult reports what MirrorFiles did.
+type MirrorResult struct {
+	Mirrored int
+	Skipped  int
+}
+
+// MirrorKey is the key MirrorFiles stores a file under: its ID and then
+// its name, e.g. "files/123/screenshot.png". File contents never change
+// once uploaded, so a key that exists is never written again.
+func MirrorKey(f File) string {
+	return path.Join("files", itoa(f.ID), path.Base("/"+f.Name))
+}
+
+// MirrorFiles downloads every file in the workspace and writes it to
+// dst under MirrorKey. Files that dst already has are skipped, so
+// running it again only copies files uploaded since the last run.
+//
+// Mirroring stops at the first error; the result is filled in as far
+// as it got, and running again carries on from there.
+func (c *Client) MirrorFiles(ctx context.Context, dst BlobStore) (MirrorResult, error) {
+	result := MirrorResult{}
+	err := c.EachFile(FileFilter{}, func(f File) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		key := MirrorKey(f)
+		exists, err := dst.Exists(ctx, key)
+		if err != nil {
+			return fmt.Errorf("MirrorFiles: error checking %s: %s", key, err)
+		}
+		if exists {
+			result.Skipped++
+			return nil
+		}
+		if err := c.mirrorFile(ctx, dst, key, f); err != nil {
+			return err
+		}
+		result.Mirrored++
+		return nil
+	})
+	return result, err
+}
+
+// FileMirror runs MirrorFiles every Interval as a Service, so new
+// uploads keep being copied to Dst.
+type FileMirror struct {
+	Client   *Client
+	Dst      BlobStore
+	Interval time.Duration
+
+	// Results, if set, is called with the result of every run.
+	Results func(MirrorResult)
+
+	loop Loop
+}
+
+// Start starts mirroring in the background. A run that fails is sent to
+// Errors, and the next one picks up where it left off.
+func (m *FileMirror) Start(ctx context.Context) error {
+	m.loop.Step = func(ctx context.Context) error {
+		result, err := m.Client.MirrorFiles(ctx, m.Dst)
+		if m.Results != nil {
+			m.Results(result)
+		}
+		return err
+	}
+	m.loop.Interval = m.Interval
+	m.loop.Clock = m.Client.clock()
+	return m.loop.Start(ctx)
+}
+
+// Stop stops mirroring, waiting for a run in flight to finish copying.
+func (m *FileMirror) Stop(ctx context.Context) error {
+	return m.loop.Stop(ctx)
+}
+
+// Errors receives the errors from runs after Start.
+func (m *FileMirror) Errors() <-chan error {
+	return m.loop.Errors()
+}
+
+func (c *Client) mirrorFile(ctx context.Context, dst BlobStore, key string, f File) error {
+	body, err := c.downloadFile(ctx, f)
+	if err != nil {
+		return fmt.Errorf("MirrorFiles: error downloading %s: %s", f.Name, err)
+	}
+	defer body.Close()
+	if err := dst.Put(ctx, key, body); err != nil {
+		return fmt.Errorf("MirrorFiles: error writing %s: %s", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package clubhouse
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type memoryBlobStore map[string]string
+
+func (m memoryBlobStore) Put(_ context.Context, key string, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	m[key] = string(b)
+	return err
+}
+
+func (m memoryBlobStore) Exists(_ context.Context, key string) (bool, error) {
+	_, ok := m[key]
+	return ok, nil
+}
+
+func TestMirrorFiles(t *testing.T) {
+	downloads := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/files":
+			fmt.Fprintf(w, `[
+				{"id":1,"name":"a.png","url":"%[1]s/download/1"},
+				{"id":2,"name":"../b.txt","url":"%[1]s/download/2"}]`, srv.URL)
+		case "/download/1", "/download/2":
+			if r.Header.Get(DefaultTokenHeader) != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			downloads++
+			w.Write([]byte("contents of " + r.URL.Path))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	dst := memoryBlobStore{"files/1/a.png": "old"}
+	result, err := c.MirrorFiles(context.Background(), dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (MirrorResult{Mirrored: 1, Skipped: 1}) || downloads != 1 {
+		t.Errorf("expected 1 mirrored and 1 skipped, got %+v after %d downloads", result, downloads)
+	}
+	if dst["files/2/b.txt"] != "contents of /download/2" || dst["files/1/a.png"] != "old" {
+		t.Errorf("unexpected store contents %v", dst)
+	}
+}
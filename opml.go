@@ -0,0 +1,110 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+)
+
+// OutlineNode is an objective, epic or story in a portfolio outline.
+type OutlineNode struct {
+	Type      string        `json:"type"`
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	Completed bool          `json:"completed,omitempty"`
+	Children  []OutlineNode `json:"children,omitempty"`
+}
+
+// Outline turns the tree into the Objective→Epic→Story hierarchy that
+// WriteOPML and WriteOutlineJSON write. Epics that aren't in an
+// objective come after the objectives, at the top level.
+func (t *PortfolioTree) Outline() []OutlineNode {
+	nodes := []OutlineNode{}
+	for _, o := range t.Objectives {
+		node := OutlineNode{
+			Type:      "objective",
+			ID:        o.Milestone.ID,
+			Name:      o.Milestone.Name,
+			Completed: o.Milestone.Completed,
+		}
+		for _, e := range o.Epics {
+			node.Children = append(node.Children, epicOutline(e))
+		}
+		nodes = append(nodes, node)
+	}
+	for _, e := range t.Epics {
+		nodes = append(nodes, epicOutline(e))
+	}
+	return nodes
+}
+
+func epicOutline(e EpicNode) OutlineNode {
+	node := OutlineNode{
+		Type:      "epic",
+		ID:        e.Epic.ID,
+		Name:      e.Epic.Name,
+		Completed: e.Epic.Completed,
+	}
+	for _, s := range e.Stories {
+		node.Children = append(node.Children, OutlineNode{
+			Type:      "story",
+			ID:        s.ID,
+			Name:      s.Name,
+			Completed: s.Completed,
+		})
+	}
+	return node
+}
+
+// WriteOutlineJSON writes the tree's Outline as an indented JSON array.
+func (t *PortfolioTree) WriteOutlineJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t.Outline())
+}
+
+type opmlDocument struct {
+	XMLName xml.Name      `xml:"opml"`
+	Version string        `xml:"version,attr"`
+	Title   string        `xml:"head>title"`
+	Body    []opmlOutline `xml:"body>outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Type     string        `xml:"clubhouseType,attr"`
+	ID       int           `xml:"clubhouseId,attr"`
+	Status   string        `xml:"_status,attr,omitempty"`
+	Children []opmlOutline `xml:"outline"`
+}
+
+// WriteOPML writes the tree's Outline as an OPML 2.0 document called
+// title, for outliners and mind-map apps. Each outline's text is the
+// entity's name, with its type and ID in the clubhouseType and
+// clubhouseId attributes. Completed entities are marked with
+// _status="checked", which outliners show as ticked off.
+func (t *PortfolioTree) WriteOPML(w io.Writer, title string) error {
+	doc := opmlDocument{Version: "2.0", Title: title, Body: opmlOutlines(t.Outline())}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func opmlOutlines(nodes []OutlineNode) []opmlOutline {
+	outlines := []opmlOutline{}
+	for _, n := range nodes {
+		o := opmlOutline{Text: n.Name, Type: n.Type, ID: n.ID, Children: opmlOutlines(n.Children)}
+		if n.Completed {
+			o.Status = "checked"
+		}
+		outlines = append(outlines, o)
+	}
+	return outlines
+}
@@ -0,0 +1,61 @@
+package clubhouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+var outlineTree = &PortfolioTree{
+	Objectives: []ObjectiveNode{{
+		Milestone: Milestone{ID: 1, Name: "Grow & retain"},
+		Epics: []EpicNode{{
+			Epic:    Epic{ID: 10, Name: "Onboarding"},
+			Stories: []StoryBrief{{ID: 100, Name: "Welcome email", Completed: true}},
+		}},
+	}},
+	Epics: []EpicNode{{Epic: Epic{ID: 12, Name: "Loose"}}},
+}
+
+func TestWriteOPML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := outlineTree.WriteOPML(&buf, "Roadmap"); err != nil {
+		t.Fatal(err)
+	}
+	expect := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head>
+    <title>Roadmap</title>
+  </head>
+  <body>
+    <outline text="Grow &amp; retain" clubhouseType="objective" clubhouseId="1">
+      <outline text="Onboarding" clubhouseType="epic" clubhouseId="10">
+        <outline text="Welcome email" clubhouseType="story" clubhouseId="100" _status="checked"></outline>
+      </outline>
+    </outline>
+    <outline text="Loose" clubhouseType="epic" clubhouseId="12"></outline>
+  </body>
+</opml>
+`
+	if buf.String() != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+}
+
+func TestWriteOutlineJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := outlineTree.WriteOutlineJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	nodes := []OutlineNode{}
+	if err := json.Unmarshal(buf.Bytes(), &nodes); err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 || nodes[1].Type != "epic" || nodes[1].Children != nil {
+		t.Fatalf("unexpected outline %s", buf.String())
+	}
+	story := nodes[0].Children[0].Children[0]
+	if story.Type != "story" || story.ID != 100 || !story.Completed {
+		t.Errorf("unexpected story %+v", story)
+	}
+}
@@ -0,0 +1,100 @@
+package clubhouse
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// Ordering is the manual order of some stories or epics, as IDs from
+// first to last. Positions aren't kept when stories are recreated, e.g.
+// by an import or TransferStory, so save an Ordering first and apply it
+// to the new IDs afterwards.
+type Ordering []int
+
+// Remap translates the IDs in an ordering, e.g. from the IDs in the old
+// workspace to the new. IDs that aren't in mapping are dropped.
+func (o Ordering) Remap(mapping map[int]int) Ordering {
+	out := Ordering{}
+	for _, id := range o {
+		if mapped, ok := mapping[id]; ok {
+			out = append(out, mapped)
+		}
+	}
+	return out
+}
+
+// ProjectStoryOrdering returns the order of the stories in a project.
+func (c *Client) ProjectStoryOrdering(projectID int) (Ordering, error) {
+	stories, err := c.ListProjectStoryBriefs(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return storyOrdering(stories), nil
+}
+
+// EpicStoryOrdering returns the order of the stories in an epic.
+func (c *Client) EpicStoryOrdering(epicID int) (Ordering, error) {
+	stories, err := c.ListEpicStoryBriefs(epicID)
+	if err != nil {
+		return nil, err
+	}
+	return storyOrdering(stories), nil
+}
+
+func storyOrdering(stories []StoryBrief) Ordering {
+	SortStories(stories, ByPosition)
+	o := make(Ordering, len(stories))
+	for i, s := range stories {
+		o[i] = s.ID
+	}
+	return o
+}
+
+// EpicOrdering returns the order of every epic in the workspace.
+func (c *Client) EpicOrdering() (Ordering, error) {
+	epics, err := c.ListEpicsWithoutComments()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(epics, func(i, j int) bool { return epics[i].Position < epics[j].Position })
+	o := make(Ordering, len(epics))
+	for i, e := range epics {
+		o[i] = e.ID
+	}
+	return o, nil
+}
+
+// ApplyStoryOrdering moves stories so they're in the order given, by
+// putting each one after the one before it. The first story stays where
+// it is. Each move depends on the last, so this makes one request at a
+// time.
+func (c *Client) ApplyStoryOrdering(o Ordering) error {
+	return c.applyOrdering("ApplyStoryOrdering", "stories", o, func(id, after int) error {
+		_, err := c.UpdateStory(id, &UpdateStoryParams{AfterID: &after})
+		return err
+	})
+}
+
+// ApplyEpicOrdering moves epics so they're in the order given, in the
+// same way as ApplyStoryOrdering.
+func (c *Client) ApplyEpicOrdering(o Ordering) error {
+	return c.applyOrdering("ApplyEpicOrdering", "epics", o, func(id, after int) error {
+		_, err := c.UpdateEpic(id, UpdateEpicParams{AfterID: &after})
+		return err
+	})
+}
+
+func (c *Client) applyOrdering(operation, endpoint string, o Ordering, move func(id, after int) error) error {
+	if len(o) < 2 {
+		return nil
+	}
+	progress := c.trackProgress(operation, len(o)-1)
+	for i := 1; i < len(o); i++ {
+		if err := move(o[i], o[i-1]); err != nil {
+			return fmt.Errorf("%s: error moving %d: %s", operation, o[i], err)
+		}
+		progress.step(1, path.Join(endpoint, itoa(o[i])))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestStoryOrdering(t *testing.T) {
+	moves := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/projects/1/stories":
+			w.Write([]byte(`[{"id":1,"position":300},{"id":2,"position":100},{"id":3,"position":200}]`))
+		case r.Method == "PUT":
+			var body struct {
+				AfterID int `json:"after_id"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			moves = append(moves, r.URL.Path+" after "+itoa(body.AfterID))
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	o, err := c.ProjectStoryOrdering(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(o, Ordering{2, 3, 1}) {
+		t.Errorf("expected 2, 3, 1, got %v", o)
+	}
+
+	o = o.Remap(map[int]int{1: 11, 2: 12, 3: 13})
+	if err := c.ApplyStoryOrdering(o); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/v2/stories/13 after 12", "/v2/stories/11 after 13"}
+	if !reflect.DeepEqual(moves, want) {
+		t.Errorf("expected %v, got %v", want, moves)
+	}
+}
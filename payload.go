@@ -0,0 +1,90 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+)
+
+// Limits checked before sending bulk requests, so oversized requests
+// fail early with ErrPayloadTooLarge instead of an opaque 400 or 413
+// from the server. MaxBulkItems is the documented limit on the stories
+// bulk endpoints. The API doesn't document a body size limit, so
+// MaxRequestBytes is a conservative guess that can be raised.
+var (
+	MaxBulkItems    = 100
+	MaxRequestBytes = 1 << 20
+)
+
+// ErrPayloadTooLarge is returned when a request would exceed
+// MaxBulkItems or MaxRequestBytes.
+type ErrPayloadTooLarge struct {
+	Endpoint string
+	Items    int
+	Bytes    int
+}
+
+func (e ErrPayloadTooLarge) Error() string {
+	return fmt.Sprintf(
+		"clubhouse: %s request too large (%d items, %d bytes; limits are %d items, %d bytes); "+
+			"split it into smaller requests or use the Chunked variant",
+		e.Endpoint, e.Items, e.Bytes, MaxBulkItems, MaxRequestBytes)
+}
+
+// checkPayload returns ErrPayloadTooLarge if a bulk request with items
+// entries and the given params is over the limits.
+func checkPayload(endpoint string, items int, params interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("could not marshal params, %s", err)
+	}
+	if items > MaxBulkItems || len(body) > MaxRequestBytes {
+		return ErrPayloadTooLarge{Endpoint: endpoint, Items: items, Bytes: len(body)}
+	}
+	return nil
+}
+
+// CreateStoriesChunked creates any number of stories, splitting them
+// into as many CreateStories requests as needed to stay under
+// MaxBulkItems and MaxRequestBytes. Requests are made in order; if one
+// fails, the stories created so far are returned with the error.
+func (c *Client) CreateStoriesChunked(plist []CreateStoryParams) ([]StorySlim, error) {
+	endpoint := path.Join("stories", "bulk")
+
+	// {"stories":[...]} plus commas
+	overhead := len(`{"stories":[]}`)
+	chunks := [][]CreateStoryParams{}
+	start, size := 0, overhead
+	for i, p := range plist {
+		item, err := json.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal params, %s", err)
+		}
+		if overhead+len(item) > MaxRequestBytes {
+			return nil, ErrPayloadTooLarge{Endpoint: endpoint, Items: 1, Bytes: overhead + len(item)}
+		}
+		if i > start && (i-start >= MaxBulkItems || size+1+len(item) > MaxRequestBytes) {
+			chunks = append(chunks, plist[start:i])
+			start, size = i, overhead
+		}
+		if i > start {
+			size++
+		}
+		size += len(item)
+	}
+	if start < len(plist) {
+		chunks = append(chunks, plist[start:])
+	}
+
+	created := []StorySlim{}
+	progress := c.trackProgress("CreateStoriesChunked", len(plist))
+	for _, chunk := range chunks {
+		stories, err := c.CreateStories(chunk)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, stories...)
+		progress.step(len(chunk), endpoint)
+	}
+	return created, nil
+}
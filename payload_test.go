@@ -0,0 +1,65 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckPayload(t *testing.T) {
+	if err := checkPayload("stories/bulk", 100, deleteStoriesParam{}); err != nil {
+		t.Errorf("expected no error at the limit, got %s", err)
+	}
+	err := checkPayload("stories/bulk", 101, deleteStoriesParam{})
+	if _, ok := err.(ErrPayloadTooLarge); !ok {
+		t.Errorf("expected ErrPayloadTooLarge, got %v", err)
+	}
+}
+
+func TestCreateStoriesChunked(t *testing.T) {
+	defer func(items, bytes int) { MaxBulkItems, MaxRequestBytes = items, bytes }(MaxBulkItems, MaxRequestBytes)
+	MaxBulkItems, MaxRequestBytes = 3, 200
+
+	sizes := []int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if len(body) > MaxRequestBytes {
+			t.Errorf("request of %d bytes is over the limit", len(body))
+		}
+		params := createStoriesParam{}
+		json.Unmarshal(body, &params)
+		sizes = append(sizes, len(params.Stories))
+		out := make([]StorySlim, len(params.Stories))
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	plist := []CreateStoryParams{}
+	for i := 0; i < 7; i++ {
+		plist = append(plist, CreateStoryParams{Name: "story", ProjectID: 1})
+	}
+	plist[4].Description = strings.Repeat("x", 100)
+
+	if _, err := c.CreateStories(plist); err == nil {
+		t.Error("expected CreateStories to reject 7 stories")
+	}
+	created, err := c.CreateStoriesChunked(plist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 7 {
+		t.Errorf("expected 7 stories, got %d", len(created))
+	}
+	if len(sizes) < 3 {
+		t.Errorf("expected at least 3 requests, got %v", sizes)
+	}
+
+	plist[0].Description = strings.Repeat("x", 300)
+	if _, err := c.CreateStoriesChunked(plist); err == nil {
+		t.Error("expected a single oversized story to be rejected")
+	}
+}
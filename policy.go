@@ -0,0 +1,144 @@
+package clubhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PolicyAction is the kind of thing a request does, for Policy rules.
+type PolicyAction string
+
+// Valid values for PolicyAction
+const (
+	ActionRead   PolicyAction = "read"
+	ActionCreate PolicyAction = "create"
+	ActionUpdate PolicyAction = "update"
+	ActionDelete PolicyAction = "delete"
+)
+
+// PolicyRule allows or denies an action on some resources.
+type PolicyRule struct {
+	Allow  bool
+	Action PolicyAction
+
+	// Resources are resource names as they appear in endpoints, e.g.
+	// "stories" or "comments". Empty means every resource.
+	Resources []string
+}
+
+func (r PolicyRule) matches(action PolicyAction, resource string) bool {
+	if r.Action != action {
+		return false
+	}
+	if len(r.Resources) == 0 {
+		return true
+	}
+	return containsString(r.Resources, resource)
+}
+
+// AllowRead allows GET requests for resources, or every resource if
+// none are given.
+func AllowRead(resources ...string) PolicyRule {
+	return PolicyRule{Allow: true, Action: ActionRead, Resources: resources}
+}
+
+// AllowCreate allows creating resources, or every resource if none are
+// given.
+func AllowCreate(resources ...string) PolicyRule {
+	return PolicyRule{Allow: true, Action: ActionCreate, Resources: resources}
+}
+
+// AllowUpdate allows updating resources, or every resource if none are
+// given.
+func AllowUpdate(resources ...string) PolicyRule {
+	return PolicyRule{Allow: true, Action: ActionUpdate, Resources: resources}
+}
+
+// AllowDelete allows deleting resources, or every resource if none are
+// given.
+func AllowDelete(resources ...string) PolicyRule {
+	return PolicyRule{Allow: true, Action: ActionDelete, Resources: resources}
+}
+
+// DenyCreate denies creating resources, or every resource if none are
+// given, even if another rule allows it.
+func DenyCreate(resources ...string) PolicyRule {
+	return PolicyRule{Action: ActionCreate, Resources: resources}
+}
+
+// DenyUpdate denies updating resources, or every resource if none are
+// given, even if another rule allows it.
+func DenyUpdate(resources ...string) PolicyRule {
+	return PolicyRule{Action: ActionUpdate, Resources: resources}
+}
+
+// DenyDelete denies deleting resources, or every resource if none are
+// given, even if another rule allows it.
+func DenyDelete(resources ...string) PolicyRule {
+	return PolicyRule{Action: ActionDelete, Resources: resources}
+}
+
+// Policy restricts what a client may do. Clubhouse tokens can do
+// anything their owner can, so a token embedded in a semi-trusted tool
+// can be constrained by the library even though the API can't. A
+// request is allowed if some rule allows it and no rule denies it;
+// everything else is refused with ErrPolicyDenied before it's sent.
+//
+// For example, a client that can read anything and create stories:
+//
+//	c.Policy = NewPolicy(AllowRead(), AllowCreate("stories"))
+type Policy struct {
+	Rules []PolicyRule
+}
+
+// NewPolicy makes a Policy from rules.
+func NewPolicy(rules ...PolicyRule) *Policy {
+	return &Policy{Rules: rules}
+}
+
+// ErrPolicyDenied is returned for requests the client's Policy doesn't
+// allow.
+type ErrPolicyDenied struct {
+	Method   string
+	Endpoint string
+	Action   PolicyAction
+	Resource string
+}
+
+func (e ErrPolicyDenied) Error() string {
+	return fmt.Sprintf("clubhouse: policy does not allow %s on %s (%s %s)",
+		e.Action, e.Resource, e.Method, e.Endpoint)
+}
+
+// Check returns ErrPolicyDenied if the policy doesn't allow a request.
+func (p *Policy) Check(method, endpoint string) error {
+	action := methodAction(method)
+	_, resource := endpointResource(endpoint)
+	allowed := false
+	for _, r := range p.Rules {
+		if !r.matches(action, resource) {
+			continue
+		}
+		if !r.Allow {
+			allowed = false
+			break
+		}
+		allowed = true
+	}
+	if !allowed {
+		return ErrPolicyDenied{Method: method, Endpoint: endpoint, Action: action, Resource: resource}
+	}
+	return nil
+}
+
+func methodAction(method string) PolicyAction {
+	switch strings.ToUpper(method) {
+	case "POST":
+		return ActionCreate
+	case "PUT", "PATCH":
+		return ActionUpdate
+	case "DELETE":
+		return ActionDelete
+	}
+	return ActionRead
+}
@@ -0,0 +1,79 @@
+package clubhouse
+
+import "testing"
+
+func TestPolicy(t *testing.T) {
+	p := NewPolicy(AllowRead(), AllowCreate("stories", "comments"), AllowDelete(), DenyDelete("epics"))
+	tests := []struct {
+		method   string
+		endpoint string
+		allowed  bool
+	}{
+		{"GET", "epics/12", true},
+		{"GET", "search/stories?page_size=25", true},
+		{"POST", "stories", true},
+		{"POST", "stories/bulk", true},
+		{"POST", "stories/3/comments", true},
+		{"POST", "epics", false},
+		{"PUT", "stories/3", false},
+		{"DELETE", "stories/3", true},
+		{"DELETE", "epics/3", false},
+	}
+	for _, test := range tests {
+		err := p.Check(test.method, test.endpoint)
+		if (err == nil) != test.allowed {
+			t.Errorf("%s %s: expected allowed=%v, got %v", test.method, test.endpoint, test.allowed, err)
+		}
+		if err != nil {
+			if _, ok := err.(ErrPolicyDenied); !ok {
+				t.Errorf("expected ErrPolicyDenied, got %T", err)
+			}
+		}
+	}
+
+	c := &Client{AuthToken: "token", RootURL: "http://127.0.0.1:0/", Policy: NewPolicy(AllowRead())}
+	if err := c.DeleteStory(1); err == nil {
+		t.Error("expected client to refuse a denied request")
+	} else if _, ok := err.(ErrPolicyDenied); !ok {
+		t.Errorf("expected ErrPolicyDenied, got %v", err)
+	}
+}
+
+func TestPolicyUUIDEndpoints(t *testing.T) {
+	uuid := "5d0a3c9e-0b1f-4b3e-9a67-2c6c2b6a1f10"
+	p := NewPolicy(AllowRead(), AllowUpdate(), AllowDelete(), AllowCreate("key-results"),
+		DenyUpdate("groups"), DenyDelete("entity-templates"))
+	tests := []struct {
+		method   string
+		endpoint string
+		allowed  bool
+	}{
+		{"PUT", "groups/" + uuid, false},
+		{"DELETE", "entity-templates/" + uuid, false},
+		{"PUT", "entity-templates/enable", true},
+		{"POST", "key-results/" + uuid, true},
+		{"PUT", "members/" + uuid, true},
+		{"POST", "search/stories", false},
+	}
+	for _, test := range tests {
+		if err := p.Check(test.method, test.endpoint); (err == nil) != test.allowed {
+			t.Errorf("%s %s: expected allowed=%v, got %v", test.method, test.endpoint, test.allowed, err)
+		}
+	}
+
+	resources := map[string][2]string{
+		"groups/" + uuid:           {"groups/:id", "groups"},
+		"epics/3/health":           {"epics/:id/health", "health"},
+		"health/" + uuid:           {"health/:id", "health"},
+		"stories/3/comments/4?x=1": {"stories/:id/comments/:id", "comments"},
+		"stories/bulk":             {"stories/bulk", "stories"},
+		"search/epics":             {"search/epics", "epics"},
+		"entity-templates/enable":  {"entity-templates/enable", "entity-templates"},
+		"member":                   {"member", "member"},
+	}
+	for endpoint, expect := range resources {
+		if pattern, resource := endpointResource(endpoint); pattern != expect[0] || resource != expect[1] {
+			t.Errorf("%s: expected %v, got %s %s", endpoint, expect, pattern, resource)
+		}
+	}
+}
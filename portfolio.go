@@ -0,0 +1,116 @@
+package clubhouse
+
+import (
+	"context"
+	"path"
+)
+
+// ListEpicStories lists the stories in an epic.
+func (c *Client) ListEpicStories(epicID int, opts ...ListOptions) ([]StorySlim, error) {
+	resource := []StorySlim{}
+	uri := listEndpoint(path.Join("epics", itoa(epicID), "stories"), opts)
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// ListEpicStoryBriefs is like ListEpicStories but only decodes the
+// fields in StoryBrief, and doesn't ask for descriptions.
+func (c *Client) ListEpicStoryBriefs(epicID int) ([]StoryBrief, error) {
+	resource := []StoryBrief{}
+	uri := listEndpoint(path.Join("epics", itoa(epicID), "stories"), briefOptions)
+	err := c.RequestResource("GET", &resource, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// PortfolioTree is every objective (milestone) in the workspace, with
+// its epics and their stories.
+type PortfolioTree struct {
+	Objectives []ObjectiveNode
+
+	// Epics aren't in any objective.
+	Epics []EpicNode
+}
+
+// ObjectiveNode is an objective and its epics. Stats adds up the stats
+// of the epics, with LastStoryUpdate being the latest of them.
+type ObjectiveNode struct {
+	Milestone Milestone
+	Epics     []EpicNode
+	Stats     EpicStats
+}
+
+// EpicNode is an epic and its stories.
+type EpicNode struct {
+	Epic    Epic
+	Stories []StoryBrief
+}
+
+// BuildPortfolioTree fetches every objective, epic and story in an epic
+// and assembles them into a tree, for roadmap tools. Objectives and
+// epics are each listed in one request, and stories are listed per
+// epic, up to BulkConcurrency epics at a time. Stories that aren't in
+// an epic aren't included.
+func (c *Client) BuildPortfolioTree(ctx context.Context) (*PortfolioTree, error) {
+	milestones, err := c.ListMilestones()
+	if err != nil {
+		return nil, err
+	}
+	epics, err := c.ListEpicsWithoutComments()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]EpicNode, len(epics))
+	fns := make([]func(context.Context) error, len(epics))
+	for i := range epics {
+		i := i
+		fns[i] = func(context.Context) error {
+			stories, err := c.ListEpicStoryBriefs(epics[i].ID)
+			nodes[i] = EpicNode{Epic: epics[i], Stories: stories}
+			return err
+		}
+	}
+	err = Go(ctx, c.BulkConcurrency, fns...)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &PortfolioTree{Objectives: make([]ObjectiveNode, len(milestones)), Epics: []EpicNode{}}
+	index := map[int]int{}
+	for i, m := range milestones {
+		tree.Objectives[i] = ObjectiveNode{Milestone: m, Epics: []EpicNode{}}
+		index[m.ID] = i
+	}
+	for _, node := range nodes {
+		i, ok := index[node.Epic.MilestoneID]
+		if !ok {
+			tree.Epics = append(tree.Epics, node)
+			continue
+		}
+		objective := &tree.Objectives[i]
+		objective.Epics = append(objective.Epics, node)
+		objective.Stats = addEpicStats(objective.Stats, node.Epic.Stats)
+	}
+	return tree, nil
+}
+
+func addEpicStats(a, b EpicStats) EpicStats {
+	if b.LastStoryUpdate.After(a.LastStoryUpdate) {
+		a.LastStoryUpdate = b.LastStoryUpdate
+	}
+	a.NumPoints += b.NumPoints
+	a.NumPointsDone += b.NumPointsDone
+	a.NumPointsStarted += b.NumPointsStarted
+	a.NumPointsUnstarted += b.NumPointsUnstarted
+	a.NumStoriesDone += b.NumStoriesDone
+	a.NumStoriesStarted += b.NumStoriesStarted
+	a.NumStoriesUnestimated += b.NumStoriesUnestimated
+	a.NumStoriesUnstarted += b.NumStoriesUnstarted
+	return a
+}
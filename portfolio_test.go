@@ -0,0 +1,48 @@
+package clubhouse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBuildPortfolioTree(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/milestones":
+			w.Write([]byte(`[{"id":1,"name":"Grow"},{"id":2,"name":"Empty"}]`))
+		case "/v2/epics":
+			w.Write([]byte(`[
+				{"id":10,"milestone_id":1,"stats":{"num_points":3,"last_story_update":"2019-01-01T00:00:00Z"}},
+				{"id":11,"milestone_id":1,"stats":{"num_points":5,"last_story_update":"2019-02-01T00:00:00Z"}},
+				{"id":12}]`))
+		case "/v2/epics/10/stories":
+			w.Write([]byte(`[{"id":100,"epic_id":10},{"id":101,"epic_id":10}]`))
+		case "/v2/epics/11/stories", "/v2/epics/12/stories":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	tree, err := c.BuildPortfolioTree(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Objectives) != 2 || len(tree.Epics) != 1 || tree.Epics[0].Epic.ID != 12 {
+		t.Fatalf("unexpected tree %+v", tree)
+	}
+	grow := tree.Objectives[0]
+	if len(grow.Epics) != 2 || len(grow.Epics[0].Stories) != 2 || grow.Epics[0].Stories[1].ID != 101 {
+		t.Errorf("unexpected objective %+v", grow)
+	}
+	if grow.Stats.NumPoints != 8 || grow.Stats.LastStoryUpdate.Month() != 2 {
+		t.Errorf("unexpected rollup %+v", grow.Stats)
+	}
+	if len(tree.Objectives[1].Epics) != 0 {
+		t.Errorf("expected empty objective, got %+v", tree.Objectives[1])
+	}
+}
@@ -0,0 +1,93 @@
+package clubhouse
+
+import (
+	"sync"
+	"time"
+)
+
+// ProgressUpdate describes how far along a long-running operation is.
+type ProgressUpdate struct {
+	// Operation is the name of the client method doing the work, e.g.
+	// "SearchStoriesAll".
+	Operation string
+
+	// Done and Total count items. Total is 0 when it isn't known yet.
+	Done  int
+	Total int
+
+	// Endpoint is the endpoint of the request that just finished.
+	Endpoint string
+
+	// ETA is an estimate of the time remaining, based on the pace so
+	// far (which is mostly the pace of the rate limiter). It's zero
+	// when it can't be estimated.
+	ETA time.Duration
+}
+
+// Progress receives updates from long-running operations such as
+// SearchStoriesAll, UpdateEpics and BlockedReport. An operation's
+// updates are delivered one at a time and in order, even when its work
+// runs concurrently, so Update needn't be safe for concurrent use. It
+// should return quickly, since the operation waits for it.
+type Progress interface {
+	Update(ProgressUpdate)
+}
+
+// ProgressFunc lets an ordinary function be used as a Progress.
+type ProgressFunc func(ProgressUpdate)
+
+// Update calls f(u).
+func (f ProgressFunc) Update(u ProgressUpdate) {
+	f(u)
+}
+
+// progressTracker counts finished items for one operation and reports
+// them to the client's Progress. A nil tracker does nothing, so callers
+// don't have to check whether progress reporting is on.
+type progressTracker struct {
+	mu       sync.Mutex
+	progress Progress
+	update   ProgressUpdate
+	clock    Clock
+	start    time.Time
+}
+
+func (c *Client) trackProgress(operation string, total int) *progressTracker {
+	if c.Progress == nil {
+		return nil
+	}
+	return &progressTracker{
+		progress: c.Progress,
+		update:   ProgressUpdate{Operation: operation, Total: total},
+		clock:    c.clock(),
+		start:    c.clock().Now(),
+	}
+}
+
+// setTotal updates the total once it becomes known.
+func (t *progressTracker) setTotal(total int) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.update.Total = total
+	t.mu.Unlock()
+}
+
+// step records n finished items and reports them. The report is made
+// under the lock so updates can't overlap or arrive out of order.
+func (t *progressTracker) step(n int, endpoint string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.update.Done += n
+	t.update.Endpoint = endpoint
+	t.update.ETA = 0
+	if t.update.Done > 0 && t.update.Total > t.update.Done {
+		perItem := t.clock.Now().Sub(t.start) / time.Duration(t.update.Done)
+		t.update.ETA = perItem * time.Duration(t.update.Total-t.update.Done)
+	}
+	t.progress.Update(t.update)
+	t.mu.Unlock()
+}
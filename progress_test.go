@@ -0,0 +1,69 @@
+package clubhouse
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	updates := []ProgressUpdate{}
+	c := &Client{
+		AuthToken:       "tkn",
+		BulkConcurrency: 1,
+		Progress: ProgressFunc(func(u ProgressUpdate) {
+			updates = append(updates, u)
+		}),
+	}
+	c.bulk("Test", "things", []int{1, 2, 3}, func(i, id int) error {
+		return nil
+	})
+	if len(updates) != 3 {
+		t.Fatal("expected 3 updates, got", len(updates))
+	}
+	last := updates[2]
+	if last.Done != 3 || last.Total != 3 || last.Operation != "Test" {
+		t.Errorf("wrong final update %+v", last)
+	}
+	if last.Endpoint != "things/3" {
+		t.Error("wrong endpoint", last.Endpoint)
+	}
+
+	// a nil tracker should be safe to use
+	var tracker *progressTracker
+	tracker.setTotal(10)
+	tracker.step(1, "things")
+}
+
+func TestProgressConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	inUpdate, overlapped := false, false
+	done := []int{}
+	c := &Client{
+		AuthToken:       "tkn",
+		BulkConcurrency: 4,
+		Progress: ProgressFunc(func(u ProgressUpdate) {
+			// deliberately unsynchronized apart from the overlap check
+			mu.Lock()
+			overlapped = overlapped || inUpdate
+			inUpdate = true
+			mu.Unlock()
+			done = append(done, u.Done)
+			mu.Lock()
+			inUpdate = false
+			mu.Unlock()
+		}),
+	}
+	ids := make([]int, 50)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	c.bulk("Test", "things", ids, func(i, id int) error { return nil })
+	if overlapped {
+		t.Error("expected updates not to overlap")
+	}
+	for i, n := range done {
+		if n != i+1 {
+			t.Fatalf("expected updates in order, got %v", done)
+		}
+	}
+}
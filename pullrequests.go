@@ -0,0 +1,110 @@
+package clubhouse
+
+import "sort"
+
+// StoryPullRequests returns every pull request on every branch of a
+// story. The story must have come from GetStory (or similar) so that
+// Branches is populated.
+func StoryPullRequests(story Story) []PullRequest {
+	prs := []PullRequest{}
+	for _, b := range story.Branches {
+		prs = append(prs, b.PullRequests...)
+	}
+	return prs
+}
+
+// LatestOpenPullRequest returns the most recently created pull request
+// on a story that's still open. ok is false if there isn't one.
+func LatestOpenPullRequest(story Story) (pr PullRequest, ok bool) {
+	for _, p := range StoryPullRequests(story) {
+		if p.Closed || p.Merged {
+			continue
+		}
+		if !ok || p.CreatedAt.After(pr.CreatedAt) {
+			pr, ok = p, true
+		}
+	}
+	return pr, ok
+}
+
+// MergedNotDone reports whether a story has had a pull request merged
+// but hasn't been completed, which usually means it's waiting to be
+// deployed or somebody forgot to move it.
+func MergedNotDone(story Story) bool {
+	if story.Completed {
+		return false
+	}
+	for _, p := range StoryPullRequests(story) {
+		if p.Merged {
+			return true
+		}
+	}
+	return false
+}
+
+// DeployCandidate is an entry in a DeployReport.
+type DeployCandidate struct {
+	Story  Story
+	Merged []PullRequest
+	// URL is the story's app link, if the client already knows its
+	// workspace slug (see CachedStoryURL).
+	URL string
+}
+
+// DeployReport lists the stories in a project that are ready to deploy:
+// they have merged pull requests, no open ones, and aren't completed.
+type DeployReport struct {
+	ProjectID int
+	Stories   []DeployCandidate
+}
+
+// ReadyToDeployReport builds a DeployReport for a project. Listing a
+// project's stories doesn't include their branches, so every open story
+// is fetched individually, up to BulkConcurrency at a time.
+func (c *Client) ReadyToDeployReport(projectID int) (*DeployReport, error) {
+	slim, err := c.ListProjectStories(projectID)
+	if err != nil {
+		return nil, err
+	}
+	ids := []int{}
+	for _, s := range slim {
+		if !s.Completed && !s.Archived {
+			ids = append(ids, s.ID)
+		}
+	}
+
+	stories := make([]*Story, len(ids))
+	result := c.bulk("ReadyToDeployReport", "stories", ids, func(i, id int) error {
+		story, err := c.GetStory(id)
+		stories[i] = story
+		return err
+	})
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &DeployReport{ProjectID: projectID, Stories: []DeployCandidate{}}
+	for _, story := range stories {
+		if !MergedNotDone(*story) {
+			continue
+		}
+		if _, open := LatestOpenPullRequest(*story); open {
+			continue
+		}
+		merged := []PullRequest{}
+		for _, p := range StoryPullRequests(*story) {
+			if p.Merged {
+				merged = append(merged, p)
+			}
+		}
+		report.Stories = append(report.Stories, DeployCandidate{
+			Story:  *story,
+			Merged: merged,
+			URL:    c.CachedStoryURL(story.ID),
+		})
+	}
+	sort.SliceStable(report.Stories, func(i, j int) bool {
+		return report.Stories[i].Story.ID < report.Stories[j].Story.ID
+	})
+	return report, nil
+}
@@ -0,0 +1,38 @@
+package clubhouse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPullRequestHelpers(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2019, 1, d, 0, 0, 0, 0, time.UTC) }
+	story := Story{Branches: []Branch{
+		{PullRequests: []PullRequest{
+			{ID: 1, Merged: true, Closed: true, CreatedAt: day(1)},
+			{ID: 2, CreatedAt: day(3)},
+		}},
+		{PullRequests: []PullRequest{
+			{ID: 3, CreatedAt: day(2)},
+			{ID: 4, Closed: true, CreatedAt: day(4)},
+		}},
+	}}
+
+	if n := len(StoryPullRequests(story)); n != 4 {
+		t.Errorf("expected 4 pull requests, got %d", n)
+	}
+	pr, ok := LatestOpenPullRequest(story)
+	if !ok || pr.ID != 2 {
+		t.Errorf("expected latest open PR 2, got %d (%v)", pr.ID, ok)
+	}
+	if !MergedNotDone(story) {
+		t.Error("expected story with a merged PR to be merged-not-done")
+	}
+	story.Completed = true
+	if MergedNotDone(story) {
+		t.Error("expected completed story not to be merged-not-done")
+	}
+	if _, ok := LatestOpenPullRequest(Story{}); ok {
+		t.Error("expected no open PR on a story without branches")
+	}
+}
@@ -0,0 +1,139 @@
+package clubhouse
+
+// ReassignScope controls what ReassignOwner touches.
+type ReassignScope struct {
+	// Stories transfers ownership of open stories.
+	Stories bool
+	// Epics transfers ownership of open epics.
+	Epics bool
+	// Requested also transfers open stories the member requested.
+	Requested bool
+	// RetainFollower keeps the departing member on as a follower of
+	// everything that's transferred.
+	RetainFollower bool
+}
+
+// ReassignResult reports how many resources ReassignOwner touched.
+type ReassignResult struct {
+	Stories   int
+	Requested int
+	Epics     int
+}
+
+// ReassignOwner transfers every open (not done, not archived) story
+// and/or epic owned by the member fromUUID to the member toUUID, which
+// is mostly useful when someone leaves the organization. Stories are
+// updated through the bulk endpoints; epics are updated client-side in
+// the same way as UpdateEpics.
+//
+// The result is filled in as far as it got, even when an error is
+// returned.
+func (c *Client) ReassignOwner(fromUUID, toUUID string, scope ReassignScope) (ReassignResult, error) {
+	result := ReassignResult{}
+
+	from, err := c.GetMember(fromUUID)
+	if err != nil {
+		return result, err
+	}
+	mention := from.Profile.MentionName
+	open := SearchQueryInversions{IsDone: true, IsArchived: true}
+
+	var followers []string
+	if scope.RetainFollower {
+		followers = []string{fromUUID}
+	}
+
+	if scope.Stories {
+		ids, err := c.searchStoryIDs(SearchQuery{
+			Owner:      []string{mention},
+			Inversions: open,
+		})
+		if err != nil {
+			return result, err
+		}
+		updated, err := c.updateStoriesChunked("ReassignOwner", ids, UpdateStoriesParams{
+			OwnerIDsAdd:    []string{toUUID},
+			OwnerIDsRemove: []string{fromUUID},
+			FollowerIDsAdd: followers,
+		})
+		result.Stories = len(updated)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if scope.Requested {
+		ids, err := c.searchStoryIDs(SearchQuery{
+			Requester:  mention,
+			Inversions: open,
+		})
+		if err != nil {
+			return result, err
+		}
+		updated, err := c.updateStoriesChunked("ReassignOwner", ids, UpdateStoriesParams{
+			RequestedByID:  String(toUUID),
+			FollowerIDsAdd: followers,
+		})
+		result.Requested = len(updated)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if scope.Epics {
+		epics, err := c.ListEpics()
+		if err != nil {
+			return result, err
+		}
+		owned := map[int]Epic{}
+		ids := []int{}
+		for _, e := range epics {
+			if e.Archived || e.State == StateDone || !containsString(e.OwnerIDs, fromUUID) {
+				continue
+			}
+			owned[e.ID] = e
+			ids = append(ids, e.ID)
+		}
+		bulk := c.bulk("ReassignOwner", "epics", ids, func(_, id int) error {
+			e := owned[id]
+			params := UpdateEpicParams{
+				OwnerIDs: replaceString(e.OwnerIDs, fromUUID, toUUID),
+			}
+			if scope.RetainFollower && !containsString(e.FollowerIDs, fromUUID) {
+				params.FollowerIDs = append(append([]string{}, e.FollowerIDs...), fromUUID)
+			}
+			_, err := c.UpdateEpic(id, params)
+			return err
+		})
+		result.Epics = len(bulk.Succeeded)
+		if err := bulk.Err(); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceString returns a copy of list with old swapped out for new,
+// without introducing duplicates.
+func replaceString(list []string, old, new string) []string {
+	out := []string{}
+	for _, e := range list {
+		if e == old {
+			e = new
+		}
+		if !containsString(out, e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package clubhouse
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestReplaceString(t *testing.T) {
+	tests := []struct {
+		in, expect []string
+	}{
+		{[]string{"a", "old", "b"}, []string{"a", "new", "b"}},
+		{[]string{"new", "old"}, []string{"new"}},
+		{[]string{"a"}, []string{"a"}},
+		{nil, []string{}},
+	}
+	for _, test := range tests {
+		if got := replaceString(test.in, "old", "new"); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("%v: expected %v, got %v", test.in, test.expect, got)
+		}
+	}
+}
+
+func TestReassignOwnerEpics(t *testing.T) {
+	requests := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		body, _ := ioutil.ReadAll(r.Body)
+		switch key {
+		case "GET /v2/members/old":
+			w.Write([]byte(`{"id":"old","profile":{"mention_name":"leaver"}}`))
+		case "GET /v2/epics":
+			w.Write([]byte(`[
+				{"id":1,"owner_ids":["old","new"],"state":"in progress"},
+				{"id":2,"owner_ids":["old"],"state":"done"},
+				{"id":3,"owner_ids":["old"],"archived":true},
+				{"id":4,"owner_ids":["other"]}]`))
+		case "PUT /v2/epics/1":
+			key += " " + string(body)
+			w.Write([]byte(`{"id":1}`))
+		}
+		requests = append(requests, key)
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	result, err := c.ReassignOwner("old", "new", ReassignScope{Epics: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (ReassignResult{Epics: 1}) {
+		t.Errorf("expected one epic, got %+v", result)
+	}
+	expect := []string{
+		"GET /v2/members/old",
+		"GET /v2/epics",
+		`PUT /v2/epics/1 {"owner_ids":["new"]}`,
+	}
+	if !reflect.DeepEqual(requests, expect) {
+		t.Errorf("expected only open epics to be updated, and no stories searched:\n%v\n%v", expect, requests)
+	}
+}
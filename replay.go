@@ -0,0 +1,94 @@
+package clubhouse
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// tokenPlaceholder is what the AuthToken is replaced with in Curl
+// output.
+const tokenPlaceholder = "$CLUBHOUSE_API_TOKEN"
+
+// Curl renders the failed request as an equivalent curl command, which
+// is handy for reproducing problems or sending them to support. The
+// AuthToken is never included: the command reads it from the
+// CLUBHOUSE_API_TOKEN environment variable instead.
+func (e ErrClientRequest) Curl() string {
+	parts := []string{"curl", "-X", e.Method}
+
+	target := e.URL
+	if u, err := url.Parse(e.URL); err == nil {
+		query := u.Query()
+		_, hasToken := query["token"]
+		query.Del("token")
+		u.RawQuery = query.Encode()
+		target = shellQuote(u.String())
+		if hasToken {
+			sep := "&"
+			if u.RawQuery == "" {
+				sep = "?"
+			}
+			target += shellQuote(sep+"token=") + `"` + tokenPlaceholder + `"`
+		}
+	} else {
+		target = shellQuote(target)
+	}
+	parts = append(parts, target)
+
+	if e.Request != nil {
+		names := make([]string, 0, len(e.Request.Header))
+		for name := range e.Request.Header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if isTokenHeader(name) {
+				parts = append(parts, "-H", shellQuote(name+": ")+`"`+tokenPlaceholder+`"`)
+				continue
+			}
+			for _, value := range e.Request.Header[name] {
+				parts = append(parts, "-H", shellQuote(name+": "+value))
+			}
+		}
+	}
+
+	if len(e.RequestBody) > 0 {
+		parts = append(parts, "--data-binary", shellQuote(string(e.RequestBody)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Replay re-issues the exact request that failed using client c, which
+// may be a different client than the one that made the original
+// request (e.g. one with debugging enabled).
+func (e ErrClientRequest) Replay(c *Client) ([]byte, error) {
+	if e.Endpoint == "" {
+		return nil, fmt.Errorf("clubhouse: can't replay request without an endpoint")
+	}
+	var header *http.Header
+	if e.Request != nil {
+		h := http.Header{}
+		for name, values := range e.Request.Header {
+			h[name] = append([]string{}, values...)
+		}
+		// c sends its own token
+		h.Del(DefaultTokenHeader)
+		h.Del(ShortcutTokenHeader)
+		header = &h
+	}
+	return c.HTTPRequest(e.Method, e.Endpoint, e.RequestBody, header)
+}
+
+func isTokenHeader(name string) bool {
+	return name == http.CanonicalHeaderKey(DefaultTokenHeader) ||
+		name == http.CanonicalHeaderKey(ShortcutTokenHeader)
+}
+
+// shellQuote wraps s in single quotes so it's safe to paste into a
+// POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,33 @@
+package clubhouse
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestErrClientRequestCurl(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://example.com/api/v2/labels?token=secret", nil)
+	req.Header.Add("Content-Type", "application/json")
+	e := ErrClientRequest{
+		Method:      "POST",
+		Endpoint:    "labels",
+		URL:         "https://example.com/api/v2/labels?token=secret",
+		Request:     req,
+		RequestBody: []byte(`{"name":"it's"}`),
+	}
+	expect := `curl -X POST 'https://example.com/api/v2/labels''?token='"$CLUBHOUSE_API_TOKEN" ` +
+		`-H 'Content-Type: application/json' --data-binary '{"name":"it'\''s"}'`
+	if e.Curl() != expect {
+		t.Errorf("%s != %s", e.Curl(), expect)
+	}
+
+	req.URL.RawQuery = ""
+	req.Header.Set("Clubhouse-Token", redactedToken)
+	e.URL = req.URL.String()
+	expect = `curl -X POST 'https://example.com/api/v2/labels' ` +
+		`-H 'Clubhouse-Token: '"$CLUBHOUSE_API_TOKEN" -H 'Content-Type: application/json' ` +
+		`--data-binary '{"name":"it'\''s"}'`
+	if e.Curl() != expect {
+		t.Errorf("%s != %s", e.Curl(), expect)
+	}
+}
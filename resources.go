@@ -51,16 +51,17 @@ type Category struct {
 
 // Comment is any note added within the Comment field of a Story.
 type Comment struct {
-	AuthorID   string    `json:"author_id"`
-	CreatedAt  time.Time `json:"created_at"`
-	EntityType string    `json:"entity_type"`
-	ExternalID string    `json:"external_id"`
-	ID         int       `json:"id"`
-	MentionIDs []string  `json:"mention_ids"`
-	Position   int       `json:"position"`
-	StoryID    int       `json:"story_id"`
-	Text       string    `json:"text"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	AuthorID   string     `json:"author_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	EntityType string     `json:"entity_type"`
+	ExternalID string     `json:"external_id"`
+	ID         int        `json:"id"`
+	MentionIDs []string   `json:"mention_ids"`
+	Position   int        `json:"position"`
+	Reactions  []Reaction `json:"reactions"`
+	StoryID    int        `json:"story_id"`
+	Text       string     `json:"text"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
 // Commit refers to a GitHub commit and all associated details.
@@ -134,6 +135,16 @@ type CreateCommentParams struct {
 	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
 }
 
+// CreateReactionParams ...
+type CreateReactionParams struct {
+	Emoji string `json:"emoji"`
+}
+
+// DeleteReactionParams ...
+type DeleteReactionParams struct {
+	Emoji string `json:"emoji"`
+}
+
 // UpdateCommentParams ...
 type UpdateCommentParams struct {
 	Text string `json:"text"`
@@ -180,6 +191,7 @@ type CreateStoryParams struct {
 	ExternalID          string                  `json:"external_id,omitempty"`
 	FileIDs             []int                   `json:"file_ids,omitempty"`
 	FollowerIDs         []string                `json:"follower_ids,omitempty"`
+	GroupID             string                  `json:"group_id,omitempty"`
 	Labels              []CreateLabelParams     `json:"labels,omitempty"`
 	LinkedFileIDs       []int                   `json:"linked_file_ids,omitempty"`
 	Name                string                  `json:"name,omitempty"`
@@ -204,6 +216,15 @@ type CreateTaskParams struct {
 	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
 }
 
+// UpdateTaskParams ...
+type UpdateTaskParams struct {
+	AfterID     *int     `json:"after_id,omitempty"`
+	BeforeID    *int     `json:"before_id,omitempty"`
+	Complete    *bool    `json:"complete,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	OwnerIDs    []string `json:"owner_ids,omitempty"`
+}
+
 // UpdateStoriesParams ...
 type UpdateStoriesParams struct {
 	AfterID           *int
@@ -213,6 +234,7 @@ type UpdateStoriesParams struct {
 	Estimate          *int
 	FollowerIDsAdd    []string
 	FollowerIDsRemove []string
+	IterationID       *int
 	LabelsAdd         []CreateLabelParams
 	LabelsRemove      []CreateLabelParams
 	LinkedFileIDs     []int
@@ -232,6 +254,7 @@ type updateStoriesParamsResolved struct {
 	Estimate          *json.RawMessage    `json:"estimate,omitempty"`
 	FollowerIDsAdd    []string            `json:"follower_ids_add,omitempty"`
 	FollowerIDsRemove []string            `json:"follower_ids_remove,omitempty"`
+	IterationID       *json.RawMessage    `json:"iteration_id,omitempty"`
 	LabelsAdd         []CreateLabelParams `json:"labels_add,omitempty"`
 	LabelsRemove      []CreateLabelParams `json:"labels_remove,omitempty"`
 	LinkedFileIDs     []int               `json:"linked_file_ids,omitempty"`
@@ -274,6 +297,10 @@ func (p UpdateStoriesParams) MarshalJSON() ([]byte, error) {
 		in:   p.Estimate,
 		out:  &out.Estimate,
 		null: func() bool { return p.Estimate == ResetEstimate },
+	}, {
+		in:   p.IterationID,
+		out:  &out.IterationID,
+		null: func() bool { return p.IterationID == ResetID },
 	}}.Do()
 	return json.Marshal(&out)
 }
@@ -292,6 +319,7 @@ type UpdateStoryParams struct {
 	Estimate            *int
 	FileIDs             []int
 	FollowerIDs         []string
+	GroupID             *string
 	Labels              []CreateLabelParams
 	LinkedFileIDs       []int
 	Name                *string
@@ -315,6 +343,7 @@ type updateStoryParamsResolved struct {
 	Estimate            *json.RawMessage    `json:"estimate,omitempty"`
 	FileIDs             []int               `json:"file_ids,omitempty"`
 	FollowerIDs         []string            `json:"follower_ids,omitempty"`
+	GroupID             *json.RawMessage    `json:"group_id,omitempty"`
 	Labels              []CreateLabelParams `json:"labels,omitempty"`
 	LinkedFileIDs       []int               `json:"linked_file_ids,omitempty"`
 	Name                *string             `json:"name,omitempty"`
@@ -362,6 +391,10 @@ func (p UpdateStoryParams) MarshalJSON() ([]byte, error) {
 		in:   p.Estimate,
 		out:  &out.Estimate,
 		null: func() bool { return p.Estimate == ResetEstimate },
+	}, {
+		in:   p.GroupID,
+		out:  &out.GroupID,
+		null: func() bool { return p.GroupID == ResetGroupID },
 	}, {
 		in:   p.StartedAtOverride,
 		out:  &out.StartedAtOverride,
@@ -383,13 +416,17 @@ type Epic struct {
 	Deadline            time.Time         `json:"deadline"`
 	Description         string            `json:"description"`
 	EntityType          string            `json:"entity_type"`
+	EpicStateID         int               `json:"epic_state_id"`
 	ExternalID          string            `json:"external_id"`
 	FollowerIDs         []string          `json:"follower_ids"`
+	GroupID             string            `json:"group_id"`
+	Health              *Health           `json:"health,omitempty"`
 	ID                  int               `json:"id"`
 	Labels              []Label           `json:"labels"`
 	MilestoneID         int               `json:"milestone_id"`
 	Name                string            `json:"name"`
 	OwnerIDs            []string          `json:"owner_ids"`
+	PlannedStartDate    time.Time         `json:"planned_start_date"`
 	Position            int               `json:"position"`
 	ProjectIDs          []int             `json:"project_ids"`
 	Started             bool              `json:"started"`
@@ -405,8 +442,10 @@ type CreateEpicParams struct {
 	CompletedAtOverride *time.Time          `json:"completed_at_override,omitempty"`
 	CreatedAt           *time.Time          `json:"created_at,omitempty"`
 	Deadline            *time.Time          `json:"deadline,omitempty"`
+	EpicStateID         int                 `json:"epic_state_id,omitempty"`
 	ExternalID          string              `json:"external_id,omitempty"`
 	FollowerIDs         []string            `json:"follower_ids,omitempty"`
+	GroupID             string              `json:"group_id,omitempty"`
 	Labels              []CreateLabelParams `json:"labels,omitempty"`
 	MilestoneID         int                 `json:"milestone_id,omitempty"`
 	Name                string              `json:"name"`
@@ -424,7 +463,9 @@ type UpdateEpicParams struct {
 	CompletedAtOverride *time.Time
 	Deadline            *time.Time
 	Description         *string
+	EpicStateID         *int
 	FollowerIDs         []string
+	GroupID             *string
 	Labels              []CreateLabelParams
 	MilestoneID         *int
 	Name                string
@@ -439,7 +480,9 @@ type updateEpicParamsResolved struct {
 	CompletedAtOverride *json.RawMessage    `json:"completed_at_override,omitempty"`
 	Deadline            *json.RawMessage    `json:"deadline,omitempty"`
 	Description         *string             `json:"description,omitempty"`
+	EpicStateID         *int                `json:"epic_state_id,omitempty"`
 	FollowerIDs         []string            `json:"follower_ids,omitempty"`
+	GroupID             *json.RawMessage    `json:"group_id,omitempty"`
 	Labels              []CreateLabelParams `json:"labels,omitempty"`
 	MilestoneID         *json.RawMessage    `json:"milestone_id,omitempty"`
 	Name                string              `json:"name,omitempty"`
@@ -455,6 +498,7 @@ func (p UpdateEpicParams) MarshalJSON() ([]byte, error) {
 		AfterID:     p.AfterID,
 		BeforeID:    p.BeforeID,
 		Description: p.Description,
+		EpicStateID: p.EpicStateID,
 		FollowerIDs: p.FollowerIDs,
 		Labels:      p.Labels,
 		Name:        p.Name,
@@ -478,11 +522,75 @@ func (p UpdateEpicParams) MarshalJSON() ([]byte, error) {
 		in:   p.MilestoneID,
 		out:  &out.MilestoneID,
 		null: func() bool { return p.MilestoneID == ResetID },
+	}, {
+		in:   p.GroupID,
+		out:  &out.GroupID,
+		null: func() bool { return p.GroupID == ResetGroupID },
 	}}.Do()
 
 	return json.Marshal(&out)
 }
 
+// EpicWorkflow is the workspace's set of states epics move through.
+type EpicWorkflow struct {
+	CreatedAt          time.Time   `json:"created_at"`
+	DefaultEpicStateID int         `json:"default_epic_state_id"`
+	EntityType         string      `json:"entity_type"`
+	EpicStates         []EpicState `json:"epic_states"`
+	ID                 int         `json:"id"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+}
+
+// EpicState is a state in the EpicWorkflow. Like WorkflowStates, each
+// is one of 3 types: Unstarted, Started, or Done.
+type EpicState struct {
+	Color       string            `json:"color"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Description string            `json:"description"`
+	EntityType  string            `json:"entity_type"`
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Position    int               `json:"position"`
+	Type        WorkflowStateType `json:"type"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Health is a status update on an epic, saying whether it's on track
+// along with some notes. An epic's current Health is the latest one.
+type Health struct {
+	AuthorID   string       `json:"author_id"`
+	CreatedAt  time.Time    `json:"created_at"`
+	EntityType string       `json:"entity_type"`
+	EpicID     int          `json:"epic_id"`
+	ID         string       `json:"id"`
+	Status     HealthStatus `json:"status"`
+	Text       string       `json:"text"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// HealthStatus is how an epic is doing.
+type HealthStatus string
+
+// Valid values for HealthStatus
+const (
+	HealthOnTrack  HealthStatus = "On Track"
+	HealthAtRisk   HealthStatus = "At Risk"
+	HealthOffTrack HealthStatus = "Off Track"
+	HealthNone     HealthStatus = "No Health"
+)
+
+// CreateHealthParams ...
+type CreateHealthParams struct {
+	Status HealthStatus `json:"status"`
+	Text   string       `json:"text,omitempty"`
+}
+
+// UpdateHealthParams ...
+type UpdateHealthParams struct {
+	Status HealthStatus `json:"status,omitempty"`
+	Text   *string      `json:"text,omitempty"`
+}
+
 // EpicStats represents a group of calculated values for an Epic.
 type EpicStats struct {
 	LastStoryUpdate       time.Time `json:"last_story_update"`
@@ -496,23 +604,92 @@ type EpicStats struct {
 	NumStoriesUnstarted   int       `json:"num_stories_unstarted"`
 }
 
+// EntityTemplate is a template stories can be created from in the
+// Clubhouse UI.
+type EntityTemplate struct {
+	AuthorID      string        `json:"author_id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	EntityType    string        `json:"entity_type"`
+	ID            string        `json:"id"`
+	LastUsedAt    time.Time     `json:"last_used_at"`
+	Name          string        `json:"name"`
+	StoryContents StoryContents `json:"story_contents"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// StoryContents are the fields an EntityTemplate fills in on a new
+// story.
+type StoryContents struct {
+	Deadline        time.Time `json:"deadline"`
+	Description     string    `json:"description"`
+	EntityType      string    `json:"entity_type"`
+	EpicID          int       `json:"epic_id"`
+	Estimate        int       `json:"estimate"`
+	FollowerIDs     []string  `json:"follower_ids"`
+	GroupID         string    `json:"group_id"`
+	IterationID     int       `json:"iteration_id"`
+	Labels          []Label   `json:"labels"`
+	Name            string    `json:"name"`
+	OwnerIDs        []string  `json:"owner_ids"`
+	ProjectID       int       `json:"project_id"`
+	StoryType       StoryType `json:"story_type"`
+	Tasks           []Task    `json:"tasks"`
+	WorkflowStateID int       `json:"workflow_state_id"`
+}
+
+// CreateStoryContents ...
+type CreateStoryContents struct {
+	Deadline        *time.Time          `json:"deadline,omitempty"`
+	Description     string              `json:"description,omitempty"`
+	EpicID          int                 `json:"epic_id,omitempty"`
+	Estimate        int                 `json:"estimate,omitempty"`
+	FollowerIDs     []string            `json:"follower_ids,omitempty"`
+	GroupID         string              `json:"group_id,omitempty"`
+	IterationID     int                 `json:"iteration_id,omitempty"`
+	Labels          []CreateLabelParams `json:"labels,omitempty"`
+	Name            string              `json:"name,omitempty"`
+	OwnerIDs        []string            `json:"owner_ids,omitempty"`
+	ProjectID       int                 `json:"project_id,omitempty"`
+	StoryType       StoryType           `json:"story_type,omitempty"`
+	Tasks           []CreateTaskParams  `json:"tasks,omitempty"`
+	WorkflowStateID int                 `json:"workflow_state_id,omitempty"`
+}
+
+// CreateEntityTemplateParams ...
+type CreateEntityTemplateParams struct {
+	AuthorID      string              `json:"author_id,omitempty"`
+	Name          string              `json:"name"`
+	StoryContents CreateStoryContents `json:"story_contents"`
+}
+
+// UpdateEntityTemplateParams ...
+type UpdateEntityTemplateParams struct {
+	Name          string               `json:"name,omitempty"`
+	StoryContents *CreateStoryContents `json:"story_contents,omitempty"`
+}
+
 // File is any document uploaded to your Clubhouse. Files attached from a third-party service can be accessed using the Linked Files endpoint.
+//
+// ExternalID is only set for files created by an import or an
+// integration, and is empty otherwise.
 type File struct {
-	ContentType  string    `json:"content_type"`
-	CreatedAt    time.Time `json:"created_at"`
-	Description  string    `json:"description"`
-	EntityType   string    `json:"entity_type"`
-	ExternalID   string    `json:"external_id"`
-	Filename     string    `json:"filename"`
-	ID           int       `json:"id"`
-	MentionIDs   []string  `json:"mention_ids"`
-	Name         string    `json:"name"`
-	Size         int       `json:"size"`
-	StoryIDs     []int     `json:"story_ids"`
-	ThumbnailURL string    `json:"thumbnail_url"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	UploaderID   string    `json:"uploader_id"`
-	URL          string    `json:"url"`
+	ContentType      string    `json:"content_type"`
+	CreatedAt        time.Time `json:"created_at"`
+	Description      string    `json:"description"`
+	EntityType       string    `json:"entity_type"`
+	ExternalID       string    `json:"external_id"`
+	Filename         string    `json:"filename"`
+	GroupMentionIDs  []string  `json:"group_mention_ids"`
+	ID               int       `json:"id"`
+	MemberMentionIDs []string  `json:"member_mention_ids"`
+	MentionIDs       []string  `json:"mention_ids"`
+	Name             string    `json:"name"`
+	Size             int       `json:"size"`
+	StoryIDs         []int     `json:"story_ids"`
+	ThumbnailURL     string    `json:"thumbnail_url"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	UploaderID       string    `json:"uploader_id"`
+	URL              string    `json:"url"`
 }
 
 // UpdateFileParams ...
@@ -525,6 +702,37 @@ type UpdateFileParams struct {
 	UploaderID  *string    `json:"uploader_id,omitempty"`
 }
 
+// History is a single change to a Story, made by a member (or an
+// integration) at one point in time.
+type History struct {
+	Actions    []HistoryAction `json:"actions"`
+	ChangedAt  time.Time       `json:"changed_at"`
+	ExternalID string          `json:"external_id"`
+	ID         string          `json:"id"`
+	MemberID   string          `json:"member_id"`
+	PrimaryID  int             `json:"primary_id"`
+	Version    string          `json:"version"`
+	WebhookID  string          `json:"webhook_id"`
+}
+
+// HistoryAction is something that happened to an entity as part of a
+// History entry.
+type HistoryAction struct {
+	Action     string                   `json:"action"`
+	AppURL     string                   `json:"app_url"`
+	Changes    map[string]HistoryChange `json:"changes"`
+	EntityType string                   `json:"entity_type"`
+	ID         int                      `json:"id"`
+	Name       string                   `json:"name"`
+}
+
+// HistoryChange holds the old and new values of a changed field. The
+// shape of the values depends on the field, so they are left raw.
+type HistoryChange struct {
+	New json.RawMessage `json:"new"`
+	Old json.RawMessage `json:"old"`
+}
+
 // Icon is used to attach images to Organizations, Members, and Loading
 // screens in the Clubhouse web application.
 type Icon struct {
@@ -543,6 +751,154 @@ type Identity struct {
 	Type       string `json:"type"`
 }
 
+// Group is a group of members, e.g. a team, that stories and epics can
+// be assigned to and that can be @-mentioned like a member.
+type Group struct {
+	AppURL            string   `json:"app_url"`
+	Archived          bool     `json:"archived"`
+	Color             string   `json:"color"`
+	ColorKey          string   `json:"color_key"`
+	Description       string   `json:"description"`
+	EntityType        string   `json:"entity_type"`
+	ID                string   `json:"id"`
+	MemberIDs         []string `json:"member_ids"`
+	MentionName       string   `json:"mention_name"`
+	Name              string   `json:"name"`
+	NumEpicsStarted   int      `json:"num_epics_started"`
+	NumStories        int      `json:"num_stories"`
+	NumStoriesStarted int      `json:"num_stories_started"`
+	WorkflowIDs       []int    `json:"workflow_ids"`
+}
+
+// CreateGroupParams ...
+type CreateGroupParams struct {
+	Color       string   `json:"color,omitempty"`
+	ColorKey    string   `json:"color_key,omitempty"`
+	Description string   `json:"description,omitempty"`
+	MemberIDs   []string `json:"member_ids,omitempty"`
+	MentionName string   `json:"mention_name"`
+	Name        string   `json:"name"`
+	WorkflowIDs []int    `json:"workflow_ids,omitempty"`
+}
+
+// UpdateGroupParams ...
+type UpdateGroupParams struct {
+	Archived    *bool    `json:"archived,omitempty"`
+	Color       string   `json:"color,omitempty"`
+	ColorKey    string   `json:"color_key,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	MemberIDs   []string `json:"member_ids,omitempty"`
+	MentionName string   `json:"mention_name,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	WorkflowIDs []int    `json:"workflow_ids,omitempty"`
+}
+
+// Iteration is a timeboxed period of work, e.g. a sprint.
+type Iteration struct {
+	AppURL           string          `json:"app_url"`
+	CreatedAt        time.Time       `json:"created_at"`
+	Description      string          `json:"description"`
+	EndDate          Date            `json:"end_date"`
+	EntityType       string          `json:"entity_type"`
+	FollowerIDs      []string        `json:"follower_ids"`
+	GroupIDs         []string        `json:"group_ids"`
+	GroupMentionIDs  []string        `json:"group_mention_ids"`
+	ID               int             `json:"id"`
+	LabelIDs         []int           `json:"label_ids"`
+	Labels           []Label         `json:"labels"`
+	MemberMentionIDs []string        `json:"member_mention_ids"`
+	MentionIDs       []string        `json:"mention_ids"`
+	Name             string          `json:"name"`
+	StartDate        Date            `json:"start_date"`
+	Stats            IterationStats  `json:"stats"`
+	Status           IterationStatus `json:"status"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// IterationStatus ...
+type IterationStatus string
+
+// Valid values for IterationStatus
+const (
+	IterationStatusUnstarted IterationStatus = "unstarted"
+	IterationStatusStarted   IterationStatus = "started"
+	IterationStatusDone      IterationStatus = "done"
+)
+
+// IterationStats represents a group of calculated values for an
+// Iteration.
+type IterationStats struct {
+	AverageCycleTime      int `json:"average_cycle_time"`
+	AverageLeadTime       int `json:"average_lead_time"`
+	NumPoints             int `json:"num_points"`
+	NumPointsDone         int `json:"num_points_done"`
+	NumPointsStarted      int `json:"num_points_started"`
+	NumPointsUnstarted    int `json:"num_points_unstarted"`
+	NumStoriesDone        int `json:"num_stories_done"`
+	NumStoriesStarted     int `json:"num_stories_started"`
+	NumStoriesUnestimated int `json:"num_stories_unestimated"`
+	NumStoriesUnstarted   int `json:"num_stories_unstarted"`
+}
+
+// CreateIterationParams ...
+type CreateIterationParams struct {
+	Description string              `json:"description,omitempty"`
+	EndDate     Date                `json:"end_date"`
+	FollowerIDs []string            `json:"follower_ids,omitempty"`
+	GroupIDs    []string            `json:"group_ids,omitempty"`
+	Labels      []CreateLabelParams `json:"labels,omitempty"`
+	Name        string              `json:"name"`
+	StartDate   Date                `json:"start_date"`
+}
+
+// UpdateIterationParams ...
+type UpdateIterationParams struct {
+	Description *string             `json:"description,omitempty"`
+	EndDate     Date                `json:"end_date,omitempty"`
+	FollowerIDs []string            `json:"follower_ids,omitempty"`
+	GroupIDs    []string            `json:"group_ids,omitempty"`
+	Labels      []CreateLabelParams `json:"labels,omitempty"`
+	Name        string              `json:"name,omitempty"`
+	StartDate   Date                `json:"start_date,omitempty"`
+}
+
+// KeyResult is a measurable outcome of an objective.
+type KeyResult struct {
+	CurrentObservedValue KeyResultValue `json:"current_observed_value"`
+	CurrentTargetValue   KeyResultValue `json:"current_target_value"`
+	ID                   string         `json:"id"`
+	InitialObservedValue KeyResultValue `json:"initial_observed_value"`
+	Name                 string         `json:"name"`
+	ObjectiveID          int            `json:"objective_id"`
+	Progress             int            `json:"progress"`
+	Type                 KeyResultType  `json:"type"`
+}
+
+// KeyResultType is the kind of value a KeyResult measures.
+type KeyResultType string
+
+// Valid values for KeyResultType
+const (
+	KeyResultNumeric KeyResultType = "numeric"
+	KeyResultPercent KeyResultType = "percent"
+	KeyResultBoolean KeyResultType = "boolean"
+)
+
+// KeyResultValue is a key result value as the API sends it. Numeric
+// and percent values are decimal strings. See Value for building them.
+type KeyResultValue struct {
+	BooleanValue *bool  `json:"boolean_value,omitempty"`
+	NumericValue string `json:"numeric_value,omitempty"`
+}
+
+// UpdateKeyResultParams ...
+type UpdateKeyResultParams struct {
+	InitialObservedValue *KeyResultValue `json:"initial_observed_value,omitempty"`
+	Name                 string          `json:"name,omitempty"`
+	ObservedValue        *KeyResultValue `json:"observed_value,omitempty"`
+	TargetValue          *KeyResultValue `json:"target_value,omitempty"`
+}
+
 // Label can be used to associate and filter Stories and Epics, and also create new Workspaces.
 type Label struct {
 	Archived   bool       `json:"archived"`
@@ -698,6 +1054,23 @@ type Member struct {
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+// MemberInfo is returned when asking about the member that owns the
+// client's AuthToken.
+type MemberInfo struct {
+	ID          string        `json:"id"`
+	MentionName string        `json:"mention_name"`
+	Name        string        `json:"name"`
+	Workspace   WorkspaceInfo `json:"workspace2"`
+}
+
+// WorkspaceInfo describes the workspace the client's AuthToken belongs
+// to.
+type WorkspaceInfo struct {
+	EstimateScale []int  `json:"estimate_scale"`
+	URL           string `json:"url,omitempty"`
+	URLSlug       string `json:"url_slug"`
+}
+
 // Milestone is a collection of Epics that represent a release or some
 // other large initiative that your organization is working on.
 type Milestone struct {
@@ -849,6 +1222,7 @@ type PullRequest struct {
 	CreatedAt      time.Time `json:"created_at"`
 	EntityType     string    `json:"entity_type"`
 	ID             int       `json:"id"`
+	Merged         bool      `json:"merged"`
 	NumAdded       int       `json:"num_added"`
 	NumCommits     int       `json:"num_commits"`
 	NumRemoved     int       `json:"num_removed"`
@@ -859,6 +1233,13 @@ type PullRequest struct {
 	URL            string    `json:"url"`
 }
 
+// Reaction is an emoji reaction to a comment, with the members who
+// reacted with it.
+type Reaction struct {
+	Emoji         string   `json:"emoji"`
+	PermissionIDs []string `json:"permission_ids"`
+}
+
 // Repository refers to a GitHub repository.
 type Repository struct {
 	CreatedAt  time.Time `json:"created_at"`
@@ -898,6 +1279,7 @@ type SearchQuery struct {
 	State         string
 	Text          string
 	Type          StoryType
+	UpdatedSince  time.Time
 	Inversions    SearchQueryInversions
 }
 
@@ -1008,6 +1390,10 @@ func (q SearchQuery) MarshalJSON() ([]byte, error) {
 	if q.Type != "" {
 		parts = append(parts, fmt.Sprintf(`type:%s`, q.Type))
 	}
+	if !q.UpdatedSince.IsZero() {
+		// the operator only has day granularity
+		parts = append(parts, fmt.Sprintf(`updated:%s..*`, q.UpdatedSince.UTC().Format("2006-01-02")))
+	}
 
 	if q.Inversions.Epic != nil {
 		for _, e := range q.Inversions.Epic {
@@ -1103,11 +1489,22 @@ func (q SearchQuery) MarshalJSON() ([]byte, error) {
 
 // SearchParams ...
 type SearchParams struct {
+	Detail   SearchDetail `json:"detail,omitempty"`
 	Next     string       `json:"next,omitempty"`
 	PageSize int          `json:"page_size,omitempty"`
 	Query    *SearchQuery `json:"query,omitempty"`
 }
 
+// SearchDetail controls how much of each story the search endpoints
+// return.
+type SearchDetail string
+
+// Valid values for SearchDetail
+const (
+	SearchDetailFull SearchDetail = "full"
+	SearchDetailSlim SearchDetail = "slim"
+)
+
 // SearchResults represents the results of the search query.
 type SearchResults struct {
 	Data  []StorySearch `json:"data"`
@@ -1115,6 +1512,51 @@ type SearchResults struct {
 	Total int           `json:"total"`
 }
 
+// EpicSearchResults is a page of results from SearchEpics.
+type EpicSearchResults struct {
+	Data  []EpicSearch `json:"data"`
+	Next  string       `json:"next"`
+	Total int          `json:"total"`
+}
+
+// CombinedSearchResults is what Search returns: the first page of
+// matching stories and the first page of matching epics.
+type CombinedSearchResults struct {
+	Epics   EpicSearchResults `json:"epics"`
+	Stories SearchResults     `json:"stories"`
+}
+
+// EpicSearch is an epic in search results.
+type EpicSearch struct {
+	AppURL              string    `json:"app_url"`
+	Archived            bool      `json:"archived"`
+	Completed           bool      `json:"completed"`
+	CompletedAt         time.Time `json:"completed_at"`
+	CompletedAtOverride time.Time `json:"completed_at_override"`
+	CreatedAt           time.Time `json:"created_at"`
+	Deadline            time.Time `json:"deadline"`
+	Description         string    `json:"description"`
+	EntityType          string    `json:"entity_type"`
+	EpicStateID         int       `json:"epic_state_id"`
+	ExternalID          string    `json:"external_id"`
+	FollowerIDs         []string  `json:"follower_ids"`
+	GroupID             string    `json:"group_id"`
+	ID                  int       `json:"id"`
+	Labels              []Label   `json:"labels"`
+	MilestoneID         int       `json:"milestone_id"`
+	Name                string    `json:"name"`
+	OwnerIDs            []string  `json:"owner_ids"`
+	PlannedStartDate    time.Time `json:"planned_start_date"`
+	Position            int       `json:"position"`
+	ProjectIDs          []int     `json:"project_ids"`
+	Started             bool      `json:"started"`
+	StartedAt           time.Time `json:"started_at"`
+	StartedAtOverride   time.Time `json:"started_at_override"`
+	State               State     `json:"state"`
+	Stats               EpicStats `json:"stats"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
 // Story the standard unit of work in Clubhouse and represent individual
 // features, bugs, and chores.
 type Story struct {
@@ -1137,7 +1579,9 @@ type Story struct {
 	ExternalID          string           `json:"external_id"`
 	Files               []File           `json:"files"`
 	FollowerIDs         []string         `json:"follower_ids"`
+	GroupID             string           `json:"group_id"`
 	ID                  int              `json:"id"`
+	IterationID         int              `json:"iteration_id"`
 	Labels              []Label          `json:"labels"`
 	LinkedFiles         []LinkedFile     `json:"linked_files"`
 	MovedAt             time.Time        `json:"moved_at"`
@@ -1153,7 +1597,22 @@ type Story struct {
 	StoryType           StoryType        `json:"story_type"`
 	Tasks               []Task           `json:"tasks"`
 	UpdatedAt           time.Time        `json:"updated_at"`
-	WorflowStateID      int              `json:"worflow_state_id"`
+	WorkflowStateID     int              `json:"workflow_state_id"`
+
+	// Deprecated: WorflowStateID is a misspelling kept so old code
+	// still compiles. It's set to WorkflowStateID when decoding.
+	WorflowStateID int `json:"-"`
+}
+
+// UnmarshalJSON decodes a story, filling in the deprecated
+// WorflowStateID.
+func (s *Story) UnmarshalJSON(b []byte) error {
+	type story Story
+	if err := json.Unmarshal(b, (*story)(s)); err != nil {
+		return err
+	}
+	s.WorflowStateID = s.WorkflowStateID
+	return nil
 }
 
 // StoryLink represents a semantic relationships between two
@@ -1187,7 +1646,9 @@ type StorySearch struct {
 	Estimate            int              `json:"estimate"`
 	ExternalID          string           `json:"external_id"`
 	FollowerIDs         []string         `json:"follower_ids"`
+	GroupID             string           `json:"group_id"`
 	ID                  int              `json:"id"`
+	IterationID         int              `json:"iteration_id"`
 	Labels              []Label          `json:"labels"`
 	MovedAt             time.Time        `json:"moved_at"`
 	Name                string           `json:"name"`
@@ -1221,6 +1682,7 @@ type StorySlim struct {
 	ExternalID          string           `json:"external_id"`
 	FileIDs             []int            `json:"file_ids"`
 	FollowerIDs         []string         `json:"follower_ids"`
+	GroupID             string           `json:"group_id"`
 	ID                  int              `json:"id"`
 	Labels              []Label          `json:"labels"`
 	LinkedFileIDs       []int            `json:"linked_file_ids"`
@@ -1325,3 +1787,19 @@ type WorkflowState struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	Verb        string    `json:"verb"`
 }
+
+// StateType returns Type as a WorkflowStateType. Type stays a plain
+// string so existing code that sets or compares it keeps compiling.
+func (s WorkflowState) StateType() WorkflowStateType {
+	return WorkflowStateType(s.Type)
+}
+
+// WorkflowStateType is the kind of work a WorkflowState represents.
+type WorkflowStateType string
+
+// Valid values for WorkflowStateType
+const (
+	WorkflowStateTypeUnstarted WorkflowStateType = "unstarted"
+	WorkflowStateTypeStarted   WorkflowStateType = "started"
+	WorkflowStateTypeDone      WorkflowStateType = "done"
+)
@@ -0,0 +1,41 @@
+package sanitize
+
+import "testing"
+
+func TestSanitizeJSON(t *testing.T) {
+	s := &Sanitizer{Salt: "salt"}
+	in := `{"id":5,"profile":{"name":"Brian","email_address":"b@corp.com"},"next":"/search?token=abc&page=2"}`
+	out, err := s.JSON([]byte(in))
+	if err != nil {
+		t.Fatal("did not expect error", err)
+	}
+	again, _ := s.JSON([]byte(in))
+	if string(out) != string(again) {
+		t.Error("sanitizing should be deterministic")
+	}
+	expect := `{"id":5,"profile":{"name":"` + s.fake(KindName, "Brian") +
+		`","email_address":"` + s.fake(KindEmail, "b@corp.com") +
+		`"},"next":"/search?token=` + s.fake(KindSecret, "abc") + `&page=2"}`
+	if string(out) != expect {
+		t.Errorf("%s != %s", out, expect)
+	}
+}
+
+func TestSanitizeTokenHeaders(t *testing.T) {
+	s := &Sanitizer{Salt: "salt"}
+	in := `{"headers":{"clubhouse-token":["abc"],"Shortcut-Token":"def","Accept":"application/json"}}`
+	out, err := s.JSON([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"headers":{"clubhouse-token":["` + s.fake(KindSecret, "abc") +
+		`"],"Shortcut-Token":"` + s.fake(KindSecret, "def") + `","Accept":"application/json"}}`
+	if string(out) != expect {
+		t.Errorf("%s != %s", out, expect)
+	}
+
+	text := s.Text([]byte("GET /v2/member HTTP/1.1\r\nCLUBHOUSE-TOKEN: abc\r\n"))
+	if expect := "GET /v2/member HTTP/1.1\r\nCLUBHOUSE-TOKEN: " + s.fake(KindSecret, "abc") + "\r\n"; string(text) != expect {
+		t.Errorf("%q != %q", text, expect)
+	}
+}
@@ -0,0 +1,185 @@
+package clubhouse
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaDrift describes the differences between one resource type as
+// returned by the API and the Go struct that models it.
+type SchemaDrift struct {
+	Resource string `json:"resource"`
+	Endpoint string `json:"endpoint"`
+
+	// Skipped is true when there was no instance of the resource in
+	// the workspace to compare against.
+	Skipped bool `json:"skipped,omitempty"`
+
+	// MissingFromStruct lists fields in the response that the struct
+	// doesn't have. Nested fields are dotted, e.g. "stats.num_points".
+	MissingFromStruct []string `json:"missing_from_struct,omitempty"`
+
+	// MissingFromResponse lists fields in the struct that weren't in
+	// the response.
+	MissingFromResponse []string `json:"missing_from_response,omitempty"`
+}
+
+// SchemaReport is the result of VerifySchemaCompatibility.
+type SchemaReport struct {
+	Resources []SchemaDrift `json:"resources"`
+}
+
+// OK reports whether every resource matched its struct exactly.
+func (r *SchemaReport) OK() bool {
+	for _, d := range r.Resources {
+		if len(d.MissingFromStruct) > 0 || len(d.MissingFromResponse) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// schemaChecks lists the resources checked by VerifySchemaCompatibility.
+var schemaChecks = []struct {
+	resource string
+	endpoint string
+	model    interface{}
+}{
+	{"Category", "categories", Category{}},
+	{"Epic", "epics", Epic{}},
+	{"File", "files", File{}},
+	{"Label", "labels", Label{}},
+	{"LinkedFile", "linked-files", LinkedFile{}},
+	{"Member", "members", Member{}},
+	{"Milestone", "milestones", Milestone{}},
+	{"Project", "projects", Project{}},
+	{"Repository", "repositories", Repository{}},
+	{"Team", "teams", Team{}},
+	{"Workflow", "workflows", Workflow{}},
+}
+
+// VerifySchemaCompatibility fetches one instance of each resource type
+// and compares the fields in the response with the fields of the Go
+// struct for it. Running it on a schedule catches API drift (like new
+// fields being added) early.
+func (c *Client) VerifySchemaCompatibility(ctx context.Context) (*SchemaReport, error) {
+	report := &SchemaReport{}
+	var projectID int
+
+	for _, check := range schemaChecks {
+		first, err := c.firstRaw(ctx, check.endpoint)
+		if err != nil {
+			return nil, err
+		}
+		drift := compareSchema(check.resource, check.endpoint, first, check.model)
+		report.Resources = append(report.Resources, drift)
+
+		if check.resource == "Project" && first != nil {
+			project := Project{}
+			if err := json.Unmarshal(first, &project); err == nil {
+				projectID = project.ID
+			}
+		}
+	}
+
+	// stories can only be listed per project
+	storyDrift := SchemaDrift{Resource: "Story", Skipped: true}
+	if projectID != 0 {
+		endpoint := path.Join("projects", itoa(projectID), "stories")
+		first, err := c.firstRaw(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		storyDrift = compareSchema("Story", endpoint, first, Story{})
+	}
+	report.Resources = append(report.Resources, storyDrift)
+
+	return report, nil
+}
+
+// firstRaw returns the first element of a list endpoint, or nil if the
+// list is empty.
+func (c *Client) firstRaw(ctx context.Context, endpoint string) (json.RawMessage, error) {
+	list := []json.RawMessage{}
+	if err := c.Call(ctx, "GET", endpoint, nil, &list); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+func compareSchema(resource, endpoint string, raw json.RawMessage, model interface{}) SchemaDrift {
+	drift := SchemaDrift{Resource: resource, Endpoint: endpoint}
+	if raw == nil {
+		drift.Skipped = true
+		return drift
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		drift.Skipped = true
+		return drift
+	}
+	walkSchema(decoded, reflect.TypeOf(model), "", &drift)
+	sort.Strings(drift.MissingFromStruct)
+	sort.Strings(drift.MissingFromResponse)
+	return drift
+}
+
+// walkSchema compares a decoded JSON value with type t, recursing into
+// nested objects and the first element of arrays.
+func walkSchema(value interface{}, t reflect.Type, prefix string, drift *SchemaDrift) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		if t.Kind() == reflect.Slice {
+			list, ok := value.([]interface{})
+			if !ok || len(list) == 0 {
+				return
+			}
+			value = list[0]
+		}
+		t = t.Elem()
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok || t.Kind() != reflect.Struct || t == reflect.TypeOf(json.RawMessage{}) {
+		return
+	}
+	if t.PkgPath() != reflect.TypeOf(Story{}).PkgPath() {
+		// e.g. time.Time
+		return
+	}
+
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+
+	for name, v := range object {
+		ft, ok := fields[name]
+		if !ok {
+			drift.MissingFromStruct = append(drift.MissingFromStruct, prefix+name)
+			continue
+		}
+		walkSchema(v, ft, prefix+name+".", drift)
+	}
+	for name := range fields {
+		if _, ok := object[name]; !ok {
+			drift.MissingFromResponse = append(drift.MissingFromResponse, prefix+name)
+		}
+	}
+}
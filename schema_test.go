@@ -0,0 +1,26 @@
+package clubhouse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareSchema(t *testing.T) {
+	raw := []byte(`{
+		"archived": false, "color": "red", "created_at": "2018-04-20T16:20:00Z",
+		"entity_type": "label", "external_id": null, "id": 1, "name": "bug",
+		"updated_at": "2018-04-20T16:20:00Z", "description": "new!",
+		"stats": {"num_epics": 1, "num_related_documents": 2}
+	}`)
+	drift := compareSchema("Label", "labels", raw, Label{})
+	expect := []string{"description", "stats.num_related_documents"}
+	if !reflect.DeepEqual(drift.MissingFromStruct, expect) {
+		t.Errorf("%v != %v", drift.MissingFromStruct, expect)
+	}
+	if len(drift.MissingFromResponse) == 0 {
+		t.Error("expected missing stats fields to be reported")
+	}
+	if drift.MissingFromResponse[0] != "stats.num_points_completed" {
+		t.Error("unexpected first missing field", drift.MissingFromResponse[0])
+	}
+}
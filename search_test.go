@@ -0,0 +1,59 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSearchEpics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body SearchParams
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.URL.Path == "/v2/search":
+			w.Write([]byte(`{"stories":{"data":[{"id":1,"name":"story"}],"total":1},` +
+				`"epics":{"data":[{"id":2,"name":"epic"}],"total":1}}`))
+		case body.Next == "":
+			w.Write([]byte(`{"data":[{"id":2,"name":"a"},{"id":3,"name":"b"}],` +
+				`"next":"/api/v2/search/epics?next=2","total":3}`))
+		default:
+			w.Write([]byte(`{"data":[{"id":3,"name":"b2"},{"id":4,"name":"c"}],"total":3}`))
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	collisions := []string{}
+	c.EpicSearchCollisionHandler = func(earlier, later EpicSearch) {
+		collisions = append(collisions, earlier.Name+"->"+later.Name)
+	}
+
+	params := &SearchParams{Query: &SearchQuery{Raw: "team"}}
+	epics, err := c.SearchEpicsAll(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, e := range epics {
+		names = append(names, e.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b2", "c"}) {
+		t.Errorf("expected a, b2, c, got %v", names)
+	}
+	if !reflect.DeepEqual(collisions, []string{"b->b2"}) {
+		t.Errorf("expected the handler to see b replaced, got %v", collisions)
+	}
+	if params.Next != "" {
+		t.Error("expected params to be left alone")
+	}
+
+	results, err := c.Search(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Stories.Data[0].Name != "story" || results.Epics.Data[0].Name != "epic" {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
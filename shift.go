@@ -0,0 +1,130 @@
+package clubhouse
+
+import (
+	"fmt"
+	"time"
+)
+
+// ShiftScope selects what ShiftDeadlines moves. Only stories and epics
+// that have a deadline and aren't completed are ever moved.
+type ShiftScope struct {
+	// Stories selects stories by search; nil means no stories.
+	Stories *SearchQuery
+	// Epics selects epics; nil means no epics.
+	Epics func(Epic) bool
+}
+
+// ShiftOptions controls how ShiftDeadlines applies changes.
+type ShiftOptions struct {
+	// DryRun reports what would be moved without updating anything.
+	DryRun bool
+	// Comment leaves a comment on everything that's moved saying what
+	// the deadline changed from and to.
+	Comment bool
+}
+
+// DeadlineShift is a deadline ShiftDeadlines moved, or would move.
+type DeadlineShift struct {
+	EntityType string
+	ID         int
+	Name       string
+	From       time.Time
+	To         time.Time
+}
+
+func (s DeadlineShift) comment() *CreateCommentParams {
+	return &CreateCommentParams{Text: fmt.Sprintf("Deadline moved from %s to %s.",
+		s.From.Format("2006-01-02"), s.To.Format("2006-01-02"))}
+}
+
+// ShiftDeadlines moves the deadline of every story and epic in scope by
+// delta, which is useful when a release slips. Stories and epics are
+// each updated up to BulkConcurrency at a time.
+//
+// The shifts that were applied are returned, or in dry-run mode the
+// ones that would have been. If some updates failed, the error is an
+// ErrBulk describing which. A failed comment counts as a failure even
+// though the deadline was moved. Stories and epics are passed to the
+// BulkResultHandler together, as one result.
+func (c *Client) ShiftDeadlines(scope ShiftScope, delta time.Duration, opts ShiftOptions) ([]DeadlineShift, error) {
+	stories := []DeadlineShift{}
+	if scope.Stories != nil {
+		query := *scope.Stories
+		query.HasDeadline = true
+		results, err := c.SearchStoriesAll(&SearchParams{PageSize: 25, Query: &query})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range results {
+			if s.Completed || s.Deadline.IsZero() {
+				continue
+			}
+			stories = append(stories, DeadlineShift{"story", s.ID, s.Name, s.Deadline, s.Deadline.Add(delta)})
+		}
+	}
+
+	epics := []DeadlineShift{}
+	if scope.Epics != nil {
+		results, err := c.ListEpics()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range results {
+			if e.Completed || e.Deadline.IsZero() || !scope.Epics(e) {
+				continue
+			}
+			epics = append(epics, DeadlineShift{"epic", e.ID, e.Name, e.Deadline, e.Deadline.Add(delta)})
+		}
+	}
+
+	if opts.DryRun {
+		return append(stories, epics...), nil
+	}
+
+	// stories and epics are reported as one result
+	c.checkSetup()
+	run := c.startBulk("ShiftDeadlines", "")
+	progress := c.trackProgress("ShiftDeadlines", len(stories)+len(epics))
+	storyErrs := run.each("stories", shiftIDs(stories), progress, func(i, id int) error {
+		if _, err := c.UpdateStory(id, &UpdateStoryParams{Deadline: &stories[i].To}); err != nil {
+			return err
+		}
+		if opts.Comment {
+			_, err := c.CreateStoryComment(id, stories[i].comment())
+			return err
+		}
+		return nil
+	})
+	epicErrs := run.each("epics", shiftIDs(epics), progress, func(i, id int) error {
+		if _, err := c.UpdateEpic(id, UpdateEpicParams{Deadline: &epics[i].To}); err != nil {
+			return err
+		}
+		if opts.Comment {
+			_, err := c.CreateEpicComment(id, epics[i].comment())
+			return err
+		}
+		return nil
+	})
+	result := run.finish()
+
+	shifted := []DeadlineShift{}
+	for _, group := range []struct {
+		shifts []DeadlineShift
+		errs   map[int]error
+	}{{stories, storyErrs}, {epics, epicErrs}} {
+		for _, s := range group.shifts {
+			if _, ok := group.errs[s.ID]; !ok {
+				shifted = append(shifted, s)
+			}
+		}
+	}
+	return shifted, result.Err()
+}
+
+func shiftIDs(shifts []DeadlineShift) []int {
+	ids := make([]int, len(shifts))
+	for i, s := range shifts {
+		ids[i] = s.ID
+	}
+	return ids
+}
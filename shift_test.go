@@ -0,0 +1,87 @@
+package clubhouse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShiftDeadlines(t *testing.T) {
+	var mu sync.Mutex
+	updates := map[string]string{}
+	comments := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/v2/search/stories":
+			var body struct{ Query string }
+			json.NewDecoder(r.Body).Decode(&body)
+			if !strings.Contains(body.Query, "has:deadline") {
+				t.Errorf("expected has:deadline in query, got %q", body.Query)
+			}
+			w.Write([]byte(`{"data":[
+				{"id":1,"name":"a","deadline":"2019-05-01T00:00:00Z"},
+				{"id":2,"name":"b","deadline":"2019-05-01T00:00:00Z","completed":true}]}`))
+		case r.URL.Path == "/v2/epics":
+			w.Write([]byte(`[
+				{"id":10,"name":"release","deadline":"2019-06-01T00:00:00Z"},
+				{"id":11,"name":"other","deadline":"2019-06-01T00:00:00Z"},
+				{"id":12,"name":"release","completed":true,"deadline":"2019-06-01T00:00:00Z"}]`))
+		case strings.HasSuffix(r.URL.Path, "/comments"):
+			var body CreateCommentParams
+			json.NewDecoder(r.Body).Decode(&body)
+			comments = append(comments, r.URL.Path+" "+body.Text)
+			w.Write([]byte(`{}`))
+		case r.Method == "PUT":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			updates[r.URL.Path] = body["deadline"]
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	handled := []BulkResult{}
+	c.BulkResultHandler = func(r BulkResult) { handled = append(handled, r) }
+
+	scope := ShiftScope{
+		Stories: &SearchQuery{Project: "web"},
+		Epics:   func(e Epic) bool { return e.Name == "release" },
+	}
+	week := 7 * 24 * time.Hour
+
+	shifts, err := c.ShiftDeadlines(scope, week, ShiftOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shifts) != 2 || shifts[0].ID != 1 || shifts[1].ID != 10 || len(updates) != 0 {
+		t.Fatalf("expected dry run to plan story 1 and epic 10 only, got %+v, %v", shifts, updates)
+	}
+	if want := time.Date(2019, 5, 8, 0, 0, 0, 0, time.UTC); !shifts[0].To.Equal(want) {
+		t.Errorf("expected new deadline %s, got %s", want, shifts[0].To)
+	}
+
+	shifts, err = c.ShiftDeadlines(scope, week, ShiftOptions{Comment: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shifts) != 2 {
+		t.Errorf("expected 2 shifts, got %+v", shifts)
+	}
+	if updates["/v2/stories/1"] != "2019-05-08T00:00:00Z" || updates["/v2/epics/10"] != "2019-06-08T00:00:00Z" {
+		t.Errorf("unexpected updates %v", updates)
+	}
+	if len(comments) != 2 || !strings.Contains(comments[0]+comments[1], "from 2019-05-01 to 2019-05-08") {
+		t.Errorf("unexpected comments %v", comments)
+	}
+	if len(handled) != 1 || handled[0].Operation != "ShiftDeadlines" || !equalInts(handled[0].Succeeded, []int{1, 10}) {
+		t.Errorf("expected one result for stories and epics, got %+v", handled)
+	}
+}
@@ -0,0 +1,99 @@
+package clubhouse
+
+import "time"
+
+// ListStoriesUpdatedSince returns the stories updated at or after since,
+// along with the high-water mark to pass as since next time: the latest
+// UpdatedAt seen, or since itself if nothing changed. It's meant for
+// incremental syncs. Stories updated exactly at since are returned
+// again, so one updated in the same instant as the last sync isn't
+// missed; each story is returned once, in its latest version.
+//
+// The stories are found with the updated: search operator. If the
+// search fails, it falls back to listing every project's stories and
+// filtering them client-side, which is slower but always works.
+func (c *Client) ListStoriesUpdatedSince(since time.Time) ([]StoryBrief, time.Time, error) {
+	stories, err := c.SearchStoryBriefs(&SearchParams{
+		PageSize: 25,
+		Query:    &SearchQuery{UpdatedSince: since},
+	})
+	if err != nil {
+		c.logf(LogWarn, "", "", "ListStoriesUpdatedSince: search failed, listing projects: %s", err)
+		stories, err = c.listAllStoryBriefs()
+		if err != nil {
+			return nil, since, err
+		}
+	}
+
+	// the search operator only has day granularity, so there may be
+	// stories from earlier on the same day
+	updated := []StoryBrief{}
+	seen := map[int]int{}
+	highWater := since
+	for _, s := range stories {
+		if s.UpdatedAt.Before(since) {
+			continue
+		}
+		if i, ok := seen[s.ID]; ok {
+			if s.UpdatedAt.After(updated[i].UpdatedAt) {
+				updated[i] = s
+			}
+		} else {
+			seen[s.ID] = len(updated)
+			updated = append(updated, s)
+		}
+		if s.UpdatedAt.After(highWater) {
+			highWater = s.UpdatedAt
+		}
+	}
+	return updated, highWater, nil
+}
+
+// ListEpicsUpdatedSince returns the epics updated at or after since,
+// along with the high-water mark to pass as since next time, like
+// ListStoriesUpdatedSince. Epics can't be
+// searched, so every epic is listed (without comments) and filtered
+// client-side.
+func (c *Client) ListEpicsUpdatedSince(since time.Time) ([]Epic, time.Time, error) {
+	epics, err := c.ListEpicsWithoutComments()
+	if err != nil {
+		return nil, since, err
+	}
+	updated := []Epic{}
+	seen := map[int]int{}
+	highWater := since
+	for _, e := range epics {
+		if e.UpdatedAt.Before(since) {
+			continue
+		}
+		if i, ok := seen[e.ID]; ok {
+			if e.UpdatedAt.After(updated[i].UpdatedAt) {
+				updated[i] = e
+			}
+		} else {
+			seen[e.ID] = len(updated)
+			updated = append(updated, e)
+		}
+		if e.UpdatedAt.After(highWater) {
+			highWater = e.UpdatedAt
+		}
+	}
+	return updated, highWater, nil
+}
+
+// listAllStoryBriefs lists the stories in every project.
+func (c *Client) listAllStoryBriefs() ([]StoryBrief, error) {
+	projects, err := c.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	stories := []StoryBrief{}
+	for _, p := range projects {
+		briefs, err := c.ListProjectStoryBriefs(p.ID)
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, briefs...)
+	}
+	return stories, nil
+}
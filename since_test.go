@@ -0,0 +1,50 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListStoriesUpdatedSinceFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/search/stories":
+			w.WriteHeader(400)
+			w.Write([]byte(`{"message":"bad query"}`))
+		case "/v2/projects":
+			w.Write([]byte(`[{"id":1}]`))
+		case "/v2/projects/1/stories":
+			w.Write([]byte(`[
+				{"id":1,"updated_at":"2019-04-02T09:00:00Z"},
+				{"id":2,"updated_at":"2019-04-02T11:00:00Z"},
+				{"id":3,"updated_at":"2019-04-03T08:00:00Z"},
+				{"id":4,"updated_at":"2019-04-02T10:00:00Z"},
+				{"id":2,"updated_at":"2019-04-02T12:00:00Z"}
+			]`))
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+
+	since := time.Date(2019, 4, 2, 10, 0, 0, 0, time.UTC)
+	stories, highWater, err := c.ListStoriesUpdatedSince(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []int{}
+	for _, s := range stories {
+		ids = append(ids, s.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{2, 3, 4}) {
+		t.Errorf("expected stories 2, 3 and 4, got %v", ids)
+	}
+	if !stories[0].UpdatedAt.Equal(time.Date(2019, 4, 2, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the latest copy of story 2, got %s", stories[0].UpdatedAt)
+	}
+	if !highWater.Equal(time.Date(2019, 4, 3, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected high-water mark %s", highWater)
+	}
+}
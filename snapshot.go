@@ -0,0 +1,199 @@
+package clubhouse
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatsSnapshot is the stats of one epic or project at one point in
+// time. Recording these regularly makes it possible to chart trends
+// (scope growth, burn rate) that the point-in-time stats can't show.
+type StatsSnapshot struct {
+	RecordedAt time.Time
+	EntityType string
+	ID         int
+	Name       string
+
+	// Stats holds every numeric stat, keyed by its API name, e.g.
+	// "num_points_done".
+	Stats map[string]int
+}
+
+// SnapshotStore persists StatsSnapshots.
+type SnapshotStore interface {
+	Save(snapshots []StatsSnapshot) error
+}
+
+// CSVSnapshotStore writes snapshots as CSV rows of
+// recorded_at,entity_type,id,name,stat,value. The long format means
+// new stats can show up without changing the columns.
+type CSVSnapshotStore struct {
+	mu     sync.Mutex
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVSnapshotStore makes a store that writes to w. If header is true,
+// a header row is written before the first snapshot.
+func NewCSVSnapshotStore(w io.Writer, header bool) *CSVSnapshotStore {
+	return &CSVSnapshotStore{w: csv.NewWriter(w), header: header}
+}
+
+// Save writes snapshots as CSV rows.
+func (s *CSVSnapshotStore) Save(snapshots []StatsSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.header {
+		s.header = false
+		if err := s.w.Write([]string{"recorded_at", "entity_type", "id", "name", "stat", "value"}); err != nil {
+			return err
+		}
+	}
+	for _, snap := range snapshots {
+		names := make([]string, 0, len(snap.Stats))
+		for name := range snap.Stats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			err := s.w.Write([]string{
+				snap.RecordedAt.UTC().Format(time.RFC3339),
+				snap.EntityType,
+				itoa(snap.ID),
+				snap.Name,
+				name,
+				itoa(snap.Stats[name]),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// StatsRecorder takes snapshots of the stats of every epic and project
+// and saves them to a SnapshotStore.
+type StatsRecorder struct {
+	Client *Client
+	Store  SnapshotStore
+
+	// Interval is how often Run records a snapshot. It must be positive.
+	Interval time.Duration
+
+	loop Loop
+}
+
+// Record takes one snapshot of every unarchived epic and project.
+func (r *StatsRecorder) Record() error {
+	now := r.Client.clock().Now()
+	snapshots := []StatsSnapshot{}
+
+	epics, err := r.Client.ListEpics()
+	if err != nil {
+		return err
+	}
+	for _, e := range epics {
+		if e.Archived {
+			continue
+		}
+		snapshots = append(snapshots, StatsSnapshot{
+			RecordedAt: now,
+			EntityType: "epic",
+			ID:         e.ID,
+			Name:       e.Name,
+			Stats:      flattenStats(e.Stats),
+		})
+	}
+
+	projects, err := r.Client.ListProjects()
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		if p.Archived {
+			continue
+		}
+		snapshots = append(snapshots, StatsSnapshot{
+			RecordedAt: now,
+			EntityType: "project",
+			ID:         p.ID,
+			Name:       p.Name,
+			Stats:      flattenStats(p.Stats),
+		})
+	}
+
+	return r.Store.Save(snapshots)
+}
+
+// Run records a snapshot right away and then every Interval until ctx
+// is done.
+func (r *StatsRecorder) Run(ctx context.Context) error {
+	if r.Interval <= 0 {
+		return errors.New("clubhouse: StatsRecorder needs a positive Interval")
+	}
+	if err := r.Record(); err != nil {
+		return err
+	}
+	ticker := r.Client.clock().NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+			if err := r.Record(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Start is Run in the background, for using a StatsRecorder as a
+// Service. Failed snapshots are sent to Errors rather than stopping it.
+func (r *StatsRecorder) Start(ctx context.Context) error {
+	r.loop.Step = func(context.Context) error { return r.Record() }
+	r.loop.Interval = r.Interval
+	r.loop.Clock = r.Client.clock()
+	return r.loop.Start(ctx)
+}
+
+// Stop stops a StatsRecorder started with Start, waiting for a snapshot
+// in flight to be saved.
+func (r *StatsRecorder) Stop(ctx context.Context) error {
+	return r.loop.Stop(ctx)
+}
+
+// Errors receives the errors from snapshots taken after Start.
+func (r *StatsRecorder) Errors() <-chan error {
+	return r.loop.Errors()
+}
+
+// flattenStats turns the int fields of a stats struct into a map keyed
+// by their JSON names.
+func flattenStats(stats interface{}) map[string]int {
+	out := map[string]int{}
+	v := reflect.ValueOf(stats)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type.Kind() != reflect.Int {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+		out[name] = int(v.Field(i).Int())
+	}
+	return out
+}
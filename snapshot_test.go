@@ -0,0 +1,53 @@
+package clubhouse
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCSVSnapshotStore(t *testing.T) {
+	buf := bytes.Buffer{}
+	store := NewCSVSnapshotStore(&buf, true)
+	at := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	snaps := []StatsSnapshot{{
+		RecordedAt: at,
+		EntityType: "project",
+		ID:         7,
+		Name:       "Web, v2",
+		Stats:      flattenStats(ProjectStats{NumPoints: 13, NumStories: 5}),
+	}}
+	if err := store.Save(snaps); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(snaps[:0]); err != nil {
+		t.Fatal(err)
+	}
+	expect := "recorded_at,entity_type,id,name,stat,value\n" +
+		"2019-03-01T12:00:00Z,project,7,\"Web, v2\",num_points,13\n" +
+		"2019-03-01T12:00:00Z,project,7,\"Web, v2\",num_stories,5\n"
+	if got := buf.String(); got != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+func TestFlattenStatsSkipsNonInts(t *testing.T) {
+	stats := flattenStats(EpicStats{LastStoryUpdate: time.Now(), NumPointsDone: 3})
+	if _, ok := stats["last_story_update"]; ok {
+		t.Error("expected time fields to be skipped")
+	}
+	if stats["num_points_done"] != 3 {
+		t.Errorf("expected num_points_done 3, got %d", stats["num_points_done"])
+	}
+	if len(stats) != 8 {
+		t.Errorf("expected 8 stats, got %d", len(stats))
+	}
+}
+
+func TestStatsRecorderInterval(t *testing.T) {
+	r := &StatsRecorder{Client: &Client{AuthToken: "token"}}
+	if err := r.Run(context.Background()); err == nil {
+		t.Error("expected Run to refuse a zero Interval")
+	}
+}
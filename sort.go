@@ -0,0 +1,156 @@
+package clubhouse
+
+import (
+	"sort"
+	"time"
+)
+
+// StoryKey holds the fields stories are sorted and grouped by. Every
+// story type (Story, StorySlim, StorySearch and StoryBrief) can produce
+// one.
+type StoryKey struct {
+	ID              int
+	Position        int
+	Deadline        time.Time
+	Estimate        int
+	WorkflowStateID int
+	OwnerIDs        []string
+}
+
+// StoryLike is any of the story types.
+type StoryLike interface {
+	SortKey() StoryKey
+}
+
+// SortKey ...
+func (s Story) SortKey() StoryKey {
+	return StoryKey{s.ID, s.Position, s.Deadline, s.Estimate, s.WorkflowStateID, s.OwnerIDs}
+}
+
+// SortKey ...
+func (s StorySlim) SortKey() StoryKey {
+	return StoryKey{s.ID, s.Position, s.Deadline, s.Estimate, s.WorkflowStateID, s.OwnerIDs}
+}
+
+// SortKey ...
+func (s StorySearch) SortKey() StoryKey {
+	return StoryKey{s.ID, s.Position, s.Deadline, s.Estimate, s.WorkflowStateID, s.OwnerIDs}
+}
+
+// SortKey ...
+func (s StoryBrief) SortKey() StoryKey {
+	return StoryKey{s.ID, s.Position, s.Deadline, s.Estimate, s.WorkflowStateID, s.OwnerIDs}
+}
+
+// StoryOrder compares two stories, returning a negative number if a
+// sorts before b, a positive number if after, and zero if they're equal.
+type StoryOrder func(a, b StoryKey) int
+
+// Orders for SortStories.
+var (
+	// ByPosition is the order stories appear in within a column in the
+	// Clubhouse UI.
+	ByPosition StoryOrder = func(a, b StoryKey) int { return compareInts(a.Position, b.Position) }
+
+	// ByDeadline puts the earliest deadline first and stories without a
+	// deadline last.
+	ByDeadline StoryOrder = func(a, b StoryKey) int {
+		switch {
+		case a.Deadline.Equal(b.Deadline):
+			return 0
+		case a.Deadline.IsZero():
+			return 1
+		case b.Deadline.IsZero():
+			return -1
+		case a.Deadline.Before(b.Deadline):
+			return -1
+		}
+		return 1
+	}
+
+	// ByEstimate puts the smallest estimate first.
+	ByEstimate StoryOrder = func(a, b StoryKey) int { return compareInts(a.Estimate, b.Estimate) }
+
+	// ByWorkflowStateID groups stories in the same state together. Use
+	// ByWorkflowState to order them the way the workflow does.
+	ByWorkflowStateID StoryOrder = func(a, b StoryKey) int { return compareInts(a.WorkflowStateID, b.WorkflowStateID) }
+
+	// ByOwner orders by first owner ID, with unowned stories last.
+	ByOwner StoryOrder = func(a, b StoryKey) int {
+		switch {
+		case len(a.OwnerIDs) == 0 && len(b.OwnerIDs) == 0:
+			return 0
+		case len(a.OwnerIDs) == 0:
+			return 1
+		case len(b.OwnerIDs) == 0:
+			return -1
+		case a.OwnerIDs[0] < b.OwnerIDs[0]:
+			return -1
+		case a.OwnerIDs[0] > b.OwnerIDs[0]:
+			return 1
+		}
+		return 0
+	}
+)
+
+// ByWorkflowState orders stories by the position of their state in its
+// workflow, the way columns are laid out in the UI. Stories in states
+// that aren't in workflows sort last.
+func ByWorkflowState(workflows []Workflow) StoryOrder {
+	positions := map[int]int{}
+	for _, w := range workflows {
+		for _, s := range w.States {
+			positions[s.ID] = s.Position
+		}
+	}
+	rank := func(id int) int {
+		if p, ok := positions[id]; ok {
+			return p
+		}
+		return int(^uint(0) >> 1)
+	}
+	return func(a, b StoryKey) int {
+		return compareInts(rank(a.WorkflowStateID), rank(b.WorkflowStateID))
+	}
+}
+
+// compareInts compares a and b without subtracting them, which can
+// overflow for the large positions Clubhouse hands out.
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// SortStories stably sorts stories in place by each order in turn,
+// falling back to the next order on ties. With no orders, stories are
+// sorted by workflow state ID and then position.
+func SortStories[S StoryLike](stories []S, by ...StoryOrder) {
+	if len(by) == 0 {
+		by = []StoryOrder{ByWorkflowStateID, ByPosition}
+	}
+	sort.SliceStable(stories, func(i, j int) bool {
+		a, b := stories[i].SortKey(), stories[j].SortKey()
+		for _, order := range by {
+			if c := order(a, b); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// GroupStoriesBy groups stories by the key keyFn returns for each,
+// keeping their order within each group.
+func GroupStoriesBy[S any, K comparable](stories []S, keyFn func(S) K) map[K][]S {
+	groups := map[K][]S{}
+	for _, s := range stories {
+		k := keyFn(s)
+		groups[k] = append(groups[k], s)
+	}
+	return groups
+}
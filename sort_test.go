@@ -0,0 +1,77 @@
+package clubhouse
+
+import (
+	"testing"
+	"time"
+)
+
+func storyIDs[S StoryLike](stories []S) []int {
+	ids := []int{}
+	for _, s := range stories {
+		ids = append(ids, s.SortKey().ID)
+	}
+	return ids
+}
+
+func TestSortStories(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2019, 5, d, 0, 0, 0, 0, time.UTC) }
+	stories := []StorySlim{
+		{ID: 1, WorkflowStateID: 20, Position: 2},
+		{ID: 2, WorkflowStateID: 10, Position: 5, Deadline: day(3)},
+		{ID: 3, WorkflowStateID: 20, Position: 1, Deadline: day(1)},
+		{ID: 4, WorkflowStateID: 10, Position: 4, OwnerIDs: []string{"b"}},
+		{ID: 5, WorkflowStateID: 30, Position: 3, OwnerIDs: []string{"a"}},
+	}
+
+	SortStories(stories)
+	if got := storyIDs(stories); !equalInts(got, []int{4, 2, 3, 1, 5}) {
+		t.Errorf("default order: got %v", got)
+	}
+
+	SortStories(stories, ByDeadline, ByPosition)
+	if got := storyIDs(stories); !equalInts(got, []int{3, 2, 1, 5, 4}) {
+		t.Errorf("deadline order: got %v", got)
+	}
+
+	SortStories(stories, ByOwner, ByPosition)
+	if got := storyIDs(stories); !equalInts(got, []int{5, 4, 3, 1, 2}) {
+		t.Errorf("owner order: got %v", got)
+	}
+
+	workflows := []Workflow{{States: []WorkflowState{
+		{ID: 30, Position: 1}, {ID: 20, Position: 2}, {ID: 10, Position: 3},
+	}}}
+	SortStories(stories, ByWorkflowState(workflows), ByPosition)
+	if got := storyIDs(stories); !equalInts(got, []int{5, 3, 1, 4, 2}) {
+		t.Errorf("workflow order: got %v", got)
+	}
+}
+
+func TestByPositionLarge(t *testing.T) {
+	max := int(^uint(0) >> 1)
+	stories := []StorySlim{{ID: 1, Position: max}, {ID: 2, Position: -max}, {ID: 3, Position: 0}}
+	SortStories(stories, ByPosition)
+	if got := storyIDs(stories); !equalInts(got, []int{2, 3, 1}) {
+		t.Errorf("expected 2, 3, 1, got %v", got)
+	}
+}
+
+func TestGroupStoriesBy(t *testing.T) {
+	stories := []StoryBrief{{ID: 1, EpicID: 7}, {ID: 2}, {ID: 3, EpicID: 7}}
+	groups := GroupStoriesBy(stories, func(s StoryBrief) int { return s.EpicID })
+	if len(groups) != 2 || !equalInts(storyIDs(groups[7]), []int{1, 3}) || !equalInts(storyIDs(groups[0]), []int{2}) {
+		t.Errorf("unexpected groups %+v", groups)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package clubhouse
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store is a small key-value store with expiring entries. It backs the
+// response cache and anything else in this package that needs to
+// persist state between runs, so each feature doesn't invent its own
+// storage. DiskCache and MemoryStore implement it; adapters for other
+// backends (e.g. Redis) only need these four methods.
+type Store interface {
+	// Get returns the value for key, if there's one that hasn't
+	// expired.
+	Get(key string) ([]byte, bool)
+
+	// Set stores value under key. A ttl of zero means the entry never
+	// expires.
+	Set(key string, value []byte, ttl time.Duration) error
+
+	// Delete removes key. Deleting a key that doesn't exist isn't an
+	// error.
+	Delete(key string) error
+
+	// Keys returns every unexpired key starting with prefix, sorted.
+	Keys(prefix string) ([]string, error)
+}
+
+// MemoryStore is a Store that lives in memory, for tests and
+// short-lived processes. The zero value is an empty store.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+func (e memoryEntry) expired() bool {
+	return !e.expires.IsZero() && time.Now().After(e.expires)
+}
+
+// NewMemoryStore makes an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]memoryEntry{}}
+}
+
+// Get ...
+func (s *MemoryStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || e.expired() {
+		return nil, false
+	}
+	return append([]byte{}, e.value...), true
+}
+
+// Set ...
+func (s *MemoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := memoryEntry{value: append([]byte{}, value...)}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	if s.entries == nil {
+		s.entries = map[string]memoryEntry{}
+	}
+	s.entries[key] = e
+	return nil
+}
+
+// Delete ...
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// Keys ...
+func (s *MemoryStore) Keys(prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := []string{}
+	for key, e := range s.entries {
+		if strings.HasPrefix(key, prefix) && !e.expired() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
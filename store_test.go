@@ -0,0 +1,53 @@
+package clubhouse
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testStore checks the behavior every Store should have.
+func testStore(t *testing.T, store Store) {
+	if keys, err := store.Keys(""); err != nil || len(keys) != 0 {
+		t.Fatalf("expected empty store, got %v %v", keys, err)
+	}
+
+	store.Set("pending/2", []byte("two"), 0)
+	store.Set("pending/1", []byte("one"), time.Hour)
+	store.Set(".hidden key", []byte("dot"), 0)
+	store.Set("gone", []byte("gone"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	if v, ok := store.Get("pending/1"); !ok || string(v) != "one" {
+		t.Errorf("expected one, got %q %v", v, ok)
+	}
+	if v, ok := store.Get(".hidden key"); !ok || string(v) != "dot" {
+		t.Errorf("expected dot, got %q %v", v, ok)
+	}
+	if _, ok := store.Get("gone"); ok {
+		t.Error("expected expired entry to be missing")
+	}
+
+	keys, err := store.Keys("pending/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"pending/1", "pending/2"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+
+	if err := store.Delete("pending/1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("pending/1"); err != nil {
+		t.Errorf("deleting a missing key should not fail: %s", err)
+	}
+	if _, ok := store.Get("pending/1"); ok {
+		t.Error("expected deleted entry to be missing")
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+	testStore(t, &MemoryStore{})
+}
@@ -0,0 +1,65 @@
+package clubhouse
+
+import (
+	"fmt"
+)
+
+// Errors returned by the story link helpers.
+var (
+	ErrSelfLink      = fmt.Errorf("clubhouse: a story can't be linked to itself")
+	ErrDuplicateLink = fmt.Errorf("clubhouse: stories are already linked")
+)
+
+// BlockStory records that the story blockerID blocks the story
+// blockedID.
+func (c *Client) BlockStory(blockerID, blockedID int) (*StoryLink, error) {
+	return c.linkStories(blockerID, VerbBlocks, blockedID)
+}
+
+// MarkDuplicate records that the story dupID is a duplicate of the
+// story canonicalID.
+func (c *Client) MarkDuplicate(dupID, canonicalID int) (*StoryLink, error) {
+	return c.linkStories(dupID, VerbDuplicates, canonicalID)
+}
+
+// RelateStories records that the stories a and b are related.
+func (c *Client) RelateStories(a, b int) (*StoryLink, error) {
+	return c.linkStories(a, VerbRelatesTo, b)
+}
+
+// linkStories creates a "subject verb object" link, e.g. "5 blocks 6",
+// after checking that it isn't a self-link and doesn't already exist.
+func (c *Client) linkStories(subjectID int, verb StoryVerb, objectID int) (*StoryLink, error) {
+	if subjectID == objectID {
+		return nil, ErrSelfLink
+	}
+	subject, err := c.GetStory(subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if hasStoryLink(subject.StoryLinks, subjectID, verb, objectID) {
+		return nil, ErrDuplicateLink
+	}
+	return c.CreateStoryLink(&CreateStoryLinkParams{
+		SubjectID: subjectID,
+		Verb:      verb,
+		ObjectID:  objectID,
+	})
+}
+
+// hasStoryLink checks whether an equivalent link is already in links.
+// "relates to" goes both ways, so either orientation counts.
+func hasStoryLink(links []TypedStoryLink, subjectID int, verb StoryVerb, objectID int) bool {
+	for _, l := range links {
+		if StoryVerb(l.Verb) != verb {
+			continue
+		}
+		if l.SubjectID == subjectID && l.ObjectID == objectID {
+			return true
+		}
+		if verb == VerbRelatesTo && l.SubjectID == objectID && l.ObjectID == subjectID {
+			return true
+		}
+	}
+	return false
+}
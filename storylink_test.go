@@ -0,0 +1,36 @@
+package clubhouse
+
+import "testing"
+
+func TestHasStoryLink(t *testing.T) {
+	links := []TypedStoryLink{
+		{SubjectID: 1, ObjectID: 2, Verb: "blocks"},
+		{SubjectID: 3, ObjectID: 1, Verb: "relates to"},
+	}
+	for _, test := range []struct {
+		Name    string
+		Subject int
+		Verb    StoryVerb
+		Object  int
+		Expect  bool
+	}{
+		{"same link", 1, VerbBlocks, 2, true},
+		{"reversed block", 2, VerbBlocks, 1, false},
+		{"different verb", 1, VerbDuplicates, 2, false},
+		{"reversed relation", 1, VerbRelatesTo, 3, true},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			got := hasStoryLink(links, test.Subject, test.Verb, test.Object)
+			if got != test.Expect {
+				t.Errorf("expected %v, got %v", test.Expect, got)
+			}
+		})
+	}
+}
+
+func TestLinkStoriesSelfLink(t *testing.T) {
+	c := &Client{AuthToken: "tkn"}
+	if _, err := c.BlockStory(10, 10); err != ErrSelfLink {
+		t.Error("expected ErrSelfLink, got", err)
+	}
+}
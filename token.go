@@ -0,0 +1,69 @@
+package clubhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// TokenStatus says why VerifyToken couldn't verify a token.
+type TokenStatus string
+
+// Valid values for TokenStatus
+const (
+	// TokenInvalid means Clubhouse doesn't know the token. Deleted
+	// tokens look the same as ones that never existed.
+	TokenInvalid TokenStatus = "invalid"
+	// TokenRevoked means the token exists but may no longer be used,
+	// usually because its owner was disabled or lost access.
+	TokenRevoked TokenStatus = "revoked"
+	// TokenUnverified means Clubhouse couldn't be reached or didn't
+	// answer properly, so the token may well be fine.
+	TokenUnverified TokenStatus = "unverified"
+)
+
+// ErrToken is returned by VerifyToken when the token couldn't be
+// verified. Err is the underlying error.
+type ErrToken struct {
+	Status TokenStatus
+	Err    error
+}
+
+func (e ErrToken) Error() string {
+	return fmt.Sprintf("clubhouse: token %s: %s", e.Status, e.Err)
+}
+
+// VerifyToken checks the client's AuthToken with a single GET /member
+// and returns the member that owns it and their workspace. It's meant
+// for services to call at startup, before accepting any work, so it
+// bypasses the Cache and retries and gives up when ctx is done. The
+// client's Policy still applies.
+//
+// If the token can't be verified, the error is an ErrToken saying
+// whether it's invalid, revoked, or Clubhouse couldn't be reached.
+// Clubhouse tokens don't expire, so there's no expiry to report.
+//
+// A verified member replaces the one remembered by CurrentMember.
+func (c *Client) VerifyToken(ctx context.Context) (*MemberInfo, error) {
+	if c.AuthToken == "" {
+		return nil, ErrToken{TokenInvalid, fmt.Errorf("no AuthToken set")}
+	}
+	c.checkSetup()
+
+	content, err := c.httpRequest(ctx, requestDirect, "GET", "member", nil, nil)
+	switch {
+	case hasStatus(err, 401):
+		return nil, ErrToken{TokenInvalid, err}
+	case hasStatus(err, 403):
+		return nil, ErrToken{TokenRevoked, err}
+	case err != nil:
+		return nil, ErrToken{TokenUnverified, err}
+	}
+	member := MemberInfo{}
+	if err := json.Unmarshal(content, &member); err != nil {
+		return nil, ErrToken{TokenUnverified, err}
+	}
+	cached := member
+	c.setCurrentMember(&cached)
+	return &member, nil
+}
@@ -0,0 +1,52 @@
+package clubhouse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifyToken(t *testing.T) {
+	status := 200
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != 200 {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"id":"abc","mention_name":"brian","workspace2":{"url_slug":"acme"}}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	member, err := c.VerifyToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if member.MentionName != "brian" || member.Workspace.URLSlug != "acme" {
+		t.Errorf("unexpected member %+v", member)
+	}
+
+	for code, expect := range map[int]TokenStatus{
+		401: TokenInvalid,
+		403: TokenRevoked,
+		502: TokenUnverified,
+	} {
+		status = code
+		_, err := c.VerifyToken(context.Background())
+		if e, ok := err.(ErrToken); !ok || e.Status != expect {
+			t.Errorf("%d: expected %s, got %v", code, expect, err)
+		}
+	}
+
+	srv.Close()
+	_, err = c.VerifyToken(context.Background())
+	if e, ok := err.(ErrToken); !ok || e.Status != TokenUnverified {
+		t.Errorf("expected network failure to be unverified, got %v", err)
+	}
+
+	_, err = (&Client{}).VerifyToken(context.Background())
+	if e, ok := err.(ErrToken); !ok || e.Status != TokenInvalid {
+		t.Errorf("expected missing token to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package clubhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MemberMapping maps member IDs in one workspace to the IDs of the same
+// people in another. Members that aren't in the mapping are dropped.
+type MemberMapping map[string]string
+
+func (m MemberMapping) ids(in []string) []string {
+	out := []string{}
+	for _, id := range in {
+		if mapped, ok := m[id]; ok {
+			out = append(out, mapped)
+		}
+	}
+	return out
+}
+
+// TransferStory moves a story from the workspace of src to the
+// workspace of dst.
+//
+// The story is recreated in the dst project with the same name as its
+// src project, along with its labels, tasks and comments. Files are
+// downloaded with src's token and uploaded again; if the copy can't be
+// created, the uploaded files are deleted. Owners, followers, requester
+// and comment authors are translated with mapping. Once the copy
+// exists, the original is archived and each story gets a comment
+// linking to the other.
+func TransferStory(src, dst *Client, storyID int, mapping MemberMapping) (*Story, error) {
+	story, err := src.GetStory(storyID)
+	if err != nil {
+		return nil, err
+	}
+	project, err := src.GetProject(story.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	dstProject, err := dst.findProject(project.Name)
+	if err != nil {
+		return nil, err
+	}
+	if dstProject == nil {
+		return nil, fmt.Errorf("TransferStory: no project named %q in destination", project.Name)
+	}
+
+	params := CreateStoryParams{
+		Deadline:    timeOrNil(story.Deadline),
+		Description: story.Description,
+		Estimate:    story.Estimate,
+		ExternalID:  story.ExternalID,
+		FollowerIDs: mapping.ids(story.FollowerIDs),
+		Name:        story.Name,
+		OwnerIDs:    mapping.ids(story.OwnerIDs),
+		ProjectID:   dstProject.ID,
+		StoryType:   story.StoryType,
+	}
+	if id, ok := mapping[story.RequestedByID]; ok {
+		params.RequestedByID = id
+	}
+	for _, l := range story.Labels {
+		params.Labels = append(params.Labels, CreateLabelParams{
+			Name:  l.Name,
+			Color: l.Color,
+		})
+	}
+	for _, t := range story.Tasks {
+		params.Tasks = append(params.Tasks, CreateTaskParams{
+			Complete:    t.Complete,
+			Description: t.Description,
+			OwnerIDs:    mapping.ids(t.OwnerIDs),
+		})
+	}
+	for _, cm := range story.Comments {
+		comment := CreateCommentParams{
+			CreatedAt: Time(cm.CreatedAt),
+			Text:      cm.Text,
+		}
+		if id, ok := mapping[cm.AuthorID]; ok {
+			comment.AuthorID = id
+		}
+		params.Comments = append(params.Comments, comment)
+	}
+	params.Comments = append(params.Comments, CreateCommentParams{
+		Text: fmt.Sprintf("Moved from %s", story.AppURL),
+	})
+
+	for _, f := range story.Files {
+		uploaded, err := transferFile(src, dst, f)
+		if err != nil {
+			return nil, deleteFiles(dst, params.FileIDs, err)
+		}
+		params.FileIDs = append(params.FileIDs, uploaded.ID)
+	}
+
+	moved, err := dst.CreateStory(&params)
+	if err != nil {
+		return nil, deleteFiles(dst, params.FileIDs, err)
+	}
+
+	if _, err := src.CreateStoryComment(story.ID, &CreateCommentParams{
+		Text: fmt.Sprintf("Moved to %s", moved.AppURL),
+	}); err != nil {
+		return moved, err
+	}
+	if _, err := src.UpdateStory(story.ID, &UpdateStoryParams{
+		Archived: Archived,
+	}); err != nil {
+		return moved, err
+	}
+	return moved, nil
+}
+
+// transferFile downloads f using src and uploads it again with dst.
+func transferFile(src, dst *Client, f File) (*File, error) {
+	body, err := src.downloadFile(context.Background(), f)
+	if err != nil {
+		return nil, fmt.Errorf("TransferStory: error downloading %s: %s", f.Name, err)
+	}
+	defer body.Close()
+	files, err := dst.UploadFiles([]FileUpload{{Name: f.Name, File: body}})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("TransferStory: upload of %s returned no files", f.Name)
+	}
+	return &files[0], nil
+}
+
+// deleteFiles deletes the files uploaded for a story that couldn't be
+// created, and returns err along with any errors deleting them.
+func deleteFiles(dst *Client, ids []int, err error) error {
+	msgs := []string{}
+	for _, id := range ids {
+		if derr := dst.DeleteFile(id); derr != nil {
+			msgs = append(msgs, derr.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("TransferStory: %s (deleting uploaded files also failed: %s)", err, strings.Join(msgs, "; "))
+	}
+	return err
+}
+
+// timeOrNil returns nil for the zero time, so it's left out of params.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
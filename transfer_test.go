@@ -0,0 +1,56 @@
+package clubhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTransferStoryCleansUpFiles(t *testing.T) {
+	var src *httptest.Server
+	src = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/stories/1":
+			w.Write([]byte(`{"id":1,"project_id":2,"files":[{"id":5,"name":"a.txt","url":"` + src.URL + `/files/a.txt"}]}`))
+		case "/v2/projects/2":
+			w.Write([]byte(`{"id":2,"name":"Mobile"}`))
+		case "/files/a.txt":
+			if r.Header.Get(DefaultTokenHeader) != "src-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("contents"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer src.Close()
+
+	requests := []string{}
+	dst := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		requests = append(requests, key)
+		switch key {
+		case "GET /v2/projects":
+			w.Write([]byte(`[{"id":9,"name":"Mobile"}]`))
+		case "POST /v2/files":
+			w.Write([]byte(`[{"id":77,"name":"a.txt"}]`))
+		case "DELETE /v2/files/77":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer dst.Close()
+
+	srcClient := &Client{AuthToken: "src-token", RootURL: src.URL, Limiter: RateLimiter(0)}
+	dstClient := &Client{AuthToken: "dst-token", RootURL: dst.URL, Limiter: RateLimiter(0)}
+	if _, err := TransferStory(srcClient, dstClient, 1, MemberMapping{}); err == nil {
+		t.Fatal("expected the failed create to be returned")
+	}
+	expect := []string{"GET /v2/projects", "POST /v2/files", "POST /v2/stories", "DELETE /v2/files/77"}
+	if !reflect.DeepEqual(requests, expect) {
+		t.Errorf("expected %v, got %v", expect, requests)
+	}
+}
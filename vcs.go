@@ -0,0 +1,87 @@
+package clubhouse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxSlugLength keeps suggested branch names a reasonable length.
+const maxSlugLength = 50
+
+// CommitToken returns the token Clubhouse's VCS integration recognizes in
+// commit messages to associate a commit with a story, e.g. "[ch1234]".
+func CommitToken(storyID int) string {
+	return fmt.Sprintf("[ch%d]", storyID)
+}
+
+// BranchName suggests a branch name for a story in the format the VCS
+// integration recognizes, e.g. "feature/ch1234/slug-of-name". The prefix
+// is the story type, defaulting to "feature".
+func BranchName(story Story) string {
+	prefix := string(story.StoryType)
+	if prefix == "" {
+		prefix = StoryTypeFeature
+	}
+	name := fmt.Sprintf("%s/ch%d", prefix, story.ID)
+	if slug := Slugify(story.Name); slug != "" {
+		name += "/" + slug
+	}
+	return name
+}
+
+// Slugify lowercases s and replaces every run of characters that aren't
+// letters or digits with a single "-", e.g. "Fix the (broken) login!"
+// becomes "fix-the-broken-login".
+func Slugify(s string) string {
+	b := strings.Builder{}
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		cut := maxSlugLength
+		for !utf8.RuneStart(slug[cut]) {
+			cut--
+		}
+		slug = slug[:cut]
+		if i := strings.LastIndex(slug, "-"); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	return slug
+}
+
+// storyRefPattern matches "ch1234", or "sc-1234" as Shortcut writes it,
+// when it isn't part of a longer word, as in branch names
+// ("feature/ch1234/foo", "feature/sc-1234/foo") and commit messages
+// ("[ch1234]", "[closes sc-1234]").
+var storyRefPattern = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:ch|sc-)(\d+)\b`)
+
+// ParseStoryIDs extracts the story IDs referenced in a branch name or
+// commit message, in order of first appearance and without duplicates.
+func ParseStoryIDs(text string) []int {
+	ids := []int{}
+	seen := map[int]bool{}
+	for _, m := range storyRefPattern.FindAllStringSubmatch(text, -1) {
+		id, err := strconv.Atoi(m[1])
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,49 @@
+package clubhouse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBranchName(t *testing.T) {
+	tests := []struct {
+		story  Story
+		expect string
+	}{
+		{Story{ID: 1234, Name: "Fix the (broken) login!"}, "feature/ch1234/fix-the-broken-login"},
+		{Story{ID: 7, Name: "Crash on save", StoryType: StoryTypeBug}, "bug/ch7/crash-on-save"},
+		{Story{ID: 8, Name: "!!!", StoryType: StoryTypeChore}, "chore/ch8"},
+		{
+			Story{ID: 9, Name: "a very long story name that goes on and on well past the limit"},
+			"feature/ch9/a-very-long-story-name-that-goes-on-and-on-well",
+		},
+	}
+	for _, test := range tests {
+		if got := BranchName(test.story); got != test.expect {
+			t.Errorf("expected %q, got %q", test.expect, got)
+		}
+	}
+}
+
+func TestParseStoryIDs(t *testing.T) {
+	tests := []struct {
+		text   string
+		expect []int
+	}{
+		{"feature/ch1234/slug-of-name", []int{1234}},
+		{"Fix login [ch12] [closes ch13]\n\nAlso see [ch12].", []int{12, 13}},
+		{"CH99-hotfix", []int{99}},
+		{"branch/such/ch/nope", []int{}},
+		{"search12 and match5", []int{}},
+		{"feature/sc-1234/slug [closes SC-56] [ch1234]", []int{1234, 56}},
+		{"disc-7 and sc7", []int{}},
+	}
+	for _, test := range tests {
+		if got := ParseStoryIDs(test.text); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("%q: expected %v, got %v", test.text, test.expect, got)
+		}
+	}
+	if tok := CommitToken(42); tok != "[ch42]" {
+		t.Errorf("expected [ch42], got %s", tok)
+	}
+}
@@ -0,0 +1,269 @@
+package clubhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// WatchEventType is the kind of change a Watcher saw.
+type WatchEventType string
+
+// Valid values for WatchEventType
+const (
+	EntityCreated    WatchEventType = "created"
+	EntityUpdated    WatchEventType = "updated"
+	EntityArchived   WatchEventType = "archived"
+	EntityUnarchived WatchEventType = "unarchived"
+	EntityDeleted    WatchEventType = "deleted"
+)
+
+// WatchEvent is a change to a story or epic seen by a Watcher. For
+// deletions, Name and UpdatedAt are from the last time the entity was
+// seen.
+type WatchEvent struct {
+	Type       WatchEventType
+	EntityType string
+	ID         int
+	Name       string
+	UpdatedAt  time.Time
+}
+
+// Watcher polls for changes to stories and epics. Each poll lists every
+// story and epic and compares them with the last poll, so unlike
+// polling by updated-at (see ListStoriesUpdatedSince) it also sees
+// deletions, and reports archiving and unarchiving as their own events
+// rather than as plain updates.
+type Watcher struct {
+	Client *Client
+
+	// Stories and Epics choose what to watch. If neither is set, both
+	// are watched.
+	Stories bool
+	Epics   bool
+
+	// Interval is how often Run polls. It must be positive.
+	Interval time.Duration
+
+	// Handler is called by Run with each event.
+	Handler func(WatchEvent)
+
+	// Store, if set, checkpoints what each poll saw under StoreKey, so
+	// a Watcher that is restarted picks up where the last one left off:
+	// its first poll reports the changes made in between rather than
+	// just priming.
+	Store Store
+
+	// StoreKey is the key the checkpoint is stored under. If empty,
+	// DefaultWatchStoreKey is used. Watchers sharing a Store need
+	// different keys.
+	StoreKey string
+
+	primed  bool
+	stories map[int]watchedEntity
+	epics   map[int]watchedEntity
+	loop    Loop
+}
+
+// DefaultWatchStoreKey is the key a Watcher's checkpoint is stored
+// under if it has no StoreKey.
+var DefaultWatchStoreKey = "watcher"
+
+type watchedEntity struct {
+	Name      string
+	Archived  bool
+	UpdatedAt time.Time
+}
+
+// watchCheckpoint is what a Watcher persists between runs: every
+// entity it knows about, and the latest UpdatedAt among them. A kind
+// that isn't watched is nil.
+type watchCheckpoint struct {
+	HighWater time.Time
+	Stories   map[int]watchedEntity
+	Epics     map[int]watchedEntity
+}
+
+func (w *Watcher) storeKey() string {
+	if w.StoreKey != "" {
+		return w.StoreKey
+	}
+	return DefaultWatchStoreKey
+}
+
+// loadCheckpoint primes w from its Store, if there's a checkpoint.
+func (w *Watcher) loadCheckpoint() error {
+	content, ok := w.Store.Get(w.storeKey())
+	if !ok {
+		return nil
+	}
+	checkpoint := watchCheckpoint{}
+	if err := json.Unmarshal(content, &checkpoint); err != nil {
+		return fmt.Errorf("Watcher: could not read checkpoint: %s", err)
+	}
+	w.stories, w.epics, w.primed = checkpoint.Stories, checkpoint.Epics, true
+	return nil
+}
+
+func (w *Watcher) saveCheckpoint(stories, epics map[int]watchedEntity) error {
+	checkpoint := watchCheckpoint{Stories: stories, Epics: epics}
+	for _, entities := range []map[int]watchedEntity{stories, epics} {
+		for _, e := range entities {
+			if e.UpdatedAt.After(checkpoint.HighWater) {
+				checkpoint.HighWater = e.UpdatedAt
+			}
+		}
+	}
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := w.Store.Set(w.storeKey(), content, 0); err != nil {
+		return fmt.Errorf("Watcher: could not save checkpoint: %s", err)
+	}
+	return nil
+}
+
+// Poll lists everything being watched and returns what changed since
+// the last poll, ordered by entity type and ID. The first poll only
+// records the current state and returns no events, unless there's a
+// checkpoint in Store to compare with.
+func (w *Watcher) Poll() ([]WatchEvent, error) {
+	if !w.primed && w.Store != nil {
+		if err := w.loadCheckpoint(); err != nil {
+			return nil, err
+		}
+	}
+	both := !w.Stories && !w.Epics
+	var stories, epics map[int]watchedEntity
+
+	if w.Stories || both {
+		briefs, err := w.Client.listAllStoryBriefs()
+		if err != nil {
+			return nil, err
+		}
+		stories = map[int]watchedEntity{}
+		for _, s := range briefs {
+			stories[s.ID] = watchedEntity{s.Name, s.Archived, s.UpdatedAt}
+		}
+	}
+	if w.Epics || both {
+		list, err := w.Client.ListEpicsWithoutComments()
+		if err != nil {
+			return nil, err
+		}
+		epics = map[int]watchedEntity{}
+		for _, e := range list {
+			epics[e.ID] = watchedEntity{e.Name, e.Archived, e.UpdatedAt}
+		}
+	}
+
+	events := []WatchEvent{}
+	// a kind that wasn't watched last time has nothing to compare with
+	if w.primed && w.epics != nil {
+		events = append(events, diffWatched("epic", w.epics, epics)...)
+	}
+	if w.primed && w.stories != nil {
+		events = append(events, diffWatched("story", w.stories, stories)...)
+	}
+	if w.Store != nil {
+		// on failure, keep the old state so the next poll reports
+		// these events again
+		if err := w.saveCheckpoint(stories, epics); err != nil {
+			return nil, err
+		}
+	}
+	w.stories, w.epics, w.primed = stories, epics, true
+	return events, nil
+}
+
+// diffWatched compares two polls of one entity type.
+func diffWatched(entityType string, before, after map[int]watchedEntity) []WatchEvent {
+	events := []WatchEvent{}
+	for id, now := range after {
+		event := WatchEvent{EntityType: entityType, ID: id, Name: now.Name, UpdatedAt: now.UpdatedAt}
+		was, ok := before[id]
+		switch {
+		case !ok:
+			event.Type = EntityCreated
+		case now.Archived && !was.Archived:
+			event.Type = EntityArchived
+		case !now.Archived && was.Archived:
+			event.Type = EntityUnarchived
+		case !now.UpdatedAt.Equal(was.UpdatedAt):
+			event.Type = EntityUpdated
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	for id, was := range before {
+		if _, ok := after[id]; !ok {
+			events = append(events, WatchEvent{
+				Type:       EntityDeleted,
+				EntityType: entityType,
+				ID:         id,
+				Name:       was.Name,
+				UpdatedAt:  was.UpdatedAt,
+			})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events
+}
+
+// Run polls right away and then every Interval until ctx is done,
+// passing every event to Handler. It returns the first error.
+func (w *Watcher) Run(ctx context.Context) error {
+	if w.Interval <= 0 {
+		return fmt.Errorf("clubhouse: Watcher needs a positive Interval")
+	}
+	if err := w.pollAndHandle(); err != nil {
+		return err
+	}
+	ticker := w.Client.clock().NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+			if err := w.pollAndHandle(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Start is Run in the background, for using a Watcher as a Service.
+// Failed polls are sent to Errors rather than stopping it.
+func (w *Watcher) Start(ctx context.Context) error {
+	w.loop.Step = func(context.Context) error { return w.pollAndHandle() }
+	w.loop.Interval = w.Interval
+	w.loop.Clock = w.Client.clock()
+	return w.loop.Start(ctx)
+}
+
+// Stop stops a Watcher started with Start, waiting for the poll in
+// flight and its events to be handled.
+func (w *Watcher) Stop(ctx context.Context) error {
+	return w.loop.Stop(ctx)
+}
+
+// Errors receives the errors from polls made after Start.
+func (w *Watcher) Errors() <-chan error {
+	return w.loop.Errors()
+}
+
+func (w *Watcher) pollAndHandle() error {
+	events, err := w.Poll()
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		w.Handler(e)
+	}
+	return nil
+}
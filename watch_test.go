@@ -0,0 +1,87 @@
+package clubhouse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	stories := `[{"id":1,"updated_at":"2019-01-01T00:00:00Z"},{"id":2},{"id":3}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/projects":
+			w.Write([]byte(`[{"id":1}]`))
+		case "/v2/projects/1/stories":
+			w.Write([]byte(stories))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	w := &Watcher{Client: c, Stories: true}
+
+	events, err := w.Poll()
+	if err != nil || len(events) != 0 {
+		t.Fatalf("expected first poll to prime, got %v, %v", events, err)
+	}
+
+	stories = `[{"id":1,"updated_at":"2019-01-02T00:00:00Z"},{"id":2,"archived":true},{"id":4}]`
+	events, err = w.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, e := range events {
+		got = append(got, string(e.Type)+" "+e.EntityType+" "+itoa(e.ID))
+	}
+	want := []string{"updated story 1", "archived story 2", "deleted story 3", "created story 4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if err := w.Run(context.Background()); err == nil {
+		t.Error("expected Run to refuse a zero Interval")
+	}
+}
+
+func TestWatcherCheckpoint(t *testing.T) {
+	stories := `[{"id":1,"updated_at":"2019-01-01T00:00:00Z"},{"id":2}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/projects":
+			w.Write([]byte(`[{"id":1}]`))
+		case "/v2/projects/1/stories":
+			w.Write([]byte(stories))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{AuthToken: "token", RootURL: srv.URL, Limiter: RateLimiter(0)}
+	store := NewMemoryStore()
+
+	first := &Watcher{Client: c, Stories: true, Store: store}
+	if events, err := first.Poll(); err != nil || len(events) != 0 {
+		t.Fatalf("expected first poll to prime, got %v, %v", events, err)
+	}
+
+	// changes made while no watcher is running
+	stories = `[{"id":1,"updated_at":"2019-01-02T00:00:00Z"},{"id":3}]`
+	restarted := &Watcher{Client: c, Stories: true, Store: store}
+	events, err := restarted.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, e := range events {
+		got = append(got, string(e.Type)+" "+e.EntityType+" "+itoa(e.ID))
+	}
+	want := []string{"updated story 1", "deleted story 2", "created story 3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Event is a decoded webhook payload.
+//
+// Payloads are decoded with json.Decoder.UseNumber, so no number in an
+// event ever goes through float64: IDs keep their exact digits, and the
+// numbers in Changes are json.Numbers.
+type Event struct {
+	ID         string      `json:"id"`
+	ChangedAt  time.Time   `json:"changed_at"`
+	PrimaryID  ID          `json:"primary_id"`
+	MemberID   string      `json:"member_id"`
+	Version    string      `json:"version"`
+	Actions    []Action    `json:"actions"`
+	References []Reference `json:"references"`
+}
+
+// Action is a change to one entity in an Event.
+type Action struct {
+	ID         ID                `json:"id"`
+	EntityType string            `json:"entity_type"`
+	Action     string            `json:"action"`
+	Name       string            `json:"name"`
+	Changes    map[string]Change `json:"changes"`
+}
+
+// Reference is an entity mentioned by an Event's actions, e.g. the
+// workflow state a story moved to.
+type Reference struct {
+	ID         ID     `json:"id"`
+	EntityType string `json:"entity_type"`
+	Name       string `json:"name"`
+}
+
+// Change is the change to one field in an Action. Old and New are set
+// for fields that were replaced, and Adds and Removes for lists.
+type Change struct {
+	Old     interface{}   `json:"old"`
+	New     interface{}   `json:"new"`
+	Adds    []interface{} `json:"adds"`
+	Removes []interface{} `json:"removes"`
+}
+
+// OldID returns Old as an ID, e.g. for "workflow_state_id". ok is false
+// if Old isn't a number or string.
+func (c Change) OldID() (id ID, ok bool) {
+	return toID(c.Old)
+}
+
+// NewID returns New as an ID. ok is false if New isn't a number or
+// string.
+func (c Change) NewID() (id ID, ok bool) {
+	return toID(c.New)
+}
+
+// AddedIDs returns the IDs in Adds, e.g. for "label_ids".
+func (c Change) AddedIDs() []ID {
+	return toIDs(c.Adds)
+}
+
+// RemovedIDs returns the IDs in Removes.
+func (c Change) RemovedIDs() []ID {
+	return toIDs(c.Removes)
+}
+
+// ID is an entity ID from an event. Numeric IDs are kept as their exact
+// digits, and UUIDs (e.g. member IDs) as they are.
+type ID string
+
+// UnmarshalJSON accepts a JSON number or string.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = ID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("webhook: ID must be a number or string, got %s", data)
+	}
+	*id = ID(n)
+	return nil
+}
+
+// Int64 returns the ID as an int64, or an error if it isn't an integer
+// that fits in one.
+func (id ID) Int64() (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("webhook: ID %q is not an int64", string(id))
+	}
+	return n, nil
+}
+
+// Int returns the ID as an int, the type the client uses for IDs, or
+// an error if it isn't an integer that fits in one.
+func (id ID) Int() (int, error) {
+	n, err := strconv.ParseInt(string(id), 10, strconv.IntSize)
+	if err != nil {
+		return 0, fmt.Errorf("webhook: ID %q is not an int", string(id))
+	}
+	return int(n), nil
+}
+
+// String returns the ID as it appeared in the payload.
+func (id ID) String() string {
+	return string(id)
+}
+
+func toID(v interface{}) (ID, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		return ID(v), true
+	case string:
+		return ID(v), true
+	}
+	return "", false
+}
+
+func toIDs(values []interface{}) []ID {
+	ids := []ID{}
+	for _, v := range values {
+		if id, ok := toID(v); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// DecodeEvent decodes a webhook payload without losing the precision of
+// any of its numbers.
+func DecodeEvent(payload []byte) (*Event, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	event := Event{}
+	if err := dec.Decode(&event); err != nil {
+		return nil, fmt.Errorf("webhook: could not decode event: %s", err)
+	}
+	return &event, nil
+}
+
+// ProcessEvents is Process with each payload decoded by DecodeEvent. A
+// payload that can't be decoded counts as a failed attempt.
+func (o *Outbox) ProcessEvents(handle func(event *Event) error) (int, error) {
+	return o.Process(func(payload []byte) error {
+		event, err := DecodeEvent(payload)
+		if err != nil {
+			return err
+		}
+		return handle(event)
+	})
+}
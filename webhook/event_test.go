@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+// 2^53 + 1 can't be represented as a float64
+const bigEvent = `{
+	"id": "5952-85dc",
+	"changed_at": "2019-06-27T16:20:44Z",
+	"primary_id": 9007199254740993,
+	"member_id": "56d8a839-1c52-437f-b981-c3a15a11d6d4",
+	"version": "v1",
+	"actions": [{
+		"id": 9007199254740993,
+		"entity_type": "story",
+		"action": "update",
+		"name": "Big story",
+		"changes": {
+			"workflow_state_id": {"old": 9007199254740995, "new": 500000010},
+			"label_ids": {"adds": [9007199254740997], "removes": [12]},
+			"owner_ids": {"adds": ["56d8a839-1c52-437f-b981-c3a15a11d6d4"]}
+		}
+	}],
+	"references": [{"id": "500000010", "entity_type": "workflow-state", "name": "Done"}]
+}`
+
+func TestDecodeEvent(t *testing.T) {
+	event, err := DecodeEvent([]byte(bigEvent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := event.PrimaryID.Int64(); err != nil || n != 9007199254740993 {
+		t.Errorf("expected exact primary ID, got %d %v", n, err)
+	}
+	action := event.Actions[0]
+	if action.ID != "9007199254740993" {
+		t.Errorf("unexpected action ID %s", action.ID)
+	}
+
+	state := action.Changes["workflow_state_id"]
+	if old, ok := state.OldID(); !ok || old != "9007199254740995" {
+		t.Errorf("expected exact old state, got %q", old)
+	}
+	if id, ok := state.NewID(); !ok {
+		t.Error("expected new state ID")
+	} else if n, err := id.Int(); err != nil || n != 500000010 {
+		t.Errorf("expected new state as int, got %d %v", n, err)
+	}
+	if _, ok := state.New.(json.Number); !ok {
+		t.Errorf("expected changes to hold json.Numbers, got %T", state.New)
+	}
+
+	labels := action.Changes["label_ids"]
+	if !reflect.DeepEqual(labels.AddedIDs(), []ID{"9007199254740997"}) || !reflect.DeepEqual(labels.RemovedIDs(), []ID{"12"}) {
+		t.Errorf("unexpected label changes %v %v", labels.AddedIDs(), labels.RemovedIDs())
+	}
+	owners := action.Changes["owner_ids"].AddedIDs()
+	if len(owners) != 1 || owners[0].String() != event.MemberID {
+		t.Errorf("expected UUIDs to be kept, got %v", owners)
+	}
+	if _, err := ID(event.MemberID).Int64(); err == nil {
+		t.Error("expected a UUID not to be an int64")
+	}
+	if n, _ := event.References[0].ID.Int(); n != 500000010 {
+		t.Errorf("expected string IDs to convert, got %d", n)
+	}
+
+	if _, err := DecodeEvent([]byte(`{"primary_id": true}`)); err == nil {
+		t.Error("expected a bool ID to be rejected")
+	}
+}
+
+func TestOutboxProcessEvents(t *testing.T) {
+	o := &Outbox{Store: clubhouse.NewMemoryStore()}
+	o.Enqueue([]byte(bigEvent))
+	var got ID
+	n, err := o.ProcessEvents(func(e *Event) error {
+		got = e.PrimaryID
+		return nil
+	})
+	if n != 1 || err != nil || got != "9007199254740993" {
+		t.Errorf("expected the event to be processed exactly, got %d %v %s", n, err, got)
+	}
+}
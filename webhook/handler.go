@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+// SignatureHeaders are the headers a payload's signature is read from:
+// Clubhouse's, and the one Shortcut sends it in since the rebrand.
+var SignatureHeaders = []string{"Clubhouse-Signature", "Payload-Signature"}
+
+// MaxPayloadBytes is the largest request body the handlers read. Bigger
+// requests are rejected with 413 Request Entity Too Large.
+var MaxPayloadBytes int64 = 1 << 20
+
+// ErrBadSignature is returned for payloads whose signature is missing
+// or doesn't match the secret.
+var ErrBadSignature = errors.New("webhook: bad signature")
+
+// Sign returns the signature of payload with the webhook's secret: the
+// hex-encoded HMAC-SHA256 of the payload.
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks that signature is payload's signature with
+// secret, returning ErrBadSignature if it isn't.
+func VerifySignature(secret, payload []byte, signature string) error {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrBadSignature
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// verifyRequest checks the signature of a request's payload, if there's
+// a secret to check it with.
+func verifyRequest(secret []byte, r *http.Request, payload []byte) error {
+	if len(secret) == 0 {
+		return nil
+	}
+	for _, name := range SignatureHeaders {
+		if signature := r.Header.Get(name); signature != "" {
+			return VerifySignature(secret, payload, signature)
+		}
+	}
+	return ErrBadSignature
+}
+
+// readPayload reads a webhook request's body and checks its signature,
+// answering the request itself if either fails.
+func readPayload(w http.ResponseWriter, r *http.Request, secret []byte) ([]byte, bool) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxPayloadBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := verifyRequest(secret, r, payload); err != nil {
+		http.Error(w, "bad signature", http.StatusUnauthorized)
+		return nil, false
+	}
+	return payload, true
+}
+
+// Handler is an http.Handler that decodes webhook events and handles
+// them while the request waits. Use an Outbox instead when handling
+// events is slow or might fail, so they're stored first.
+type Handler struct {
+	// Secret, if set, is the webhook's secret. Requests without a
+	// matching signature are rejected with 401 Unauthorized.
+	Secret []byte
+
+	// Handle is called with each event. When it returns an error the
+	// request fails with 500 Internal Server Error.
+	Handle func(event *Event) error
+}
+
+// ServeHTTP decodes the request body and passes it to h.Handle,
+// responding 204 No Content once it's handled.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, ok := readPayload(w, r, h.Secret)
+	if !ok {
+		return
+	}
+	event, err := DecodeEvent(payload)
+	if err != nil {
+		http.Error(w, "malformed payload", http.StatusBadRequest)
+		return
+	}
+	if err := h.Handle(event); err != nil {
+		http.Error(w, "error handling event", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSignature(t *testing.T) {
+	secret, payload := []byte("secret"), []byte(`{"id":"1"}`)
+	signature := Sign(secret, payload)
+	if err := VerifySignature(secret, payload, signature); err != nil {
+		t.Error("expected the signature to match, got", err)
+	}
+	for _, bad := range []string{"", "not hex", Sign([]byte("other"), payload)} {
+		if err := VerifySignature(secret, payload, bad); err != ErrBadSignature {
+			t.Errorf("expected %q to be rejected, got %v", bad, err)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	secret := []byte("secret")
+	events := []*Event{}
+	h := &Handler{Secret: secret, Handle: func(event *Event) error {
+		events = append(events, event)
+		if event.ID == "fail" {
+			return errors.New("failed")
+		}
+		return nil
+	}}
+	post := func(payload, header, signature string) int {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(payload))
+		if header != "" {
+			req.Header.Set(header, signature)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post(bigEvent, "Clubhouse-Signature", Sign(secret, []byte(bigEvent))); code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", code)
+	}
+	if len(events) != 1 || events[0].Actions[0].Name != "Big story" {
+		t.Fatalf("expected the decoded event, got %v", events)
+	}
+	if code := post(bigEvent, "Payload-Signature", Sign(secret, []byte(bigEvent))); code != http.StatusNoContent {
+		t.Errorf("expected the Shortcut header to work, got %d", code)
+	}
+	if code := post(bigEvent, "", ""); code != http.StatusUnauthorized {
+		t.Errorf("expected unsigned requests to be rejected, got %d", code)
+	}
+	if code := post(bigEvent, "Clubhouse-Signature", Sign([]byte("other"), []byte(bigEvent))); code != http.StatusUnauthorized {
+		t.Errorf("expected a bad signature to be rejected, got %d", code)
+	}
+	if code := post(`not json`, "Clubhouse-Signature", Sign(secret, []byte(`not json`))); code != http.StatusBadRequest {
+		t.Errorf("expected a malformed payload to be rejected, got %d", code)
+	}
+	fail := `{"id":"fail"}`
+	if code := post(fail, "Clubhouse-Signature", Sign(secret, []byte(fail))); code != http.StatusInternalServerError {
+		t.Errorf("expected a failed event to be a 500, got %d", code)
+	}
+	if len(events) != 3 {
+		t.Errorf("expected rejected requests not to be handled, got %d events", len(events))
+	}
+}
@@ -0,0 +1,316 @@
+// Package webhook receives Clubhouse webhook events.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+// DefaultMaxAttempts is the number of times Process tries an event before
+// moving it to the dead letters.
+var DefaultMaxAttempts = 5
+
+// DefaultProcessInterval is how often a started Outbox processes events
+// if it has no Interval.
+var DefaultProcessInterval = time.Second
+
+var _ clubhouse.Service = (*Outbox)(nil)
+
+// Key prefixes used in the outbox's Store.
+const (
+	pendingPrefix    = "outbox/pending/"
+	deadPrefix       = "outbox/dead/"
+	deadReasonPrefix = "outbox/dead-reason/"
+)
+
+// DeadLetter is an event that couldn't be processed.
+type DeadLetter struct {
+	ID      string
+	Payload []byte
+	Reason  string
+}
+
+// Outbox is a durable queue that sits between the HTTP handler and
+// whatever processes events. Events are stored before the webhook
+// request is acknowledged, and only removed once they've been handled,
+// so a failing handler doesn't lose events. This gives at-least-once
+// processing: handlers should be idempotent.
+type Outbox struct {
+	Store clubhouse.Store
+
+	// Secret, if set, is the webhook's secret, checked against the
+	// signature of every request to ServeHTTP.
+	Secret []byte
+
+	// MaxAttempts is the number of times an event is tried before it's
+	// moved to the dead letters. If zero, DefaultMaxAttempts is used.
+	MaxAttempts int
+
+	// Handler is called with each event by an Outbox that's been
+	// started, every Interval. If Interval is zero,
+	// DefaultProcessInterval is used.
+	Handler  func(payload []byte) error
+	Interval time.Duration
+
+	mu        sync.Mutex // guards the store's keys
+	processMu sync.Mutex // serializes Process
+	seq       int
+	loop      clubhouse.Loop
+}
+
+// NewOutbox makes an outbox stored on disk in dir, creating dir if it
+// doesn't exist. Events left in dir by older versions, which kept them
+// in dir/pending and dir/dead, are moved into the store.
+func NewOutbox(dir string) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("webhook: could not create outbox: %s", err)
+	}
+	store := &clubhouse.DiskCache{Dir: dir}
+	if err := migrateOutbox(dir, store); err != nil {
+		return nil, fmt.Errorf("webhook: could not migrate outbox: %s", err)
+	}
+	return &Outbox{Store: store}, nil
+}
+
+// migrateOutbox moves the files of the old layout, dir/pending/ID.N and
+// dir/dead/ID.json with dir/dead/ID.reason, to their keys in store.
+// Each file is only removed once it has been stored, so a migration
+// that fails can be run again.
+func migrateOutbox(dir string, store clubhouse.Store) error {
+	legacy := map[string]func(name string) (string, bool){
+		"pending": func(name string) (string, bool) {
+			_, _, ok := parsePendingKey(name)
+			return pendingPrefix + name, ok
+		},
+		"dead": func(name string) (string, bool) {
+			switch filepath.Ext(name) {
+			case ".json":
+				return deadPrefix + strings.TrimSuffix(name, ".json"), true
+			case ".reason":
+				return deadReasonPrefix + strings.TrimSuffix(name, ".reason"), true
+			}
+			return "", false
+		},
+	}
+	for sub, keyFor := range legacy {
+		subdir := filepath.Join(dir, sub)
+		infos, err := ioutil.ReadDir(subdir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			key, ok := keyFor(info.Name())
+			if info.IsDir() || !ok {
+				continue
+			}
+			file := filepath.Join(subdir, info.Name())
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			if err := store.Set(key, content, 0); err != nil {
+				return err
+			}
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+		}
+		// leaves anything that wasn't migrated, e.g. partial writes
+		os.Remove(subdir)
+	}
+	return nil
+}
+
+// Enqueue durably stores an event. Payloads that aren't valid JSON can
+// never be processed, so they go straight to the dead letters.
+func (o *Outbox) Enqueue(payload []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.seq++
+	id := fmt.Sprintf("%020d-%06d", time.Now().UnixNano(), o.seq)
+	if !json.Valid(payload) {
+		return o.deadLetter(id, payload, "malformed payload")
+	}
+	return o.Store.Set(pendingKey(id, 0), payload, 0)
+}
+
+// Process calls handle with each pending event, oldest first. Events are
+// removed (acked) when handle returns nil. When it returns an error the
+// event stays queued for the next call, until it has failed MaxAttempts
+// times and is moved to the dead letters. Process returns the number of
+// events handled successfully.
+//
+// Handlers run without holding up Enqueue, so a slow handler doesn't
+// stall ServeHTTP. Calls to Process itself run one at a time.
+func (o *Outbox) Process(handle func(payload []byte) error) (int, error) {
+	o.processMu.Lock()
+	defer o.processMu.Unlock()
+
+	o.mu.Lock()
+	keys, err := o.Store.Keys(pendingPrefix)
+	o.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	maxAttempts := o.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	processed := 0
+	for _, key := range keys {
+		id, attempts, ok := parsePendingKey(key)
+		if !ok {
+			continue
+		}
+		payload, ok := o.Store.Get(key)
+		if !ok {
+			continue
+		}
+
+		herr := handle(payload)
+		if err := o.settle(key, id, attempts, maxAttempts, payload, herr); err != nil {
+			return processed, err
+		}
+		if herr == nil {
+			processed++
+		}
+	}
+	return processed, nil
+}
+
+// settle acks the pending event at key if herr is nil, and otherwise
+// requeues it with one more attempt, or moves it to the dead letters.
+func (o *Outbox) settle(key, id string, attempts, maxAttempts int, payload []byte, herr error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if herr == nil {
+		return o.Store.Delete(key)
+	}
+
+	attempts++
+	var err error
+	if attempts >= maxAttempts {
+		reason := fmt.Sprintf("failed after %d attempts: %s", attempts, herr)
+		err = o.deadLetter(id, payload, reason)
+	} else {
+		err = o.Store.Set(pendingKey(id, attempts), payload, 0)
+	}
+	if err != nil {
+		return err
+	}
+	return o.Store.Delete(key)
+}
+
+// Start processes events with Handler in the background, making the
+// outbox a clubhouse.Service. Errors from the store are sent to Errors;
+// handler failures are retried and dead-lettered as usual.
+func (o *Outbox) Start(ctx context.Context) error {
+	if o.Handler == nil {
+		return fmt.Errorf("webhook: outbox has no Handler")
+	}
+	process := func(context.Context) error {
+		_, err := o.Process(o.Handler)
+		return err
+	}
+	o.loop.Step = process
+	o.loop.Drain = process
+	o.loop.Interval = o.Interval
+	if o.loop.Interval == 0 {
+		o.loop.Interval = DefaultProcessInterval
+	}
+	return o.loop.Start(ctx)
+}
+
+// Stop stops processing, waiting for the events in flight to be
+// handled, and then makes one last pass over the events still queued.
+// Anything left after that stays in the store for the next Start.
+func (o *Outbox) Stop(ctx context.Context) error {
+	return o.loop.Stop(ctx)
+}
+
+// Errors receives the store errors from processing after Start.
+func (o *Outbox) Errors() <-chan error {
+	return o.loop.Errors()
+}
+
+// Pending returns the number of events waiting to be processed.
+func (o *Outbox) Pending() (int, error) {
+	keys, err := o.Store.Keys(pendingPrefix)
+	return len(keys), err
+}
+
+// DeadLetters returns the events that couldn't be processed.
+func (o *Outbox) DeadLetters() ([]DeadLetter, error) {
+	keys, err := o.Store.Keys(deadPrefix)
+	if err != nil {
+		return nil, err
+	}
+	letters := []DeadLetter{}
+	for _, key := range keys {
+		id := strings.TrimPrefix(key, deadPrefix)
+		payload, ok := o.Store.Get(key)
+		if !ok {
+			continue
+		}
+		reason, _ := o.Store.Get(deadReasonPrefix + id)
+		letters = append(letters, DeadLetter{ID: id, Payload: payload, Reason: string(reason)})
+	}
+	return letters, nil
+}
+
+// ServeHTTP enqueues the request body and responds 202 Accepted once the
+// event is stored. Requests whose signature doesn't match Secret are
+// rejected like Handler rejects them.
+func (o *Outbox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, ok := readPayload(w, r, o.Secret)
+	if !ok {
+		return
+	}
+	if err := o.Enqueue(payload); err != nil {
+		http.Error(w, "error queueing event", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (o *Outbox) deadLetter(id string, payload []byte, reason string) error {
+	if err := o.Store.Set(deadReasonPrefix+id, []byte(reason), 0); err != nil {
+		return err
+	}
+	return o.Store.Set(deadPrefix+id, payload, 0)
+}
+
+// pendingKey encodes the number of failed attempts so far into the key,
+// so retries survive restarts without a separate record.
+func pendingKey(id string, attempts int) string {
+	return pendingPrefix + id + "." + strconv.Itoa(attempts)
+}
+
+func parsePendingKey(key string) (string, int, bool) {
+	name := strings.TrimPrefix(key, pendingPrefix)
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return "", 0, false
+	}
+	attempts, err := strconv.Atoi(name[dot+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return name[:dot], attempts, true
+}
@@ -0,0 +1,240 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+func tempOutbox(t *testing.T) (*Outbox, func()) {
+	dir, err := ioutil.TempDir("", "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := NewOutbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o, func() { os.RemoveAll(dir) }
+}
+
+func TestOutboxAck(t *testing.T) {
+	o, cleanup := tempOutbox(t)
+	defer cleanup()
+	o.Enqueue([]byte(`{"id":1}`))
+	o.Enqueue([]byte(`{"id":2}`))
+
+	seen := []string{}
+	n, err := o.Process(func(p []byte) error {
+		seen = append(seen, string(p))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || strings.Join(seen, " ") != `{"id":1} {"id":2}` {
+		t.Errorf("expected both events in order, got %d %v", n, seen)
+	}
+	if pending, _ := o.Pending(); pending != 0 {
+		t.Errorf("expected empty outbox, got %d pending", pending)
+	}
+}
+
+func TestOutboxRetryAndDeadLetter(t *testing.T) {
+	o, cleanup := tempOutbox(t)
+	defer cleanup()
+	o.MaxAttempts = 2
+	o.Enqueue([]byte(`{"id":1}`))
+	o.Enqueue([]byte(`not json`))
+
+	fail := func(p []byte) error { return errors.New("boom") }
+	o.Process(fail)
+	if pending, _ := o.Pending(); pending != 1 {
+		t.Fatalf("expected event to stay queued, got %d pending", pending)
+	}
+	o.Process(fail)
+	if pending, _ := o.Pending(); pending != 0 {
+		t.Fatalf("expected event to be dead-lettered, got %d pending", pending)
+	}
+
+	letters, err := o.DeadLetters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letters) != 2 {
+		t.Fatalf("expected 2 dead letters, got %d", len(letters))
+	}
+	reasons := letters[0].Reason + "|" + letters[1].Reason
+	if !strings.Contains(reasons, "malformed payload") || !strings.Contains(reasons, "failed after 2 attempts: boom") {
+		t.Errorf("unexpected reasons: %s", reasons)
+	}
+}
+
+func TestOutboxServeHTTP(t *testing.T) {
+	o, cleanup := tempOutbox(t)
+	defer cleanup()
+	rec := httptest.NewRecorder()
+	o.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"id":1}`)))
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected 202, got %d", rec.Code)
+	}
+	if pending, _ := o.Pending(); pending != 1 {
+		t.Errorf("expected 1 pending event, got %d", pending)
+	}
+
+	o.Secret = []byte("secret")
+	rec = httptest.NewRecorder()
+	o.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"id":2}`)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected unsigned events to be rejected, got %d", rec.Code)
+	}
+	if pending, _ := o.Pending(); pending != 1 {
+		t.Errorf("expected the rejected event not to be stored, got %d pending", pending)
+	}
+}
+
+func TestOutboxMemoryStore(t *testing.T) {
+	o := &Outbox{Store: clubhouse.NewMemoryStore()}
+	o.Enqueue([]byte(`{"id":1}`))
+	if n, err := o.Process(func([]byte) error { return nil }); n != 1 || err != nil {
+		t.Errorf("expected 1 event processed, got %d %v", n, err)
+	}
+}
+
+func TestOutboxStartStop(t *testing.T) {
+	o := &Outbox{Store: clubhouse.NewMemoryStore(), Interval: time.Hour}
+	if err := o.Start(context.Background()); err == nil {
+		t.Error("expected Start to need a Handler")
+	}
+	handled := make(chan string, 10)
+	o.Handler = func(p []byte) error {
+		handled <- string(p)
+		return nil
+	}
+	if err := o.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	o.Enqueue([]byte(`{"id":1}`))
+	if err := o.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := o.Pending(); pending != 0 {
+		t.Errorf("expected Stop to drain the outbox, got %d pending", pending)
+	}
+	if p := <-handled; p != `{"id":1}` {
+		t.Errorf("unexpected event %s", p)
+	}
+}
+
+func TestOutboxEnqueueWhileProcessing(t *testing.T) {
+	o, cleanup := tempOutbox(t)
+	defer cleanup()
+	o.Enqueue([]byte(`{"id":1}`))
+
+	handling, release := make(chan bool), make(chan bool)
+	done := make(chan error)
+	go func() {
+		_, err := o.Process(func(payload []byte) error {
+			handling <- true
+			<-release
+			return nil
+		})
+		done <- err
+	}()
+	<-handling
+	enqueued := make(chan error)
+	go func() { enqueued <- o.Enqueue([]byte(`{"id":2}`)) }()
+	select {
+	case err := <-enqueued:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Enqueue not to wait for the handler")
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := o.Pending(); pending != 1 {
+		t.Errorf("expected the new event to still be pending, got %d", pending)
+	}
+}
+
+func TestNewOutboxError(t *testing.T) {
+	f, err := ioutil.TempFile("", "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	if _, err := NewOutbox(filepath.Join(f.Name(), "sub")); err == nil {
+		t.Error("expected an error for a dir that can't be created")
+	}
+}
+
+func TestOutboxPayloadTooLarge(t *testing.T) {
+	o, cleanup := tempOutbox(t)
+	defer cleanup()
+	defer func(max int64) { MaxPayloadBytes = max }(MaxPayloadBytes)
+	MaxPayloadBytes = 8
+
+	rec := httptest.NewRecorder()
+	o.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"id":"too long"}`)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", rec.Code)
+	}
+}
+
+func TestNewOutboxMigrates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"pending/00000000000000000001-000001.2":   `{"id":1}`,
+		"dead/00000000000000000002-000002.json":   `{"id":2}`,
+		"dead/00000000000000000002-000002.reason": "failed after 5 attempts: boom",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(p), 0700)
+		if err := ioutil.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	o, err := NewOutbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attempts := 0
+	if _, err := o.Process(func([]byte) error { attempts++; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected the pending event to be migrated, got %d", attempts)
+	}
+	dead, err := o.DeadLetters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || string(dead[0].Payload) != `{"id":2}` || dead[0].Reason != "failed after 5 attempts: boom" {
+		t.Errorf("expected the dead letter to be migrated, got %+v", dead)
+	}
+	for _, sub := range []string{"pending", "dead"} {
+		if _, err := os.Stat(filepath.Join(dir, sub)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", sub, err)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package workspace
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+// Conventions are house rules for projects, labels and categories. Lint
+// checks a workspace against them.
+type Conventions struct {
+	Projects   NamingRules `json:"projects" yaml:"projects"`
+	Labels     NamingRules `json:"labels" yaml:"labels"`
+	Categories NamingRules `json:"categories" yaml:"categories"`
+
+	// UniqueAbbreviations requires every unarchived project to have a
+	// different abbreviation, ignoring case.
+	UniqueAbbreviations bool `json:"unique_abbreviations" yaml:"unique_abbreviations"`
+}
+
+// NamingRules are the conventions for one kind of resource. Empty fields
+// aren't checked.
+type NamingRules struct {
+	// Pattern is a regular expression names must match.
+	Pattern string `json:"pattern" yaml:"pattern"`
+
+	// Case is "lower" or "upper". Names in the wrong case can be fixed.
+	Case string `json:"case" yaml:"case"`
+
+	// Palette lists the colors that may be used, e.g. "#ff0000".
+	// Resources with other colors are recolored to DefaultColor if it's
+	// set, or only reported otherwise.
+	Palette      []string `json:"palette" yaml:"palette"`
+	DefaultColor string   `json:"default_color" yaml:"default_color"`
+}
+
+// Violation is a resource that breaks a convention.
+type Violation struct {
+	Kind    string
+	Name    string
+	ID      int
+	Rule    string
+	Message string
+
+	// Fixable is true if the Plan returned by Lint fixes the violation.
+	Fixable bool
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s %q: %s: %s", v.Kind, v.Name, v.Rule, v.Message)
+}
+
+// Lint checks the unarchived projects, labels and categories in the
+// workspace against conv. It returns every violation, and a Plan that
+// fixes the ones that can be fixed by renaming or recoloring; print
+// the plan for a dry run, or apply it to fix them all.
+func Lint(c *clubhouse.Client, conv Conventions) ([]Violation, *Plan, error) {
+	l := linter{plan: &Plan{}}
+
+	projects, err := c.ListProjects()
+	if err != nil {
+		return nil, nil, err
+	}
+	abbreviations := map[string][]string{}
+	for _, p := range projects {
+		if p.Archived {
+			continue
+		}
+		id := p.ID
+		err := l.check("project", p.ID, p.Name, p.Color, conv.Projects,
+			func(c *clubhouse.Client, name, color *string) error {
+				_, err := c.UpdateProject(id, &clubhouse.UpdateProjectParams{Name: name, Color: color})
+				return err
+			})
+		if err != nil {
+			return nil, nil, err
+		}
+		if p.Abbreviation != "" {
+			key := strings.ToLower(p.Abbreviation)
+			abbreviations[key] = append(abbreviations[key], p.Name)
+		}
+	}
+	if conv.UniqueAbbreviations {
+		for _, p := range projects {
+			shared := abbreviations[strings.ToLower(p.Abbreviation)]
+			if p.Archived || len(shared) < 2 {
+				continue
+			}
+			l.violations = append(l.violations, Violation{
+				Kind:    "project",
+				Name:    p.Name,
+				ID:      p.ID,
+				Rule:    "unique_abbreviations",
+				Message: fmt.Sprintf("abbreviation %q is shared by %s", p.Abbreviation, strings.Join(shared, ", ")),
+			})
+		}
+	}
+
+	labels, err := c.ListLabels()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, label := range labels {
+		if label.Archived {
+			continue
+		}
+		id := label.ID
+		err := l.check("label", label.ID, label.Name, label.Color, conv.Labels,
+			func(c *clubhouse.Client, name, color *string) error {
+				_, err := c.UpdateLabel(id, &clubhouse.UpdateLabelParams{Name: name, Color: color})
+				return err
+			})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	categories, err := c.ListCategories()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, category := range categories {
+		if category.Archived {
+			continue
+		}
+		id := category.ID
+		err := l.check("category", category.ID, category.Name, category.Color, conv.Categories,
+			func(c *clubhouse.Client, name, color *string) error {
+				_, err := c.UpdateCategory(id, &clubhouse.UpdateCategoryParams{Name: name, Color: color})
+				return err
+			})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sort.SliceStable(l.violations, func(i, j int) bool {
+		return l.violations[i].Kind < l.violations[j].Kind
+	})
+	return l.violations, l.plan, nil
+}
+
+type linter struct {
+	violations []Violation
+	plan       *Plan
+}
+
+// check applies rules to one resource. update is called with the fixed
+// name and color, either of which may be nil if it doesn't need fixing.
+func (l *linter) check(
+	kind string,
+	id int,
+	name string,
+	color string,
+	rules NamingRules,
+	update func(c *clubhouse.Client, name, color *string) error,
+) error {
+	violation := func(rule, message string, fixable bool) {
+		l.violations = append(l.violations, Violation{
+			Kind: kind, Name: name, ID: id, Rule: rule, Message: message, Fixable: fixable,
+		})
+	}
+	fields := []FieldChange{}
+	var newName, newColor *string
+
+	if rules.Pattern != "" {
+		re, err := regexp.Compile(rules.Pattern)
+		if err != nil {
+			return fmt.Errorf("workspace: bad %s name pattern, %s", kind, err)
+		}
+		if !re.MatchString(name) {
+			violation("pattern", fmt.Sprintf("name doesn't match %s", rules.Pattern), false)
+		}
+	}
+
+	fixed := name
+	switch rules.Case {
+	case "":
+	case "lower":
+		fixed = strings.ToLower(name)
+	case "upper":
+		fixed = strings.ToUpper(name)
+	default:
+		return fmt.Errorf("workspace: unknown %s case %q", kind, rules.Case)
+	}
+	if fixed != name {
+		violation("case", fmt.Sprintf("name isn't %s case", rules.Case), true)
+		fields = drift(fields, "name", name, fixed)
+		newName = &fixed
+	}
+
+	if len(rules.Palette) > 0 && !inPalette(rules.Palette, color) {
+		fixable := rules.DefaultColor != ""
+		violation("palette", fmt.Sprintf("color %q isn't in the palette", color), fixable)
+		if fixable {
+			fields = drift(fields, "color", color, rules.DefaultColor)
+			newColor = &rules.DefaultColor
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	l.plan.Changes = append(l.plan.Changes, Change{
+		Action: ActionUpdate,
+		Kind:   kind,
+		Name:   name,
+		ID:     id,
+		Fields: fields,
+		apply: func(c *clubhouse.Client) error {
+			return update(c, newName, newColor)
+		},
+	})
+	return nil
+}
+
+func inPalette(palette []string, color string) bool {
+	for _, p := range palette {
+		if strings.EqualFold(p, color) {
+			return true
+		}
+	}
+	return false
+}
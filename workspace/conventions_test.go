@@ -0,0 +1,73 @@
+package workspace
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+func TestLint(t *testing.T) {
+	updates := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			body := map[string]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			updates[r.URL.Path] = body["name"] + " " + body["color"]
+			w.Write([]byte(`{}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/projects":
+			w.Write([]byte(`[
+				{"id":1,"name":"API","abbreviation":"ap","color":"#000000"},
+				{"id":2,"name":"App","abbreviation":"AP","color":"#ffffff"},
+				{"id":3,"name":"Old","abbreviation":"ap","archived":true}]`))
+		case "/v2/labels":
+			w.Write([]byte(`[{"id":10,"name":"Bug","color":"#FF0000"},{"id":11,"name":"feature","color":"#00ff00"}]`))
+		case "/v2/categories":
+			w.Write([]byte(`[{"id":20,"name":"q1","color":"#123456"}]`))
+		}
+	}))
+	defer srv.Close()
+	c := &clubhouse.Client{AuthToken: "token", RootURL: srv.URL, Limiter: clubhouse.RateLimiter(0)}
+
+	conv := Conventions{
+		Labels:              NamingRules{Case: "lower", Palette: []string{"#ff0000", "#0000ff"}, DefaultColor: "#0000ff"},
+		Categories:          NamingRules{Pattern: `^Q[1-4] `, Palette: []string{"#000000"}},
+		UniqueAbbreviations: true,
+	}
+	violations, plan, err := Lint(c, conv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, v := range violations {
+		got = append(got, v.Kind+" "+v.Name+" "+v.Rule)
+	}
+	want := []string{
+		"category q1 pattern",
+		"category q1 palette",
+		"label Bug case",
+		"label feature palette",
+		"project API unique_abbreviations",
+		"project App unique_abbreviations",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if len(plan.Changes) != 2 {
+		t.Fatalf("expected 2 fixes, got\n%s", plan)
+	}
+	if err := plan.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+	wantUpdates := map[string]string{"/v2/labels/10": "bug ", "/v2/labels/11": " #0000ff"}
+	if !reflect.DeepEqual(updates, wantUpdates) {
+		t.Errorf("expected %v, got %v", wantUpdates, updates)
+	}
+}
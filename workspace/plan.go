@@ -0,0 +1,530 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+// Action is what a Change does to a resource.
+type Action string
+
+// Valid values for Action
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionArchive Action = "archive"
+)
+
+// FieldChange describes a single drifted field.
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Change is a single step in a Plan.
+type Change struct {
+	Action Action
+	Kind   string
+	Name   string
+	ID     int // zero for groups, whose IDs are strings
+	Fields []FieldChange
+
+	apply func(c *clubhouse.Client) error
+}
+
+func (ch Change) String() string {
+	symbol := map[Action]string{
+		ActionCreate:  "+",
+		ActionUpdate:  "~",
+		ActionArchive: "-",
+	}[ch.Action]
+
+	lines := []string{fmt.Sprintf("%s %s %q", symbol, ch.Kind, ch.Name)}
+	for _, f := range ch.Fields {
+		lines = append(lines, fmt.Sprintf("    %s: %q -> %q", f.Field, f.From, f.To))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Plan is the set of changes needed to bring a workspace in line with a
+// Spec.
+type Plan struct {
+	Changes []Change
+}
+
+// Empty reports whether the workspace already matches the spec.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String renders the plan as a diff, suitable for a dry run.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "no changes\n"
+	}
+	buf := strings.Builder{}
+	for _, ch := range p.Changes {
+		buf.WriteString(ch.String())
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// Apply makes every change in the plan, stopping at the first error.
+func (p *Plan) Apply(c *clubhouse.Client) error {
+	for _, ch := range p.Changes {
+		if err := ch.apply(c); err != nil {
+			return fmt.Errorf("workspace: could not %s %s %q, %s",
+				ch.Action, ch.Kind, ch.Name, err)
+		}
+	}
+	return nil
+}
+
+// Diff compares spec with the current state of the workspace and
+// returns the plan to reconcile them. Nothing is changed until the plan
+// is applied.
+func Diff(c *clubhouse.Client, spec *Spec) (*Plan, error) {
+	plan := &Plan{}
+	steps := []func(*clubhouse.Client, *Spec, *Plan) error{
+		diffProjects,
+		diffLabels,
+		diffCategories,
+		diffMilestones,
+		diffGroups,
+	}
+	for _, step := range steps {
+		if err := step(c, spec, plan); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// drift appends a FieldChange if want is set and differs from have.
+func drift(fields []FieldChange, field, have, want string) []FieldChange {
+	if want == "" || want == have {
+		return fields
+	}
+	return append(fields, FieldChange{Field: field, From: have, To: want})
+}
+
+func diffProjects(c *clubhouse.Client, spec *Spec, plan *Plan) error {
+	existing, err := c.ListProjects()
+	if err != nil {
+		return err
+	}
+	seen := map[int]bool{}
+	for _, want := range spec.Projects {
+		want := want
+		var have *clubhouse.Project
+		for i, p := range existing {
+			if strings.EqualFold(p.Name, want.Name) {
+				have = &existing[i]
+				break
+			}
+		}
+		if have == nil {
+			plan.Changes = append(plan.Changes, Change{
+				Action: ActionCreate,
+				Kind:   "project",
+				Name:   want.Name,
+				apply: func(c *clubhouse.Client) error {
+					_, err := c.CreateProject(&clubhouse.CreateProjectParams{
+						Name:         want.Name,
+						Abbreviation: want.Abbreviation,
+						Color:        want.Color,
+						Description:  want.Description,
+						TeamID:       want.TeamID,
+					})
+					return err
+				},
+			})
+			continue
+		}
+		seen[have.ID] = true
+
+		fields := []FieldChange{}
+		fields = drift(fields, "abbreviation", have.Abbreviation, want.Abbreviation)
+		fields = drift(fields, "color", have.Color, want.Color)
+		fields = drift(fields, "description", have.Description, want.Description)
+		if want.TeamID != 0 && want.TeamID != have.TeamID {
+			fields = append(fields, FieldChange{
+				Field: "team_id",
+				From:  fmt.Sprint(have.TeamID),
+				To:    fmt.Sprint(want.TeamID),
+			})
+		}
+		if have.Archived {
+			fields = append(fields, FieldChange{Field: "archived", From: "true", To: "false"})
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		id := have.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionUpdate,
+			Kind:   "project",
+			Name:   want.Name,
+			ID:     id,
+			Fields: fields,
+			apply: func(c *clubhouse.Client) error {
+				params := clubhouse.UpdateProjectParams{Archived: clubhouse.Unarchived}
+				if want.Abbreviation != "" {
+					params.Abbreviation = clubhouse.String(want.Abbreviation)
+				}
+				if want.Color != "" {
+					params.Color = clubhouse.String(want.Color)
+				}
+				if want.Description != "" {
+					params.Description = clubhouse.String(want.Description)
+				}
+				if want.TeamID != 0 {
+					params.TeamID = clubhouse.ID(want.TeamID)
+				}
+				_, err := c.UpdateProject(id, &params)
+				return err
+			},
+		})
+	}
+
+	if !spec.ArchiveExtras {
+		return nil
+	}
+	for _, p := range existing {
+		if seen[p.ID] || p.Archived {
+			continue
+		}
+		id := p.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionArchive,
+			Kind:   "project",
+			Name:   p.Name,
+			ID:     id,
+			apply: func(c *clubhouse.Client) error {
+				_, err := c.UpdateProject(id, &clubhouse.UpdateProjectParams{
+					Archived: clubhouse.Archived,
+				})
+				return err
+			},
+		})
+	}
+	return nil
+}
+
+func diffLabels(c *clubhouse.Client, spec *Spec, plan *Plan) error {
+	existing, err := c.ListLabels()
+	if err != nil {
+		return err
+	}
+	seen := map[int]bool{}
+	for _, want := range spec.Labels {
+		want := want
+		var have *clubhouse.Label
+		for i, l := range existing {
+			if strings.EqualFold(l.Name, want.Name) {
+				have = &existing[i]
+				break
+			}
+		}
+		if have == nil {
+			plan.Changes = append(plan.Changes, Change{
+				Action: ActionCreate,
+				Kind:   "label",
+				Name:   want.Name,
+				apply: func(c *clubhouse.Client) error {
+					_, err := c.CreateLabel(&clubhouse.CreateLabelParams{
+						Name:  want.Name,
+						Color: want.Color,
+					})
+					return err
+				},
+			})
+			continue
+		}
+		seen[have.ID] = true
+
+		fields := drift(nil, "color", have.Color, want.Color)
+		if have.Archived {
+			fields = append(fields, FieldChange{Field: "archived", From: "true", To: "false"})
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		id := have.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionUpdate,
+			Kind:   "label",
+			Name:   want.Name,
+			ID:     id,
+			Fields: fields,
+			apply: func(c *clubhouse.Client) error {
+				params := clubhouse.UpdateLabelParams{Archived: clubhouse.Unarchived}
+				if want.Color != "" {
+					params.Color = clubhouse.String(want.Color)
+				}
+				_, err := c.UpdateLabel(id, &params)
+				return err
+			},
+		})
+	}
+
+	if !spec.ArchiveExtras {
+		return nil
+	}
+	for _, l := range existing {
+		if seen[l.ID] || l.Archived {
+			continue
+		}
+		id := l.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionArchive,
+			Kind:   "label",
+			Name:   l.Name,
+			ID:     id,
+			apply: func(c *clubhouse.Client) error {
+				_, err := c.UpdateLabel(id, &clubhouse.UpdateLabelParams{
+					Archived: clubhouse.Archived,
+				})
+				return err
+			},
+		})
+	}
+	return nil
+}
+
+func diffCategories(c *clubhouse.Client, spec *Spec, plan *Plan) error {
+	existing, err := c.ListCategories()
+	if err != nil {
+		return err
+	}
+	seen := map[int]bool{}
+	for _, want := range spec.Categories {
+		want := want
+		var have *clubhouse.Category
+		for i, cat := range existing {
+			if strings.EqualFold(cat.Name, want.Name) {
+				have = &existing[i]
+				break
+			}
+		}
+		if have == nil {
+			plan.Changes = append(plan.Changes, Change{
+				Action: ActionCreate,
+				Kind:   "category",
+				Name:   want.Name,
+				apply: func(c *clubhouse.Client) error {
+					_, err := c.CreateCategory(&clubhouse.CreateCategoryParams{
+						Name:  want.Name,
+						Color: want.Color,
+					})
+					return err
+				},
+			})
+			continue
+		}
+		seen[have.ID] = true
+
+		fields := drift(nil, "color", have.Color, want.Color)
+		if have.Archived {
+			fields = append(fields, FieldChange{Field: "archived", From: "true", To: "false"})
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		id := have.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionUpdate,
+			Kind:   "category",
+			Name:   want.Name,
+			ID:     id,
+			Fields: fields,
+			apply: func(c *clubhouse.Client) error {
+				params := clubhouse.UpdateCategoryParams{Archived: clubhouse.Unarchived}
+				if want.Color != "" {
+					params.Color = clubhouse.String(want.Color)
+				}
+				_, err := c.UpdateCategory(id, &params)
+				return err
+			},
+		})
+	}
+
+	if !spec.ArchiveExtras {
+		return nil
+	}
+	for _, cat := range existing {
+		if seen[cat.ID] || cat.Archived {
+			continue
+		}
+		id := cat.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionArchive,
+			Kind:   "category",
+			Name:   cat.Name,
+			ID:     id,
+			apply: func(c *clubhouse.Client) error {
+				_, err := c.UpdateCategory(id, &clubhouse.UpdateCategoryParams{
+					Archived: clubhouse.Archived,
+				})
+				return err
+			},
+		})
+	}
+	return nil
+}
+
+// diffMilestones never archives extras, since milestones can't be
+// archived.
+func diffMilestones(c *clubhouse.Client, spec *Spec, plan *Plan) error {
+	existing, err := c.ListMilestones()
+	if err != nil {
+		return err
+	}
+	for _, want := range spec.Milestones {
+		want := want
+		var have *clubhouse.Milestone
+		for i, m := range existing {
+			if strings.EqualFold(m.Name, want.Name) {
+				have = &existing[i]
+				break
+			}
+		}
+		if have == nil {
+			plan.Changes = append(plan.Changes, Change{
+				Action: ActionCreate,
+				Kind:   "milestone",
+				Name:   want.Name,
+				apply: func(c *clubhouse.Client) error {
+					_, err := c.CreateMilestone(&clubhouse.CreateMilestoneParams{
+						Name:        want.Name,
+						Description: want.Description,
+						State:       want.State,
+					})
+					return err
+				},
+			})
+			continue
+		}
+
+		fields := []FieldChange{}
+		fields = drift(fields, "description", have.Description, want.Description)
+		fields = drift(fields, "state", string(have.State), string(want.State))
+		if len(fields) == 0 {
+			continue
+		}
+		id := have.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionUpdate,
+			Kind:   "milestone",
+			Name:   want.Name,
+			ID:     id,
+			Fields: fields,
+			apply: func(c *clubhouse.Client) error {
+				params := clubhouse.UpdateMilestoneParams{State: want.State}
+				if want.Description != "" {
+					params.Description = clubhouse.String(want.Description)
+				}
+				_, err := c.UpdateMilestone(id, &params)
+				return err
+			},
+		})
+	}
+	return nil
+}
+
+// diffGroups only runs when the spec lists groups, so specs for
+// workspaces on version 2 of the API, which has no groups, still work.
+func diffGroups(c *clubhouse.Client, spec *Spec, plan *Plan) error {
+	if len(spec.Groups) == 0 {
+		return nil
+	}
+	existing, err := c.ListGroups()
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, want := range spec.Groups {
+		want := want
+		var have *clubhouse.Group
+		for i, g := range existing {
+			if strings.EqualFold(g.Name, want.Name) {
+				have = &existing[i]
+				break
+			}
+		}
+		if have == nil {
+			plan.Changes = append(plan.Changes, Change{
+				Action: ActionCreate,
+				Kind:   "group",
+				Name:   want.Name,
+				apply: func(c *clubhouse.Client) error {
+					_, err := c.CreateGroup(&clubhouse.CreateGroupParams{
+						Name:        want.Name,
+						MentionName: want.MentionName,
+						Color:       want.Color,
+						Description: want.Description,
+					})
+					return err
+				},
+			})
+			continue
+		}
+		seen[have.ID] = true
+
+		fields := []FieldChange{}
+		fields = drift(fields, "mention_name", have.MentionName, want.MentionName)
+		fields = drift(fields, "color", have.Color, want.Color)
+		fields = drift(fields, "description", have.Description, want.Description)
+		if have.Archived {
+			fields = append(fields, FieldChange{Field: "archived", From: "true", To: "false"})
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		id := have.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionUpdate,
+			Kind:   "group",
+			Name:   want.Name,
+			Fields: fields,
+			apply: func(c *clubhouse.Client) error {
+				params := clubhouse.UpdateGroupParams{
+					Archived:    clubhouse.Unarchived,
+					MentionName: want.MentionName,
+					Color:       want.Color,
+				}
+				if want.Description != "" {
+					params.Description = clubhouse.String(want.Description)
+				}
+				_, err := c.UpdateGroup(id, &params)
+				return err
+			},
+		})
+	}
+
+	if !spec.ArchiveExtras {
+		return nil
+	}
+	for _, g := range existing {
+		if seen[g.ID] || g.Archived {
+			continue
+		}
+		id := g.ID
+		plan.Changes = append(plan.Changes, Change{
+			Action: ActionArchive,
+			Kind:   "group",
+			Name:   g.Name,
+			apply: func(c *clubhouse.Client) error {
+				_, err := c.UpdateGroup(id, &clubhouse.UpdateGroupParams{
+					Archived: clubhouse.Archived,
+				})
+				return err
+			},
+		})
+	}
+	return nil
+}
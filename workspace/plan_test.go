@@ -0,0 +1,102 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/brianloveswords/clubhouse"
+)
+
+func TestPlanString(t *testing.T) {
+	plan := &Plan{Changes: []Change{
+		{Action: ActionCreate, Kind: "label", Name: "bug"},
+		{Action: ActionUpdate, Kind: "project", Name: "api", Fields: drift(nil, "color", "red", "blue")},
+		{Action: ActionArchive, Kind: "category", Name: "old"},
+	}}
+	expect := `+ label "bug"
+~ project "api"
+    color: "red" -> "blue"
+- category "old"
+`
+	if plan.String() != expect {
+		t.Errorf("%s != %s", plan.String(), expect)
+	}
+	if (&Plan{}).String() != "no changes\n" {
+		t.Error("expected empty plan to say so")
+	}
+}
+
+func TestDrift(t *testing.T) {
+	if fields := drift(nil, "color", "red", ""); len(fields) != 0 {
+		t.Error("unset fields shouldn't drift")
+	}
+	if fields := drift(nil, "color", "red", "red"); len(fields) != 0 {
+		t.Error("equal fields shouldn't drift")
+	}
+}
+
+func TestDiffApply(t *testing.T) {
+	requests := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		body, _ := ioutil.ReadAll(r.Body)
+		switch key {
+		case "GET /v3/projects":
+			w.Write([]byte(`[{"id":1,"name":"API","color":"red"}]`))
+		case "GET /v3/labels":
+			w.Write([]byte(`[{"id":2,"name":"bug","color":"#f00"}]`))
+		case "GET /v3/categories":
+			w.Write([]byte(`[{"id":3,"name":"old"}]`))
+		case "GET /v3/milestones":
+			w.Write([]byte(`[]`))
+		case "GET /v3/groups":
+			w.Write([]byte(`[{"id":"g1","name":"Platform","mention_name":"platform"},{"id":"g2","name":"Legacy"}]`))
+		default:
+			requests = append(requests, key+" "+string(body))
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+	c := &clubhouse.Client{AuthToken: "token", RootURL: srv.URL, Version: "v3", Limiter: clubhouse.RateLimiter(0)}
+
+	spec := &Spec{
+		Projects:      []Project{{Name: "api", Color: "blue"}},
+		Labels:        []Label{{Name: "Bug"}, {Name: "chore", Color: "#0f0"}},
+		Groups:        []Group{{Name: "Platform", MentionName: "platform"}, {Name: "Mobile", MentionName: "mobile"}},
+		ArchiveExtras: true,
+	}
+	plan, err := Diff(c, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `~ project "api"
+    color: "red" -> "blue"
++ label "chore"
+- category "old"
++ group "Mobile"
+- group "Legacy"
+`
+	if plan.String() != expect {
+		t.Errorf("%s != %s", plan.String(), expect)
+	}
+	if len(requests) != 0 {
+		t.Fatalf("expected Diff not to change anything, got %v", requests)
+	}
+
+	if err := plan.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+	expectRequests := []string{
+		`PUT /v3/projects/1 {"archived":false,"color":"blue"}`,
+		`POST /v3/labels {"color":"#0f0","name":"chore"}`,
+		`PUT /v3/categories/3 {"archived":true}`,
+		`POST /v3/groups {"mention_name":"mobile","name":"Mobile"}`,
+		`PUT /v3/groups/g2 {"archived":true}`,
+	}
+	if !reflect.DeepEqual(requests, expectRequests) {
+		t.Errorf("expected\n%v\ngot\n%v", expectRequests, requests)
+	}
+}
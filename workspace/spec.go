@@ -0,0 +1,103 @@
+// Package workspace reconciles a Clubhouse workspace toward a
+// declarative spec, a bit like a very small terraform.
+//
+// A Spec lists the projects, labels, categories, milestones and groups
+// that should exist. Diff compares it with what's in the workspace and
+// returns a Plan, which can be printed for a dry run and then applied:
+//
+//	spec, err := workspace.Load("workspace.yaml")
+//	plan, err := workspace.Diff(client, spec)
+//	fmt.Print(plan)
+//	err = plan.Apply(client)
+//
+// Fields left empty in the spec are not managed, so they are never
+// reported as drift.
+//
+// Lint checks the workspace against naming and color Conventions
+// instead, and returns a Plan that fixes what it can.
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/brianloveswords/clubhouse"
+	"gopkg.in/yaml.v2"
+)
+
+// Spec is the desired state of a workspace.
+type Spec struct {
+	Projects   []Project   `json:"projects" yaml:"projects"`
+	Labels     []Label     `json:"labels" yaml:"labels"`
+	Categories []Category  `json:"categories" yaml:"categories"`
+	Milestones []Milestone `json:"milestones" yaml:"milestones"`
+
+	// Groups are only in version 3 of the API, so they're only looked
+	// at if the spec lists some, and need a client with Version "v3".
+	Groups []Group `json:"groups" yaml:"groups"`
+
+	// ArchiveExtras archives projects, labels, categories and (if the
+	// spec lists any) groups that exist in the workspace but aren't in
+	// the spec.
+	ArchiveExtras bool `json:"archive_extras" yaml:"archive_extras"`
+
+	// Conventions are checked by Lint rather than Diff.
+	Conventions Conventions `json:"conventions" yaml:"conventions"`
+}
+
+// Project is the desired state of a project.
+type Project struct {
+	Name         string `json:"name" yaml:"name"`
+	Abbreviation string `json:"abbreviation" yaml:"abbreviation"`
+	Color        string `json:"color" yaml:"color"`
+	Description  string `json:"description" yaml:"description"`
+	TeamID       int    `json:"team_id" yaml:"team_id"`
+}
+
+// Label is the desired state of a label.
+type Label struct {
+	Name  string `json:"name" yaml:"name"`
+	Color string `json:"color" yaml:"color"`
+}
+
+// Category is the desired state of a category.
+type Category struct {
+	Name  string `json:"name" yaml:"name"`
+	Color string `json:"color" yaml:"color"`
+}
+
+// Milestone is the desired state of a milestone.
+type Milestone struct {
+	Name        string          `json:"name" yaml:"name"`
+	Description string          `json:"description" yaml:"description"`
+	State       clubhouse.State `json:"state" yaml:"state"`
+}
+
+// Group is the desired state of a group. Groups are matched by Name.
+type Group struct {
+	Name        string `json:"name" yaml:"name"`
+	MentionName string `json:"mention_name" yaml:"mention_name"`
+	Color       string `json:"color" yaml:"color"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// Load reads a Spec from a YAML or JSON file. Files ending in .json are
+// read as JSON, everything else as YAML.
+func Load(path string) (*Spec, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("workspace: could not read spec, %s", err)
+	}
+	spec := Spec{}
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(content, &spec)
+	} else {
+		err = yaml.Unmarshal(content, &spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("workspace: could not decode %s, %s", path, err)
+	}
+	return &spec, nil
+}